@@ -9,11 +9,12 @@ import (
 	"io"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/internal/output"
 	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/internal/policy"
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/internal/source"
 	"github.com/pranshuparmar/witr/internal/target"
@@ -38,8 +39,9 @@ var rootCmd = &cobra.Command{
 		DisableDefaultCmd: false,
 		DisableNoDescFlag: false,
 	},
-	Example: _genExamples(),
-	RunE:    runApp,
+	Example:           _genExamples(),
+	PersistentPreRunE: preRun,
+	RunE:              runApp,
 }
 
 func _genExamples() string {
@@ -54,12 +56,32 @@ func _genExamples() string {
   # Find the process listening on a specific port
   witr --port 5432
 
+  # Narrow a port by protocol or address, or use a range or service name
+  witr --port udp:53
+  witr --port 127.0.0.1:8080
+  witr --port 8000-8100
+
   # Find the process holding a file open
   witr --file /var/lib/dpkg/lock
 
+  # Find who holds a unix socket and who is connected to it
+  witr --socket /run/docker.sock
+
+  # Every local process connected to a host, port or network
+  witr --remote 10.0.0.5:5432
+  witr --remote 192.168.0.0/16
+
   # Inspect a container by name
   witr --container redis
 
+  # Every process of a systemd unit (or of each unit a glob matches)
+  witr --unit nginx
+  witr --unit 'php*-fpm'
+
+  # Everything a user, login session or terminal is running, as one forest
+  witr --user alice
+  witr --tty pts/3
+
   # Inspect a process by name with exact matching (no fuzzy search)
   witr bun --exact
 
@@ -94,6 +116,26 @@ func _genExamples() string {
 
   # Mixed inputs
   witr nginx --pid 1234 --port 8080
+
+  # Keep watching a port and print only what changes (new PID, restarts, warnings)
+  witr --port 8080 --watch
+  witr --port 8080 --watch=10s
+
+  # Capture the host now, explain it later (or on another machine)
+  witr snapshot save incident.witr
+  witr --from-snapshot incident.witr --port 8080
+
+  # What started or stopped between two snapshots (e.g. across a deploy)
+  witr diff before.witr after.witr
+
+  # Every listener, container and service on the host, and why it exists
+  witr audit
+
+  # Serve the analyses as a JSON API on a local unix socket
+  witr serve --listen unix:///run/witr.sock
+
+  # From a debugging sidecar, inspect the host through its bind-mounted procfs
+  witr --proc-root /host/proc --port 8080
 `
 }
 
@@ -141,20 +183,36 @@ func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("witr {{.Version}} (commit %s, built %s)\n", commit, buildDate))
 	rootCmd.SetErr(output.NewSafeTerminalWriter(os.Stderr))
 
-	rootCmd.Flags().StringSliceP("pid", "p", nil, "pid(s) to look up (repeatable)")
-	rootCmd.Flags().StringSliceP("port", "o", nil, "port(s) to look up (repeatable)")
-	rootCmd.Flags().StringSliceP("file", "f", nil, "file(s) held open by a process (repeatable)")
-	rootCmd.Flags().StringSliceP("container", "c", nil, "container(s) to look up (repeatable)")
-	rootCmd.Flags().BoolP("short", "s", false, "show only ancestry")
-	rootCmd.Flags().BoolP("tree", "t", false, "show only ancestry as a tree")
-	rootCmd.Flags().Bool("json", false, "show result as JSON")
-	rootCmd.Flags().Bool("warnings", false, "show only warnings")
-	rootCmd.Flags().Bool("no-color", false, "disable colorized output")
-	rootCmd.Flags().Bool("env", false, "show environment variables for the process")
-	rootCmd.Flags().Bool("verbose", false, "show extended process information")
-	rootCmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
-	rootCmd.Flags().BoolP("interactive", "i", false, "interactive mode (TUI)")
+	defineRootFlags(rootCmd)
+}
 
+// defineRootFlags adds the root command's flags to cmd.
+func defineRootFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("pid", "p", nil, "pid(s) to look up (repeatable)")
+	cmd.Flags().StringSliceP("port", "o", nil, "port(s) to look up: 8080, 8000-8100, https, udp:53, 127.0.0.1:8080 (repeatable)")
+	cmd.Flags().StringSliceP("file", "f", nil, "file(s) held open by a process (repeatable)")
+	cmd.Flags().StringSliceP("container", "c", nil, "container(s) to look up (repeatable)")
+	cmd.Flags().StringSlice("unit", nil, "systemd unit(s) to look up with all their processes, globs allowed (repeatable)")
+	cmd.Flags().StringSlice("user", nil, "every process of a user, by name or uid (repeatable)")
+	cmd.Flags().StringSlice("session", nil, "every process of a login session (repeatable)")
+	cmd.Flags().StringSlice("tty", nil, "every process on a terminal, e.g. pts/3 (repeatable)")
+	cmd.Flags().StringSlice("socket", nil, "unix socket(s) to look up, by path or @abstract name (repeatable)")
+	cmd.Flags().StringSlice("remote", nil, "local processes connected to a remote host[:port] or CIDR (repeatable)")
+	cmd.Flags().BoolP("short", "s", false, "show only ancestry")
+	cmd.Flags().BoolP("tree", "t", false, "show only ancestry as a tree")
+	cmd.Flags().Bool("json", false, "show result as JSON")
+	cmd.Flags().Bool("warnings", false, "show only warnings")
+	cmd.Flags().String("format", "", "print warnings as a sarif or junit report (implies --warnings)")
+	cmd.Flags().Bool("no-color", false, "disable colorized output")
+	cmd.Flags().Bool("env", false, "show environment variables for the process")
+	cmd.Flags().Bool("verbose", false, "show extended process information")
+	cmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
+	cmd.Flags().BoolP("interactive", "i", false, "interactive mode (TUI)")
+	cmd.Flags().Duration("watch", 0, "re-run the analysis every interval and print only what changed")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+	cmd.PersistentFlags().String("proc-root", "", "procfs directory to read instead of /proc (Linux; or set "+procpkg.ProcRootEnv+")")
+	cmd.PersistentFlags().String("policy", "", "warning policy file to use instead of /etc/witr/policy.yaml and the user's witr/policy.yaml")
+	cmd.Flags().String("from-snapshot", "", "analyze a snapshot saved with 'witr snapshot save' instead of the live system")
 }
 
 // appFlags holds all parsed CLI flags for convenience.
@@ -167,9 +225,21 @@ type appFlags struct {
 	verbose bool
 	exact   bool
 	env     bool
+	watch   time.Duration
+	format  string
 }
 
 func runApp(cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("from-snapshot"); path != "" {
+		if cmd.Flags().Changed("watch") {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("invalid flags: --watch cannot be combined with --from-snapshot"))
+		}
+		if _, err := activateSnapshot(cmd, path); err != nil {
+			return err
+		}
+		defer deactivateSnapshot()
+	}
+
 	interactiveFlag, _ := cmd.Flags().GetBool("interactive")
 	if interactiveFlag {
 		return runInteractive()
@@ -180,8 +250,16 @@ func runApp(cmd *cobra.Command, args []string) error {
 	portFlags, _ := cmd.Flags().GetStringSlice("port")
 	fileFlags, _ := cmd.Flags().GetStringSlice("file")
 	containerFlags, _ := cmd.Flags().GetStringSlice("container")
-
-	if !envFlag && len(pidFlags) == 0 && len(portFlags) == 0 && len(fileFlags) == 0 && len(containerFlags) == 0 && len(args) == 0 {
+	unitFlags, _ := cmd.Flags().GetStringSlice("unit")
+	userFlags, _ := cmd.Flags().GetStringSlice("user")
+	sessionFlags, _ := cmd.Flags().GetStringSlice("session")
+	ttyFlags, _ := cmd.Flags().GetStringSlice("tty")
+	socketFlags, _ := cmd.Flags().GetStringSlice("socket")
+	remoteFlags, _ := cmd.Flags().GetStringSlice("remote")
+
+	if !envFlag && len(pidFlags) == 0 && len(portFlags) == 0 && len(fileFlags) == 0 && len(containerFlags) == 0 && len(unitFlags) == 0 &&
+		len(userFlags) == 0 && len(sessionFlags) == 0 && len(ttyFlags) == 0 && len(socketFlags) == 0 && len(remoteFlags) == 0 &&
+		len(args) == 0 {
 		return runInteractive()
 	}
 
@@ -195,16 +273,32 @@ func runApp(cmd *cobra.Command, args []string) error {
 		noColor: boolFlag(cmd, "no-color"),
 		verbose: boolFlag(cmd, "verbose"),
 	}
+	flags.format, _ = cmd.Flags().GetString("format")
 
 	// Collect all targets preserving command-line order
 	targets := collectTargetsInOrder(os.Args[1:], args, flagTakesValue(cmd))
 
 	if len(targets) == 0 {
-		return withExitCode(ExitInvalidInput, fmt.Errorf("must specify --pid, --port, --file, --container, or a process name"))
+		return withExitCode(ExitInvalidInput, fmt.Errorf("must specify --pid, --port, --file, --socket, --remote, --container, --unit, --user, --session, --tty, or a process name"))
+	}
+
+	if flags.format != "" {
+		return runReport(cmd, targets, flags)
+	}
+
+	if cmd.Flags().Changed("watch") {
+		flags.watch, _ = cmd.Flags().GetDuration("watch")
+		return runWatch(cmd, targets, flags)
 	}
 
 	outw := cmd.OutOrStdout()
 	outp := output.NewPrinter(outw)
+	// A port range explains each port listening in it, like separate targets.
+	var expanded []model.Target
+	for _, t := range targets {
+		expanded = append(expanded, target.ExpandPortTarget(t)...)
+	}
+	targets = expanded
 	multiMode := len(targets) > 1
 	colorEnabled := useColor(flags, outw)
 
@@ -245,6 +339,67 @@ func runApp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func preRun(cmd *cobra.Command, args []string) error {
+	if err := applyProcRoot(cmd, args); err != nil {
+		return err
+	}
+	if err := loadSourcesConfig(); err != nil {
+		return err
+	}
+	return loadPolicy(cmd)
+}
+
+// loadSourcesConfig installs the custom source detectors declared in
+// /etc/witr/sources.yaml and the user's witr/sources.yaml.
+func loadSourcesConfig() error {
+	if err := source.LoadSourcesConfig(source.DefaultSourcesPaths()...); err != nil {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid sources config: %w", err))
+	}
+	return nil
+}
+
+// loadPolicy installs the warning policy from --policy, or from the default
+// policy files when the flag isn't given.
+func loadPolicy(cmd *cobra.Command) error {
+	paths := policy.DefaultPaths()
+	if file, _ := cmd.Flags().GetString("policy"); file != "" {
+		if _, err := os.Stat(file); err != nil {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --policy: %w", err))
+		}
+		paths = []string{file}
+	}
+	if err := policy.Load(paths...); err != nil {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid policy: %w", err))
+	}
+	return nil
+}
+
+// applyProcRoot points the Linux readers at --proc-root, or WITR_PROC_ROOT when
+// the flag isn't given, so witr can inspect a host from a sidecar container
+// that has the host's procfs bind-mounted (e.g. at /host/proc).
+func applyProcRoot(cmd *cobra.Command, _ []string) error {
+	root, _ := cmd.Flags().GetString("proc-root")
+	if root == "" {
+		// The environment is typically set image-wide; ignore it where there's
+		// no procfs to redirect rather than failing every command.
+		if runtime.GOOS != "linux" {
+			return nil
+		}
+		root = os.Getenv(procpkg.ProcRootEnv)
+	}
+	if root == "" {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("--proc-root is only supported on Linux"))
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --proc-root %q: not a directory", root))
+	}
+	procpkg.SetProcRoot(root)
+	return nil
+}
+
 func boolFlag(cmd *cobra.Command, name string) bool {
 	v, _ := cmd.Flags().GetBool(name)
 	return v
@@ -286,6 +441,8 @@ func collectTargetsInOrder(rawArgs []string, positionalArgs []string, takesValue
 		"-o": model.TargetPort, "--port": model.TargetPort,
 		"-f": model.TargetFile, "--file": model.TargetFile,
 		"-c": model.TargetContainer, "--container": model.TargetContainer,
+		"--socket": model.TargetSocket, "--remote": model.TargetRemote, "--unit": model.TargetUnit,
+		"--user": model.TargetUser, "--session": model.TargetSession, "--tty": model.TargetTTY,
 	}
 
 	// Track which positional args we've placed so we can insert them in order
@@ -370,8 +527,20 @@ func targetLabel(t model.Target) string {
 		return fmt.Sprintf("port: %s", t.Value)
 	case model.TargetFile:
 		return fmt.Sprintf("file: %s", t.Value)
+	case model.TargetSocket:
+		return fmt.Sprintf("socket: %s", t.Value)
+	case model.TargetRemote:
+		return fmt.Sprintf("remote: %s", t.Value)
 	case model.TargetContainer:
 		return fmt.Sprintf("container: %s", t.Value)
+	case model.TargetUnit:
+		return fmt.Sprintf("unit: %s", t.Value)
+	case model.TargetUser:
+		return fmt.Sprintf("user: %s", t.Value)
+	case model.TargetSession:
+		return fmt.Sprintf("session: %s", t.Value)
+	case model.TargetTTY:
+		return fmt.Sprintf("tty: %s", t.Value)
 	default:
 		return fmt.Sprintf("name: %s", t.Value)
 	}
@@ -411,8 +580,31 @@ func processTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t mo
 	if t.Type == model.TargetContainer {
 		return processContainerTarget(cmd, outw, outp, t, flags, multiMode, jsonResults)
 	}
+	if t.Type == model.TargetUnit {
+		return processUnitTarget(cmd, outw, outp, t, flags, multiMode, jsonResults)
+	}
+	if t.Type == model.TargetUser || t.Type == model.TargetSession || t.Type == model.TargetTTY {
+		return processLoginTarget(cmd, outw, outp, t, flags, multiMode, jsonResults)
+	}
+	if t.Type == model.TargetRemote {
+		return processRemoteTarget(cmd, outw, outp, t, flags, multiMode, jsonResults)
+	}
 
-	pids, err := target.Resolve(t, flags.exact)
+	// A unix socket's description names its owners, so it's resolved once
+	// and kept for the result rather than scanning every process's fds again.
+	var (
+		pids       []int
+		unixSocket *model.UnixSocketInfo
+		err        error
+	)
+	if path := strings.TrimSpace(t.Value); t.Type == model.TargetSocket && path != "" {
+		unixSocket, err = target.DescribeUnixSocket(path)
+		if err == nil {
+			pids = unixSocket.Owners
+		}
+	} else {
+		pids, err = target.Resolve(t, flags.exact)
+	}
 	if err == nil && len(pids) == 0 {
 		err = fmt.Errorf("no matching process found")
 	}
@@ -435,19 +627,11 @@ func processTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t mo
 
 	pid := pids[0]
 
-	var systemdService string
-	if t.Type == model.TargetPort && pid == 1 && source.IsSystemdRunning() {
-		if portNum, err := strconv.Atoi(t.Value); err == nil {
-			if svc, err := procpkg.ResolveSystemdService(portNum); err == nil && svc != "" {
-				systemdService = svc
-			}
-		}
-	}
-
 	res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
 		PID:     pid,
 		Verbose: flags.verbose,
 		Tree:    flags.tree,
+		Peers:   flags.verbose || flags.json,
 		Target:  t,
 	})
 
@@ -466,18 +650,10 @@ func processTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t mo
 		return classifyError(err)
 	}
 
-	if systemdService != "" {
-		res.ResolvedTarget = strings.TrimSuffix(systemdService, ".service")
-	}
-
 	if t.Type == model.TargetPort {
-		portNum := 0
-		fmt.Sscanf(t.Value, "%d", &portNum)
-		if portNum > 0 {
-			res.SocketInfo = procpkg.GetSocketStateForPort(portNum)
-			source.EnrichSocketInfo(res.SocketInfo)
-		}
+		target.DescribePort(&res, t.Value)
 	}
+	res.UnixSocket = unixSocket
 
 	renderResult(outw, res, flags, multiMode, jsonResults)
 
@@ -569,7 +745,7 @@ func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer,
 
 	if errors.Is(err, target.ErrSocketOwnerUnknown) || strings.Contains(errStr, "socket found but owning process not detected") {
 		if t.Type == model.TargetPort {
-			if portNum, convErr := strconv.Atoi(t.Value); convErr == nil {
+			if portNum := target.PortNumber(t.Value); portNum > 0 {
 				if match := procpkg.ResolveContainerByPort(portNum); match != nil {
 					label := "port " + t.Value
 					if flags.json {
@@ -600,7 +776,11 @@ func handleResolveError(cmd *cobra.Command, outw io.Writer, outp output.Printer,
 			}
 			return ExitPermission
 		}
-		errorMsg := fmt.Sprintf("%s\n\nA socket was found for the port, but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, strings.Join(os.Args, " "))
+		what := "port"
+		if t.Type == model.TargetSocket {
+			what = "path"
+		}
+		errorMsg := fmt.Sprintf("%s\n\nA socket was found for the %s, but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, what, strings.Join(os.Args, " "))
 		cmd.PrintErrln(errorMsg)
 		return ExitPermission
 	}
@@ -726,6 +906,9 @@ func printContainerMultiMatch(outp output.Printer, matches []*model.ContainerMat
 func classifyError(err error) int {
 	msg := strings.ToLower(err.Error())
 	switch {
+	case errors.Is(err, procpkg.ErrReplayUnavailable):
+		// A live-only lookup asked of a snapshot.
+		return ExitInvalidInput
 	case strings.Contains(msg, "permission denied") ||
 		strings.Contains(msg, "operation not permitted") ||
 		strings.Contains(msg, "insufficient permissions"):
@@ -772,6 +955,7 @@ func processContainerTarget(cmd *cobra.Command, outw io.Writer, outp output.Prin
 		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
 			PID:     pid,
 			Verbose: flags.verbose,
+			Peers:   flags.verbose || flags.json,
 			Tree:    flags.tree,
 			Target:  t,
 		})
@@ -2,7 +2,10 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 )
 
 // The mappings here drive script and CI integrations, so any
@@ -46,6 +49,15 @@ func TestClassifyError(t *testing.T) {
 	}
 }
 
+// A lookup --from-snapshot can't answer is the user's flag combination,
+// however the error is worded.
+func TestClassifyReplayUnavailable(t *testing.T) {
+	err := fmt.Errorf("unix sockets: %w", procpkg.ErrReplayUnavailable)
+	if got := classifyError(err); got != ExitInvalidInput {
+		t.Errorf("classifyError(%q) = %d, want %d", err, got, ExitInvalidInput)
+	}
+}
+
 // An internal error must be distinguishable from "process has warnings" so
 // scripts gating on exit 1 don't conflate the two.
 func TestExitCodesDistinct(t *testing.T) {
@@ -0,0 +1,83 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inventory every listener, container and service on the host, and why it exists",
+	Long: "Explain every listening socket, container and service-managed daemon on the\n" +
+		"host in one pass, grouped by the source that started it, with warnings rolled\n" +
+		"up and the listeners nothing explains (or that were started by hand) summarized.",
+	Example: `  # Review a new host
+  sudo witr audit
+
+  # Attach the inventory to a ticket
+  sudo witr audit --markdown > audit.md
+
+  # Audit a host after the fact
+  witr audit --from-snapshot incident.witr`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().Bool("json", false, "show the report as JSON")
+	auditCmd.Flags().Bool("markdown", false, "show the report as Markdown")
+	auditCmd.Flags().Bool("no-color", false, "disable colorized output")
+	auditCmd.Flags().String("from-snapshot", "", "audit a snapshot saved with 'witr snapshot save' instead of the live system")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, _ []string) error {
+	flags := appFlags{json: boolFlag(cmd, "json"), noColor: boolFlag(cmd, "no-color")}
+	markdown := boolFlag(cmd, "markdown")
+	if flags.json && markdown {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid flags: --json cannot be combined with --markdown"))
+	}
+
+	hostname := ""
+	if path, _ := cmd.Flags().GetString("from-snapshot"); path != "" {
+		archive, err := activateSnapshot(cmd, path)
+		if err != nil {
+			return err
+		}
+		defer deactivateSnapshot()
+		hostname = archive.Hostname
+	}
+
+	report, err := audit.Run()
+	if err != nil {
+		return withExitCode(classifyError(err), err)
+	}
+	if hostname != "" {
+		report.Hostname = hostname
+	}
+
+	outw := cmd.OutOrStdout()
+	switch {
+	case flags.json:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return withExitCode(ExitInternalError, err)
+		}
+		fmt.Fprintln(outw, string(data))
+	case markdown:
+		audit.RenderMarkdown(outw, report)
+	default:
+		audit.RenderText(outw, report, useColor(flags, outw))
+	}
+
+	if report.WarningCount() > 0 {
+		cmd.SilenceErrors = true
+		return withExitCode(ExitWarnings, fmt.Errorf("completed with exit code %d", ExitWarnings))
+	}
+	return nil
+}
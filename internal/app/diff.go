@@ -0,0 +1,91 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <snapshotA> <snapshotB>",
+	Short: "Show what started or stopped between two snapshots, and why",
+	Long: "Compare two archives written by 'witr snapshot save' and report the services,\n" +
+		"processes, listeners, containers and locks that appeared or disappeared,\n" +
+		"each annotated with the source that explains it.",
+	Example: `  # Before and after a deploy
+  witr snapshot save before.witr
+  ./deploy.sh
+  witr snapshot save after.witr
+  witr diff before.witr after.witr`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().Bool("json", false, "show changes as JSON")
+	diffCmd.Flags().Bool("no-color", false, "disable colorized output")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	before, err := snapshot.Load(args[0])
+	if err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+	after, err := snapshot.Load(args[1])
+	if err != nil {
+		return withExitCode(ExitInvalidInput, err)
+	}
+
+	flags := appFlags{json: boolFlag(cmd, "json"), noColor: boolFlag(cmd, "no-color")}
+	outw := cmd.OutOrStdout()
+	changes := snapshot.Diff(before, after)
+
+	if flags.json {
+		if changes == nil {
+			changes = []snapshot.Change{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return withExitCode(ExitInternalError, err)
+		}
+		fmt.Fprintln(outw, string(data))
+		return nil
+	}
+
+	printDiff(outw, before, after, changes, useColor(flags, outw))
+	return nil
+}
+
+func printDiff(w io.Writer, before, after *snapshot.Archive, changes []snapshot.Change, colorEnabled bool) {
+	outp := output.NewPrinter(w)
+	const stamp = "2006-01-02 15:04:05 MST"
+	outp.Printf("Comparing %s at %s with %s at %s\n\n",
+		before.Hostname, before.CapturedAt.Format(stamp), after.Hostname, after.CapturedAt.Format(stamp))
+
+	if len(changes) == 0 {
+		outp.Printf("No changes.\n")
+		return
+	}
+
+	added := 0
+	for _, c := range changes {
+		mark, color := "-", output.ColorRed
+		if c.Added {
+			mark, color = "+", output.ColorGreen
+			added++
+		}
+		if colorEnabled {
+			outp.Printf("%s%s%s %s\n", color, mark, output.ColorReset, c.Message)
+		} else {
+			outp.Printf("%s %s\n", mark, c.Message)
+		}
+	}
+	outp.Printf("\n%d appeared, %d disappeared\n", added, len(changes)-added)
+}
@@ -64,7 +64,19 @@ func TestExitCodes(t *testing.T) {
 		{"invalid pid (non-numeric)", []string{"--pid", "notanumber"}, ExitInvalidInput},
 		{"invalid pid (zero)", []string{"--pid", "0"}, ExitInvalidInput},
 		{"invalid port (out of range)", []string{"--port", "70000"}, ExitInvalidInput},
+		{"invalid port range", []string{"--port", "9000-8000"}, ExitInvalidInput},
+		{"unknown port service", []string{"--port", "no-such-service-witr"}, ExitInvalidInput},
 		{"not found (ghost pid)", []string{"--pid", ghostPID}, ExitNotFound},
+		{"unknown report format", []string{"--format", "xml", "--pid", ghostPID}, ExitInvalidInput},
+		{"report format with --json", []string{"--format", "sarif", "--json", "--pid", ghostPID}, ExitInvalidInput},
+		{"report for a ghost pid", []string{"--format", "junit", "--pid", ghostPID}, ExitNotFound},
+		{"audit with --json and --markdown", []string{"audit", "--json", "--markdown"}, ExitInvalidInput},
+		{"invalid unit pattern", []string{"--unit", "nginx["}, ExitInvalidInput},
+		{"blank user", []string{"--user", " "}, ExitInvalidInput},
+		{"user with no processes", []string{"--user", "2147483646"}, ExitNotFound},
+		{"invalid remote network", []string{"--remote", "10.0.0.0/99"}, ExitInvalidInput},
+		{"remote port out of range", []string{"--remote", "10.0.0.5:0"}, ExitInvalidInput},
+		{"missing policy file", []string{"--policy", filepath.Join(t.TempDir(), "policy.yaml"), "--pid", ghostPID}, ExitInvalidInput},
 		// Multi-target exit code is the highest severity among targets, not the
 		// first or last — assert with both orderings of a not-found(2) and an
 		// invalid(4) target.
@@ -0,0 +1,78 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// processLoginTarget handles `--user`, `--session` and `--tty` lookups: every
+// matching process is analyzed and their ancestries are shown together as
+// one forest rather than one divider per process.
+func processLoginTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+	colorEnabled := useColor(flags, outw)
+
+	pids, err := target.ResolveLogin(t)
+	if err != nil {
+		return handleResolveError(cmd, outw, outp, t, err, flags, multiMode, jsonResults)
+	}
+
+	// Processes that exit before they are analyzed are left out.
+	l := output.LoginResult{Target: t}
+	highestExit := ExitOK
+	for _, pid := range pids {
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:     pid,
+			Verbose: flags.verbose,
+			Peers:   flags.verbose || flags.json,
+			Target:  t,
+		})
+		if err != nil {
+			continue
+		}
+		if len(res.Warnings) > 0 {
+			highestExit = ExitWarnings
+		}
+		l.Processes = append(l.Processes, res)
+	}
+	if len(l.Processes) == 0 {
+		return handleResolveError(cmd, outw, outp, t, fmt.Errorf("no running processes left to analyze"), flags, multiMode, jsonResults)
+	}
+
+	switch {
+	case flags.json:
+		jsonStr, err := output.LoginToJSON(l)
+		if err != nil {
+			outp.Printf("failed to generate json output: %v\n", err)
+			return ExitInternalError
+		}
+		if multiMode {
+			*jsonResults = append(*jsonResults, jsonStr)
+		} else {
+			fmt.Fprintln(outw, jsonStr)
+		}
+	case flags.warn:
+		for i, r := range l.Processes {
+			if i > 0 {
+				outp.Println()
+			}
+			output.RenderWarnings(outw, r, colorEnabled)
+		}
+	case flags.short:
+		for _, r := range l.Processes {
+			output.RenderShort(outw, r, colorEnabled)
+		}
+	case flags.tree:
+		output.RenderForest(outw, l.Processes, colorEnabled)
+	default:
+		output.RenderLogin(outw, l, colorEnabled)
+	}
+	return highestExit
+}
@@ -0,0 +1,91 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// processRemoteTarget handles `--remote` lookups: every local process with a
+// connection to the endpoint is analyzed and shown with its own ancestry,
+// and the connections that matched.
+func processRemoteTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+	colorEnabled := useColor(flags, outw)
+
+	conns, err := target.ResolveRemote(t.Value)
+	if err != nil {
+		return handleResolveError(cmd, outw, outp, t, err, flags, multiMode, jsonResults)
+	}
+
+	// Processes that exit before they are analyzed are left out.
+	var results []model.Result
+	highestExit := ExitOK
+	for _, pid := range slices.Sorted(maps.Keys(conns)) {
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:     pid,
+			Verbose: flags.verbose,
+			Peers:   flags.verbose || flags.json,
+			Tree:    flags.tree,
+			Target:  t,
+		})
+		if err != nil {
+			continue
+		}
+		res.Connections = conns[pid]
+		if len(res.Warnings) > 0 {
+			highestExit = ExitWarnings
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		return handleResolveError(cmd, outw, outp, t, fmt.Errorf("no process connected to %s left to analyze", t.Value), flags, multiMode, jsonResults)
+	}
+
+	if flags.json {
+		// Any number of processes can be connected; the result is always an
+		// array so scripts don't have to special-case a single match.
+		var entries []string
+		for _, res := range results {
+			jsonStr, err := output.ToJSON(res)
+			if err != nil {
+				outp.Printf("failed to generate json output: %v\n", err)
+				return ExitInternalError
+			}
+			entries = append(entries, jsonStr)
+		}
+		jsonStr := "[\n  " + strings.ReplaceAll(strings.Join(entries, ",\n"), "\n", "\n  ") + "\n]"
+		if multiMode {
+			*jsonResults = append(*jsonResults, jsonStr)
+		} else {
+			fmt.Fprintln(outw, jsonStr)
+		}
+		return highestExit
+	}
+
+	for i, res := range results {
+		if i > 0 && !flags.short {
+			outp.Println()
+		}
+		switch {
+		case flags.warn:
+			output.RenderWarnings(outw, res, colorEnabled)
+		case flags.tree:
+			output.PrintTree(outw, res.Ancestry, res.Children, colorEnabled)
+		case flags.short:
+			output.RenderShort(outw, res, colorEnabled)
+		default:
+			output.RenderStandard(outw, res, colorEnabled, flags.verbose)
+		}
+	}
+	return highestExit
+}
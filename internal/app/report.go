@@ -0,0 +1,78 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/policy"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// reportFormats are the --format values: machine-readable warning reports
+// for code-scanning dashboards (SARIF) and CI test reporters (JUnit).
+var reportFormats = map[string]func([]output.WarningReport) (string, error){
+	"sarif": func(reports []output.WarningReport) (string, error) {
+		return output.ToSARIF(reports, version, policy.RuleDescription)
+	},
+	"junit": output.ToJUnit,
+}
+
+// runReport analyzes every process each target resolves to and prints their
+// warnings as one --format document. The exit code is the same as a
+// --warnings run's: the highest across targets.
+func runReport(cmd *cobra.Command, targets []model.Target, flags appFlags) error {
+	render, ok := reportFormats[flags.format]
+	if !ok {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --format %q: must be sarif or junit", flags.format))
+	}
+	conflicts := []struct {
+		name string
+		set  bool
+	}{
+		{"--json", flags.json},
+		{"--short", flags.short},
+		{"--tree", flags.tree},
+		{"--env", flags.env},
+		{"--watch", cmd.Flags().Changed("watch")},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("invalid flags: --format cannot be combined with %s", c.name))
+		}
+	}
+
+	highestExit := ExitOK
+	reports := make([]output.WarningReport, 0, len(targets))
+	for _, t := range targets {
+		rep := output.WarningReport{Label: targetLabel(t), Target: t}
+		results, err := resolveAndAnalyze(t, flags.exact)
+		exitCode := ExitOK
+		if err != nil {
+			rep.Err = err.Error()
+			exitCode = classifyError(err)
+		}
+		for _, res := range results {
+			rep.Results = append(rep.Results, res)
+			if len(res.Warnings) > 0 {
+				exitCode = max(exitCode, ExitWarnings)
+			}
+		}
+		highestExit = max(highestExit, exitCode)
+		reports = append(reports, rep)
+	}
+
+	doc, err := render(reports)
+	if err != nil {
+		return withExitCode(ExitInternalError, fmt.Errorf("failed to generate %s output: %w", flags.format, err))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), doc)
+
+	if highestExit > ExitOK {
+		cmd.SilenceErrors = true
+		return withExitCode(highestExit, fmt.Errorf("completed with exit code %d", highestExit))
+	}
+	return nil
+}
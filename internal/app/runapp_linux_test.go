@@ -0,0 +1,53 @@
+//go:build linux
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// TestRunAppSocketTarget resolves a unix socket of our own and checks the
+// report carries the description the owners were resolved from.
+func TestRunAppSocketTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	oldArgs := os.Args
+	os.Args = []string{"witr", "--socket", path, "--json"}
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	// A command of its own, so the flags set here don't stay set on the
+	// root command for the tests that follow.
+	cmd := &cobra.Command{Use: "witr", Args: cobra.ArbitraryArgs, PersistentPreRunE: preRun, RunE: runApp}
+	defineRootFlags(cmd)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--socket", path, "--json"})
+
+	// Warnings make Execute fail and cobra print usage after the report.
+	_ = cmd.Execute()
+
+	var res model.Result
+	if err := json.NewDecoder(&out).Decode(&res); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out.String())
+	}
+	if res.Process.PID != os.Getpid() {
+		t.Errorf("Process.PID = %d, want %d", res.Process.PID, os.Getpid())
+	}
+	if res.UnixSocket == nil || res.UnixSocket.Path != path || res.UnixSocket.State != "LISTEN" {
+		t.Errorf("UnixSocket = %+v, want %s listening", res.UnixSocket, path)
+	}
+}
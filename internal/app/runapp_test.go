@@ -3,9 +3,13 @@ package app
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
 )
 
 // TestRunAppRendersReportForSelf drives the full command in-process against our
@@ -38,3 +42,44 @@ func TestRunAppRendersReportForSelf(t *testing.T) {
 		t.Errorf("runApp did not render a report for self (pid %s):\n%s", pid, report)
 	}
 }
+
+// TestRunAppFromSnapshot replays a saved capture of this very process: the
+// report must come from the archive, and live reads must be restored after.
+func TestRunAppFromSnapshot(t *testing.T) {
+	archive, err := snapshot.Capture()
+	if err != nil {
+		t.Skipf("cannot capture on this host: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "self.witr")
+	if err := archive.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+	argv := []string{"--from-snapshot", path, "--pid", pid}
+	oldArgs := os.Args
+	os.Args = append([]string{"witr"}, argv...)
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	cmd := Root()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(argv)
+	t.Cleanup(func() {
+		cmd.SetArgs(nil)
+		cmd.Flags().Set("from-snapshot", "")
+	})
+
+	_ = cmd.Execute()
+
+	if !strings.Contains(errOut.String(), "Replaying snapshot of") {
+		t.Errorf("replay notice missing from stderr:\n%s", errOut.String())
+	}
+	if !strings.Contains(out.String(), pid) {
+		t.Errorf("no report for self (pid %s) from the snapshot:\n%s", pid, out.String())
+	}
+	if procpkg.ActiveReplay() != nil {
+		t.Error("replay must be deactivated once the command returns")
+	}
+}
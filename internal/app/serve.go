@@ -0,0 +1,83 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/server"
+	"github.com/spf13/cobra"
+)
+
+const defaultServeListen = "unix:///run/witr.sock"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve witr's analyses as a JSON HTTP API",
+	Long: `Serve the same analyses as the CLI over HTTP, as JSON:
+
+  GET /v1/pid/{pid}          model.Result
+  GET /v1/port/{port}        model.Result (with SocketInfo)
+  GET /v1/name/{name}        model.Result
+  GET /v1/file?path=<path>   model.Result
+  GET /v1/container/{query}  {"Container": model.ContainerMatch, "Result": model.Result}
+  GET /v1/processes          []model.Process
+  GET /v1/ports              []model.OpenPort
+
+Single-target endpoints accept ?verbose=1, ?tree=1 and ?exact=1. Errors are
+{"Error": "..."} with 404 (not found), 403 (permission), 409 (ambiguous, with
+the candidate PIDs) or 400 (invalid input).
+
+The API has no authentication of its own: prefer a unix socket and control
+access with its file mode.`,
+	Example: `  # Local unix socket, readable by root only
+  sudo witr serve --listen unix:///run/witr.sock
+  curl --unix-socket /run/witr.sock http://witr/v1/port/8080
+
+  # Loopback TCP
+  witr serve --listen 127.0.0.1:7878`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().String("listen", defaultServeListen, "address to listen on: unix:///path/to.sock or host:port")
+	serveCmd.Flags().String("socket-mode", "0600", "file mode for a unix socket (octal)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	addr, _ := cmd.Flags().GetString("listen")
+	modeStr, _ := cmd.Flags().GetString("socket-mode")
+	mode, err := strconv.ParseUint(modeStr, 8, 32)
+	if err != nil || mode > 0o777 {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --socket-mode %q: want an octal mode like 0660", modeStr))
+	}
+
+	ln, err := server.Listen(addr, os.FileMode(mode))
+	if err != nil {
+		if os.IsPermission(err) {
+			return withExitCode(ExitPermission, err)
+		}
+		return withExitCode(ExitInvalidInput, err)
+	}
+
+	errp := output.NewPrinter(cmd.ErrOrStderr())
+	if !server.IsLoopback(addr) {
+		errp.Printf("Warning: %s is reachable from other hosts and the API has no authentication\n", addr)
+	}
+	errp.Printf("witr API listening on %s\n", addr)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := server.Serve(ctx, ln); err != nil {
+		return withExitCode(ExitInternalError, err)
+	}
+	return nil
+}
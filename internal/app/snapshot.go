@@ -0,0 +1,76 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture the host's process, port, container and service state",
+	Long: "Capture everything witr reads from this host into a single archive file,\n" +
+		"so it can be analyzed later (or elsewhere) with --from-snapshot.",
+	Args: cobra.NoArgs,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save <file>",
+	Short: "Write a snapshot of the live system to file",
+	Example: `  # Capture the box during an incident, analyze it later on a laptop
+  sudo witr snapshot save incident.witr
+  witr --from-snapshot incident.witr --port 8080`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotSave,
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotSave(cmd *cobra.Command, args []string) error {
+	archive, err := snapshot.Capture()
+	if err != nil {
+		return withExitCode(ExitInternalError, fmt.Errorf("capture snapshot: %w", err))
+	}
+	if err := archive.Save(args[0]); err != nil {
+		if os.IsPermission(err) {
+			return withExitCode(ExitPermission, err)
+		}
+		return withExitCode(ExitInternalError, err)
+	}
+
+	outp := output.NewPrinter(cmd.OutOrStdout())
+	outp.Printf("Saved snapshot of %s to %s: %d processes, %d sockets, %d containers\n",
+		archive.Hostname, args[0], len(archive.Processes), len(archive.OpenPorts), len(archive.Containers))
+	return nil
+}
+
+// activateSnapshot loads the --from-snapshot archive and routes every host
+// query through it. Process actions (kill, signal) are disabled while
+// replaying, and a note goes to stderr so a replayed report is never mistaken
+// for the live host.
+func activateSnapshot(cmd *cobra.Command, path string) (*snapshot.Archive, error) {
+	archive, err := snapshot.Load(path)
+	if err != nil {
+		return nil, withExitCode(ExitInvalidInput, fmt.Errorf("--from-snapshot: %w", err))
+	}
+	archive.Activate()
+
+	if !boolFlag(cmd, "json") {
+		errp := output.NewPrinter(cmd.ErrOrStderr())
+		errp.Printf("Replaying snapshot of %s (%s) captured %s\n",
+			archive.Hostname, archive.OS, archive.CapturedAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	return archive, nil
+}
+
+// deactivateSnapshot restores live reads after a replayed run.
+func deactivateSnapshot() { procpkg.SetReplay(nil) }
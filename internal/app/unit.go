@@ -0,0 +1,122 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// processUnitTarget handles `--unit` lookups: every process in the cgroup of
+// each matching systemd unit is analyzed, and the unit is explained once
+// above its processes rather than once per process.
+func processUnitTarget(cmd *cobra.Command, outw io.Writer, outp output.Printer, t model.Target, flags appFlags, multiMode bool, jsonResults *[]string) int {
+	colorEnabled := useColor(flags, outw)
+
+	units, err := target.ResolveUnits(t.Value)
+	if err != nil {
+		return handleResolveError(cmd, outw, outp, t, err, flags, multiMode, jsonResults)
+	}
+
+	highestExit := ExitOK
+	var unitJSON []string
+	for i, u := range units {
+		ur := analyzeUnit(u, flags)
+		for _, r := range ur.Processes {
+			if len(r.Warnings) > 0 {
+				highestExit = ExitWarnings
+			}
+		}
+
+		if flags.json {
+			jsonStr, err := output.UnitToJSON(ur)
+			if err != nil {
+				outp.Printf("failed to generate json output: %v\n", err)
+				return ExitInternalError
+			}
+			unitJSON = append(unitJSON, jsonStr)
+			continue
+		}
+
+		if i > 0 {
+			outp.Println()
+		}
+		if !flags.short && !flags.tree && !flags.warn {
+			output.RenderUnit(outw, ur, colorEnabled)
+			continue
+		}
+		output.RenderUnitHeader(outw, ur.Unit, colorEnabled)
+		roles := ur.Roles()
+		for j, r := range ur.Processes {
+			outp.Println()
+			output.RenderUnitMember(outw, roles[j], r, colorEnabled)
+			switch {
+			case flags.warn:
+				output.RenderWarnings(outw, r, colorEnabled)
+			case flags.tree:
+				output.PrintTree(outw, r.Ancestry, r.Children, colorEnabled)
+			default:
+				output.RenderShort(outw, r, colorEnabled)
+			}
+		}
+	}
+
+	if flags.json {
+		// A glob can match several units; it always yields an array so
+		// scripts don't have to special-case a single match.
+		jsonStr := unitJSON[0]
+		if strings.ContainsAny(t.Value, "*?[") {
+			jsonStr = "[\n  " + strings.ReplaceAll(strings.Join(unitJSON, ",\n"), "\n", "\n  ") + "\n]"
+		}
+		if multiMode {
+			*jsonResults = append(*jsonResults, jsonStr)
+		} else {
+			fmt.Fprintln(outw, jsonStr)
+		}
+	}
+	return highestExit
+}
+
+// analyzeUnit runs the pipeline on every process of u. Processes that exit
+// before they are analyzed are left out.
+func analyzeUnit(u model.Unit, flags appFlags) output.UnitResult {
+	ur := output.UnitResult{Unit: u}
+	var members []model.UnitMember
+	for _, m := range u.Members {
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:     m.PID,
+			Verbose: flags.verbose,
+			Peers:   flags.verbose || flags.json,
+			Tree:    flags.tree,
+			Target:  model.Target{Type: model.TargetUnit, Value: u.Name},
+		})
+		if err != nil {
+			continue
+		}
+		members = append(members, m)
+		ur.Processes = append(ur.Processes, res)
+	}
+	ur.Unit.Members = members
+
+	// Without systemd's own description (e.g. when replaying a snapshot),
+	// fall back to what source detection recorded for the unit.
+	for _, r := range ur.Processes {
+		if r.Source.Type == model.SourceSystemd && r.Source.Name == u.Name {
+			if ur.Unit.Description == "" {
+				ur.Unit.Description = r.Source.Description
+			}
+			if ur.Unit.UnitFile == "" {
+				ur.Unit.UnitFile = r.Source.UnitFile
+			}
+			break
+		}
+	}
+	return ur
+}
@@ -0,0 +1,365 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is the poll period used by a bare --watch.
+const defaultWatchInterval = 2 * time.Second
+
+// minWatchInterval keeps a typo like --watch=1ms from pinning a CPU on
+// repeated /proc scans.
+const minWatchInterval = 100 * time.Millisecond
+
+// watchState is what one poll observed for a single target: either the
+// resolution error, or the analysis result of every PID the target resolved to.
+type watchState struct {
+	err     string
+	results map[int]model.Result
+}
+
+// watchChange is one difference between two consecutive polls of a target.
+type watchChange struct {
+	Time    time.Time
+	Target  model.Target
+	Kind    string // resolve, process, ancestry, source, warning, socket, restart
+	PID     int    `json:",omitempty"`
+	Message string
+}
+
+// runWatch re-analyzes every target each interval until interrupted, printing
+// only what changed since the previous poll. Targets are re-resolved on every
+// poll (not just re-read by PID), so a service that dies and comes back under
+// a new PID is followed rather than reported as gone.
+func runWatch(cmd *cobra.Command, targets []model.Target, flags appFlags) error {
+	// Watch prints its own baseline and change lines, which none of these
+	// output modes apply to.
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"env", flags.env},
+		{"tree", flags.tree},
+		{"short", flags.short},
+		{"warnings", flags.warn},
+		{"verbose", flags.verbose},
+	} {
+		if f.set {
+			return withExitCode(ExitInvalidInput, fmt.Errorf("invalid flags: --watch cannot be combined with --%s", f.name))
+		}
+	}
+	if flags.watch < minWatchInterval {
+		return withExitCode(ExitInvalidInput, fmt.Errorf("invalid --watch interval %s: must be at least %s", flags.watch, minWatchInterval))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	outw := cmd.OutOrStdout()
+	watchLoop(ctx, outw, targets, flags, observeTarget)
+	return nil
+}
+
+// watchLoop drives the poll/diff/print cycle. observe is injected so tests can
+// feed synthetic states without touching the live process table.
+func watchLoop(ctx context.Context, w io.Writer, targets []model.Target, flags appFlags, observe func(model.Target, appFlags) watchState) {
+	colorEnabled := useColor(flags, w)
+	prev := make([]watchState, len(targets))
+
+	for i, t := range targets {
+		prev[i] = observe(t, flags)
+		if !flags.json {
+			printWatchBaseline(w, t, prev[i], colorEnabled)
+		}
+	}
+	if !flags.json {
+		outp := output.NewPrinter(w)
+		outp.Printf("\nWatching %d target(s) every %s (Ctrl-C to stop)\n", len(targets), flags.watch)
+	}
+
+	ticker := time.NewTicker(flags.watch)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for i, t := range targets {
+				cur := observe(t, flags)
+				changes := diffWatchStates(t, prev[i], cur)
+				for j := range changes {
+					changes[j].Time = now
+				}
+				printWatchChanges(w, changes, flags.json, colorEnabled)
+				prev[i] = cur
+			}
+		}
+	}
+}
+
+// observeTarget runs resolveAndAnalyze for one poll.
+func observeTarget(t model.Target, flags appFlags) watchState {
+	results, err := resolveAndAnalyze(t, flags.exact)
+	if err != nil {
+		return watchState{err: err.Error()}
+	}
+	st := watchState{results: make(map[int]model.Result, len(results))}
+	for _, res := range results {
+		st.results[res.Process.PID] = res
+	}
+	return st
+}
+
+// resolveAndAnalyze resolves t and runs the analysis pipeline on every PID it
+// maps to, in PID order. Port targets also carry the socket state, matching
+// processTarget.
+func resolveAndAnalyze(t model.Target, exact bool) ([]model.Result, error) {
+	pids, err := watchResolve(t, exact)
+	if err == nil && len(pids) == 0 {
+		err = fmt.Errorf("no matching process found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Ints(pids)
+
+	var results []model.Result
+	for _, pid := range pids {
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:    pid,
+			Target: t,
+		})
+		if err != nil {
+			continue // exited between resolve and analyze
+		}
+		if t.Type == model.TargetPort {
+			target.DescribePort(&res, t.Value)
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no matching process found")
+	}
+	return results, nil
+}
+
+// watchResolve maps a target to PIDs, including container targets whose main
+// process is visible on the host.
+func watchResolve(t model.Target, exact bool) ([]int, error) {
+	if t.Type != model.TargetContainer {
+		return target.Resolve(t, exact)
+	}
+	var pids []int
+	for _, m := range procpkg.ResolveContainer(t.Value, exact) {
+		if pid := procpkg.ResolveContainerHostPID(m.Runtime, m.ID); pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no running container found matching %q", t.Value)
+	}
+	return pids, nil
+}
+
+// diffWatchStates lists what changed for target t between two polls.
+func diffWatchStates(t model.Target, prev, cur watchState) []watchChange {
+	var changes []watchChange
+	add := func(kind string, pid int, format string, args ...any) {
+		changes = append(changes, watchChange{Target: t, Kind: kind, PID: pid, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cur.err != "" {
+		if prev.err != cur.err {
+			add("resolve", 0, "target lost: %s", cur.err)
+		}
+		return changes
+	}
+	if prev.err != "" {
+		add("resolve", 0, "target is back")
+	}
+
+	for _, pid := range sortedPIDs(prev.results) {
+		if _, ok := cur.results[pid]; !ok {
+			add("process", pid, "%s (pid %d) is gone", prev.results[pid].Process.Command, pid)
+		}
+	}
+
+	for _, pid := range sortedPIDs(cur.results) {
+		c := cur.results[pid]
+		p, ok := prev.results[pid]
+		if !ok {
+			add("process", pid, "new process %s (pid %d): %s", c.Process.Command, pid, ancestryChain(c.Ancestry))
+			continue
+		}
+
+		if before, after := ancestryChain(p.Ancestry), ancestryChain(c.Ancestry); before != after {
+			add("ancestry", pid, "ancestry changed: %s → %s", before, after)
+		}
+		if before, after := sourceLabel(p.Source), sourceLabel(c.Source); before != after {
+			add("source", pid, "source changed: %s → %s", before, after)
+		}
+		if c.RestartCount > p.RestartCount {
+			add("restart", pid, "restart count %d → %d", p.RestartCount, c.RestartCount)
+		}
+		for _, w := range c.Warnings {
+			if !slices.Contains(p.Warnings, w) {
+				add("warning", pid, "new warning: %s", w)
+			}
+		}
+		for _, w := range p.Warnings {
+			if !slices.Contains(c.Warnings, w) {
+				add("warning", pid, "warning cleared: %s", w)
+			}
+		}
+		changes = append(changes, diffSockets(t, pid, p, c)...)
+	}
+	return changes
+}
+
+// diffSockets reports socket state transitions for one PID: the port
+// target's socket state, plus sockets of the process that appeared,
+// disappeared or changed state.
+func diffSockets(t model.Target, pid int, prev, cur model.Result) []watchChange {
+	var changes []watchChange
+	add := func(format string, args ...any) {
+		changes = append(changes, watchChange{Target: t, Kind: "socket", PID: pid, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if prev.SocketInfo != nil && cur.SocketInfo != nil && prev.SocketInfo.State != cur.SocketInfo.State {
+		add("port %d: %s → %s", cur.SocketInfo.Port, prev.SocketInfo.State, cur.SocketInfo.State)
+	}
+
+	before := socketStates(prev.Process.Sockets)
+	after := socketStates(cur.Process.Sockets)
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b, hadBefore := before[k]
+		a, hasAfter := after[k]
+		switch {
+		case !hadBefore:
+			add("%s opened (%s)", k, a)
+		case !hasAfter:
+			add("%s closed (was %s)", k, b)
+		case a != b:
+			add("%s: %s → %s", k, b, a)
+		}
+	}
+	return changes
+}
+
+// socketStates keys a process's sockets by "PROTO addr:port", plus
+// " → raddr:rport" for a connection, so the same socket can be compared
+// across polls without a listener and the connections accepted on its port
+// overwriting each other.
+func socketStates(sockets []model.Socket) map[string]string {
+	m := make(map[string]string, len(sockets))
+	for _, s := range sockets {
+		if s.Port == 0 {
+			continue
+		}
+		key := s.Protocol + " " + net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+		if s.RemotePort != 0 {
+			key += " → " + net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort))
+		}
+		m[key] = s.State
+	}
+	return m
+}
+
+func sortedPIDs(m map[int]model.Result) []int {
+	pids := make([]int, 0, len(m))
+	for pid := range m {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// ancestryChain renders an ancestry as the one-line "a (pid 1) → b (pid 2)"
+// form used by --short.
+func ancestryChain(ancestry []model.Process) string {
+	parts := make([]string, len(ancestry))
+	for i, p := range ancestry {
+		parts[i] = fmt.Sprintf("%s (pid %d)", output.ChainName(p), p.PID)
+	}
+	return strings.Join(parts, " → ")
+}
+
+func sourceLabel(s model.Source) string {
+	if s.Name != "" && s.Name != string(s.Type) {
+		return fmt.Sprintf("%s (%s)", s.Name, s.Type)
+	}
+	return string(s.Type)
+}
+
+// printWatchBaseline prints the first observation of a target so the change
+// lines that follow have something to be read against.
+func printWatchBaseline(w io.Writer, t model.Target, st watchState, colorEnabled bool) {
+	outp := output.NewPrinter(w)
+	label := targetLabel(t)
+	if colorEnabled {
+		outp.Printf("%s[%s]%s ", output.ColorCyan, label, output.ColorReset)
+	} else {
+		outp.Printf("[%s] ", label)
+	}
+	if st.err != "" {
+		outp.Printf("%s\n", st.err)
+		return
+	}
+	for i, pid := range sortedPIDs(st.results) {
+		if i > 0 {
+			outp.Printf("%s", strings.Repeat(" ", len(label)+3))
+		}
+		res := st.results[pid]
+		outp.Printf("%s (source: %s)\n", ancestryChain(res.Ancestry), sourceLabel(res.Source))
+	}
+}
+
+func printWatchChanges(w io.Writer, changes []watchChange, asJSON bool, colorEnabled bool) {
+	outp := output.NewPrinter(w)
+	for _, c := range changes {
+		if asJSON {
+			data, err := json.Marshal(c)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintln(w, string(data))
+			continue
+		}
+		ts := c.Time.Format("15:04:05")
+		if colorEnabled {
+			outp.Printf("%s%s%s %s[%s]%s %s\n", output.ColorDim, ts, output.ColorReset, output.ColorCyan, targetLabel(c.Target), output.ColorReset, c.Message)
+		} else {
+			outp.Printf("%s [%s] %s\n", ts, targetLabel(c.Target), c.Message)
+		}
+	}
+}
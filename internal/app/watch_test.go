@@ -0,0 +1,183 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+func watchResult(pid int, cmd string, restarts int, warnings ...string) model.Result {
+	proc := model.Process{PID: pid, PPID: 1, Command: cmd}
+	return model.Result{
+		Process:      proc,
+		Ancestry:     []model.Process{{PID: 1, Command: "systemd"}, proc},
+		Source:       model.Source{Type: model.SourceSystemd, Name: cmd + ".service"},
+		RestartCount: restarts,
+		Warnings:     warnings,
+	}
+}
+
+func messages(changes []watchChange) []string {
+	out := make([]string, len(changes))
+	for i, c := range changes {
+		out[i] = c.Kind + ": " + c.Message
+	}
+	return out
+}
+
+func TestDiffWatchStatesNoChange(t *testing.T) {
+	st := watchState{results: map[int]model.Result{100: watchResult(100, "nginx", 0)}}
+	if got := diffWatchStates(tgt(model.TargetPort, "8080"), st, st); len(got) != 0 {
+		t.Errorf("identical polls should report nothing, got %v", messages(got))
+	}
+}
+
+// A flapping service shows up as the old PID disappearing and a new one
+// appearing behind the same target, with the restart counter moving.
+func TestDiffWatchStatesRestartUnderNewPID(t *testing.T) {
+	prev := watchState{results: map[int]model.Result{100: watchResult(100, "nginx", 2)}}
+	cur := watchState{results: map[int]model.Result{200: watchResult(200, "nginx", 3)}}
+
+	got := messages(diffWatchStates(tgt(model.TargetPort, "8080"), prev, cur))
+	want := []string{
+		"process: nginx (pid 100) is gone",
+		"process: new process nginx (pid 200): systemd (pid 1) → nginx (pid 200)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffWatchStatesSamePID(t *testing.T) {
+	p := watchResult(100, "nginx", 1, "Process is running as root")
+	p.Process.Sockets = []model.Socket{{Protocol: "TCP", Address: "0.0.0.0", Port: 8080, State: "LISTEN"}}
+	p.SocketInfo = &model.SocketInfo{Port: 8080, State: "LISTEN"}
+
+	c := watchResult(100, "nginx", 4, "Process is listening on a public interface")
+	c.Ancestry = []model.Process{{PID: 1, Command: "systemd"}, {PID: 50, Command: "bash"}, c.Process}
+	c.Process.Sockets = []model.Socket{{Protocol: "TCP", Address: "0.0.0.0", Port: 8080, State: "CLOSE_WAIT"}}
+	c.SocketInfo = &model.SocketInfo{Port: 8080, State: "CLOSE_WAIT"}
+
+	got := messages(diffWatchStates(tgt(model.TargetPort, "8080"),
+		watchState{results: map[int]model.Result{100: p}},
+		watchState{results: map[int]model.Result{100: c}}))
+	want := []string{
+		"ancestry: ancestry changed: systemd (pid 1) → nginx (pid 100) → systemd (pid 1) → bash (pid 50) → nginx (pid 100)",
+		"restart: restart count 1 → 4",
+		"warning: new warning: Process is listening on a public interface",
+		"warning: warning cleared: Process is running as root",
+		"socket: port 8080: LISTEN → CLOSE_WAIT",
+		"socket: TCP 0.0.0.0:8080: LISTEN → CLOSE_WAIT",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestDiffWatchStatesAcceptedConnection keeps a listener apart from the
+// connections accepted on its port, which share its local endpoint.
+func TestDiffWatchStatesAcceptedConnection(t *testing.T) {
+	listener := model.Socket{Protocol: "TCP", Address: "0.0.0.0", Port: 8080, State: "LISTEN"}
+	p := watchResult(100, "nginx", 0)
+	p.Process.Sockets = []model.Socket{listener,
+		{Protocol: "TCP", Address: "10.0.0.2", Port: 8080, State: "ESTABLISHED", RemoteAddress: "10.0.0.9", RemotePort: 51000},
+	}
+	c := watchResult(100, "nginx", 0)
+	c.Process.Sockets = []model.Socket{
+		{Protocol: "TCP", Address: "10.0.0.2", Port: 8080, State: "CLOSE_WAIT", RemoteAddress: "10.0.0.9", RemotePort: 51000},
+		listener,
+		{Protocol: "TCP", Address: "10.0.0.2", Port: 8080, State: "ESTABLISHED", RemoteAddress: "10.0.0.7", RemotePort: 40000},
+	}
+
+	got := messages(diffWatchStates(tgt(model.TargetPort, "8080"),
+		watchState{results: map[int]model.Result{100: p}},
+		watchState{results: map[int]model.Result{100: c}}))
+	want := []string{
+		"socket: TCP 10.0.0.2:8080 → 10.0.0.7:40000 opened (ESTABLISHED)",
+		"socket: TCP 10.0.0.2:8080 → 10.0.0.9:51000: ESTABLISHED → CLOSE_WAIT",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffWatchStatesLostAndBack(t *testing.T) {
+	up := watchState{results: map[int]model.Result{100: watchResult(100, "nginx", 0)}}
+	down := watchState{err: "no process listening on port 8080"}
+	tg := tgt(model.TargetPort, "8080")
+
+	lost := messages(diffWatchStates(tg, up, down))
+	if len(lost) != 1 || lost[0] != "resolve: target lost: no process listening on port 8080" {
+		t.Errorf("lost = %v", lost)
+	}
+	// The same error on consecutive polls is not news.
+	if again := diffWatchStates(tg, down, down); len(again) != 0 {
+		t.Errorf("repeated error should be silent, got %v", messages(again))
+	}
+	back := messages(diffWatchStates(tg, down, up))
+	if len(back) != 2 || back[0] != "resolve: target is back" || !strings.HasPrefix(back[1], "process: new process nginx (pid 100)") {
+		t.Errorf("back = %v", back)
+	}
+}
+
+func TestWatchLoopPrintsBaselineThenChanges(t *testing.T) {
+	states := []watchState{
+		{results: map[int]model.Result{100: watchResult(100, "nginx", 0)}},
+		{results: map[int]model.Result{100: watchResult(100, "nginx", 1)}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := 0
+	observe := func(model.Target, appFlags) watchState {
+		st := states[min(polls, len(states)-1)]
+		polls++
+		if polls > len(states) {
+			cancel()
+		}
+		return st
+	}
+
+	var out bytes.Buffer
+	watchLoop(ctx, &out, []model.Target{tgt(model.TargetName, "nginx")}, appFlags{watch: time.Millisecond}, observe)
+
+	got := out.String()
+	if !strings.Contains(got, "[name: nginx] systemd (pid 1) → nginx (pid 100) (source: nginx.service (systemd))") {
+		t.Errorf("baseline missing:\n%s", got)
+	}
+	if !strings.Contains(got, "[name: nginx] restart count 0 → 1") {
+		t.Errorf("change line missing:\n%s", got)
+	}
+	if strings.Count(got, "restart count") != 1 {
+		t.Errorf("an unchanged poll must not repeat the change:\n%s", got)
+	}
+}
+
+// Output modes watch doesn't apply are refused rather than ignored.
+func TestRunWatchRejectsOutputModes(t *testing.T) {
+	targets := []model.Target{{Type: model.TargetPID, Value: "1"}}
+	for name, flags := range map[string]appFlags{
+		"env":      {env: true},
+		"tree":     {tree: true},
+		"short":    {short: true},
+		"warnings": {warn: true},
+		"verbose":  {verbose: true},
+	} {
+		flags.watch = time.Second
+		err := runWatch(&cobra.Command{}, targets, flags)
+		if err == nil || !strings.Contains(err.Error(), "--"+name) {
+			t.Errorf("runWatch with --%s = %v, want it refused", name, err)
+			continue
+		}
+		var ec *exitCodeError
+		if !errors.As(err, &ec) || ec.code != ExitInvalidInput {
+			t.Errorf("runWatch with --%s: exit code error = %v, want %d", name, err, ExitInvalidInput)
+		}
+	}
+}
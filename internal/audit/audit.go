@@ -0,0 +1,364 @@
+// Package audit builds a host-wide inventory of what is listening and running,
+// and why: every listening socket, every container and every service-managed
+// daemon, each traced to the source that started it.
+package audit
+
+import (
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Report is the result of an audit.
+type Report struct {
+	Hostname    string
+	GeneratedAt time.Time
+	// Groups holds every audited process, grouped by source type in
+	// groupOrder.
+	Groups []Group
+	// HiddenContainers are containers whose main process isn't visible on
+	// the host (VM-backed runtimes, other PID namespaces).
+	HiddenContainers []*model.ContainerMatch `json:",omitempty"`
+	// OrphanListeners are listening sockets with no visible owning process,
+	// usually because witr isn't running as root.
+	OrphanListeners []model.OpenPort `json:",omitempty"`
+	Summary         Summary
+}
+
+// Group is the audited processes started by one kind of source.
+type Group struct {
+	Source  model.SourceType
+	Title   string
+	Entries []Entry
+}
+
+// Entry is one audited process: its full analysis plus what brought it into
+// the audit.
+type Entry struct {
+	Result     model.Result
+	Listeners  []model.OpenPort        `json:",omitempty"`
+	Containers []*model.ContainerMatch `json:",omitempty"`
+}
+
+// Summary rolls the report up for a reviewer.
+type Summary struct {
+	Processes  int
+	Listeners  int
+	Containers int
+	// PublicListeners listen on a wildcard address.
+	PublicListeners int
+	// Unexplained listeners are owned by a process witr can't trace to any
+	// known source.
+	Unexplained []Listener
+	// Unsupervised listeners are owned by a process started by hand from a
+	// shell or SSH session: nothing restarts it and it won't survive a
+	// reboot.
+	Unsupervised []Listener
+	// Warnings counts findings per rule across every audited process.
+	Warnings []RuleCount
+}
+
+// Listener names a listening socket and the process that owns it.
+type Listener struct {
+	Address string
+	PID     int
+	Process string
+	Source  model.Source
+}
+
+// RuleCount is how many audited processes a warning rule fired on.
+type RuleCount struct {
+	RuleID   string
+	Severity model.Severity
+	Count    int
+	PIDs     []int
+}
+
+// groupOrder lists service managers first, then the rest in decreasing
+// order of how expected they are on a server.
+var groupOrder = []model.SourceType{
+	model.SourceSystemd,
+	model.SourceLaunchd,
+	model.SourceBsdRc,
+	model.SourceWindowsService,
+	model.SourceSupervisor,
+	model.SourceContainer,
+	model.SourceCron,
+	model.SourceInit,
+	model.SourceAutomation,
+	model.SourceSSH,
+	model.SourceShell,
+	model.SourceUnknown,
+}
+
+var groupTitles = map[model.SourceType]string{
+	model.SourceSystemd:        "systemd units",
+	model.SourceLaunchd:        "launchd jobs",
+	model.SourceBsdRc:          "rc.d services",
+	model.SourceWindowsService: "Windows services",
+	model.SourceSupervisor:     "supervisors",
+	model.SourceContainer:      "containers",
+	model.SourceCron:           "cron",
+	model.SourceInit:           "init",
+	model.SourceAutomation:     "CI and automation jobs",
+	model.SourceSSH:            "SSH sessions",
+	model.SourceShell:          "interactive shells",
+	model.SourceUnknown:        "unknown source",
+}
+
+// serviceSources are the source types whose processes are audited even when
+// they don't listen: long-lived daemons a reviewer wants inventoried.
+var serviceSources = map[model.SourceType]bool{
+	model.SourceSystemd:        true,
+	model.SourceLaunchd:        true,
+	model.SourceBsdRc:          true,
+	model.SourceWindowsService: true,
+	model.SourceSupervisor:     true,
+}
+
+// Run audits the host (or the active snapshot replay).
+func Run() (*Report, error) {
+	ports, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return nil, err
+	}
+	procs, err := procpkg.ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &Report{GeneratedAt: time.Now()}
+	rep.Hostname, _ = os.Hostname()
+
+	entries := make(map[int]*Entry)
+	// analyze runs the pipeline once per PID; ok is false when the process
+	// is gone or unreadable.
+	analyze := func(pid int) (*Entry, bool) {
+		if e, ok := entries[pid]; ok {
+			return e, e != nil
+		}
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:    pid,
+			Target: model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)},
+		})
+		if err != nil {
+			entries[pid] = nil
+			return nil, false
+		}
+		e := &Entry{Result: res}
+		entries[pid] = e
+		return e, true
+	}
+
+	for _, p := range listening(ports) {
+		rep.Summary.Listeners++
+		if isWildcard(p.Address) {
+			rep.Summary.PublicListeners++
+		}
+		if p.PID <= 0 {
+			rep.OrphanListeners = append(rep.OrphanListeners, p)
+			continue
+		}
+		e, ok := analyze(p.PID)
+		if !ok {
+			rep.OrphanListeners = append(rep.OrphanListeners, p)
+			continue
+		}
+		e.Listeners = append(e.Listeners, p)
+	}
+
+	for _, c := range procpkg.ListAllContainers() {
+		rep.Summary.Containers++
+		pid := procpkg.ResolveContainerHostPID(c.Runtime, c.ID)
+		if pid <= 0 || !procpkg.PIDBelongsToContainer(pid, c.ID) {
+			rep.HiddenContainers = append(rep.HiddenContainers, c)
+			continue
+		}
+		e, ok := analyze(pid)
+		if !ok {
+			rep.HiddenContainers = append(rep.HiddenContainers, c)
+			continue
+		}
+		e.Containers = append(e.Containers, c)
+		e.Result.Process.Container = output.FormatContainerLine(c)
+	}
+
+	// Service-managed daemons: the direct children of init (or of the
+	// Windows service control manager) are where service managers put their
+	// main processes.
+	byPID := make(map[int]model.Process, len(procs))
+	for _, p := range procs {
+		byPID[p.PID] = p
+	}
+	for _, p := range procs {
+		if _, seen := entries[p.PID]; seen || !isDaemonCandidate(p, byPID) {
+			continue
+		}
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:    p.PID,
+			Target: model.Target{Type: model.TargetPID, Value: strconv.Itoa(p.PID)},
+		})
+		if err != nil || !serviceSources[res.Source.Type] {
+			continue
+		}
+		entries[p.PID] = &Entry{Result: res}
+	}
+
+	rep.Groups = group(entries)
+	rep.Summary.summarize(rep.Groups)
+	return rep, nil
+}
+
+// listening returns the listening sockets of ports, sorted by port. A UDP
+// socket counts when it is bound but unconnected (reported as CLOSE on Linux,
+// stateless elsewhere).
+func listening(ports []model.OpenPort) []model.OpenPort {
+	var out []model.OpenPort
+	for _, p := range ports {
+		udp := strings.HasPrefix(strings.ToUpper(p.Protocol), "UDP")
+		if p.State == "LISTEN" || (udp && (p.State == "" || p.State == "CLOSE")) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Port != out[j].Port {
+			return out[i].Port < out[j].Port
+		}
+		return out[i].Address < out[j].Address
+	})
+	return out
+}
+
+func isWildcard(addr string) bool {
+	return addr == "0.0.0.0" || addr == "::" || addr == "*" || addr == ""
+}
+
+// isDaemonCandidate reports whether p's parent is init or the Windows
+// service control manager.
+func isDaemonCandidate(p model.Process, byPID map[int]model.Process) bool {
+	if p.PID <= 1 {
+		return false
+	}
+	if p.PPID == 1 {
+		return true
+	}
+	parent, ok := byPID[p.PPID]
+	return ok && strings.EqualFold(parent.Command, "services.exe")
+}
+
+func group(entries map[int]*Entry) []Group {
+	bySource := make(map[model.SourceType][]Entry)
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		st := e.Result.Source.Type
+		if st == "" {
+			st = model.SourceUnknown
+		}
+		bySource[st] = append(bySource[st], *e)
+	}
+
+	var groups []Group
+	add := func(st model.SourceType) {
+		list := bySource[st]
+		if len(list) == 0 {
+			return
+		}
+		sort.Slice(list, func(i, j int) bool {
+			ni, nj := list[i].Result.Source.Name, list[j].Result.Source.Name
+			if ni != nj {
+				return ni < nj
+			}
+			return list[i].Result.Process.PID < list[j].Result.Process.PID
+		})
+		title := groupTitles[st]
+		if title == "" {
+			title = string(st)
+		}
+		groups = append(groups, Group{Source: st, Title: title, Entries: list})
+		delete(bySource, st)
+	}
+	for _, st := range groupOrder {
+		add(st)
+	}
+	// Custom detector types (sources.yaml) sort after the built-ins.
+	var rest []string
+	for st := range bySource {
+		rest = append(rest, string(st))
+	}
+	sort.Strings(rest)
+	for _, st := range rest {
+		add(model.SourceType(st))
+	}
+	return groups
+}
+
+func (s *Summary) summarize(groups []Group) {
+	counts := make(map[string]*RuleCount)
+	var order []string
+	for _, g := range groups {
+		for _, e := range g.Entries {
+			s.Processes++
+			for _, l := range e.Listeners {
+				ln := Listener{
+					Address: listenAddress(l),
+					PID:     e.Result.Process.PID,
+					Process: e.Result.Process.Command,
+					Source:  e.Result.Source,
+				}
+				switch g.Source {
+				case model.SourceUnknown:
+					s.Unexplained = append(s.Unexplained, ln)
+				case model.SourceShell, model.SourceSSH:
+					s.Unsupervised = append(s.Unsupervised, ln)
+				}
+			}
+			for _, f := range e.Result.Findings {
+				rc, ok := counts[f.RuleID]
+				if !ok {
+					rc = &RuleCount{RuleID: f.RuleID, Severity: f.Severity}
+					counts[f.RuleID] = rc
+					order = append(order, f.RuleID)
+				}
+				rc.Count++
+				rc.PIDs = append(rc.PIDs, e.Result.Process.PID)
+				if f.Severity.Rank() > rc.Severity.Rank() {
+					rc.Severity = f.Severity
+				}
+			}
+		}
+	}
+	for _, id := range order {
+		s.Warnings = append(s.Warnings, *counts[id])
+	}
+	// Most severe first, then most widespread.
+	sort.SliceStable(s.Warnings, func(i, j int) bool {
+		if s.Warnings[i].Severity != s.Warnings[j].Severity {
+			return s.Warnings[i].Severity.Rank() > s.Warnings[j].Severity.Rank()
+		}
+		return s.Warnings[i].Count > s.Warnings[j].Count
+	})
+}
+
+// listenAddress renders a socket as "TCP 0.0.0.0:22".
+func listenAddress(p model.OpenPort) string {
+	return strings.ToUpper(p.Protocol) + " " + net.JoinHostPort(p.Address, strconv.Itoa(p.Port))
+}
+
+// WarningCount is the total number of findings across the report.
+func (r *Report) WarningCount() int {
+	n := 0
+	for _, w := range r.Summary.Warnings {
+		n += w.Count
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func entry(pid int, command string, src model.Source, listeners []model.OpenPort, findings ...model.Finding) *Entry {
+	p := model.Process{PID: pid, PPID: 1, Command: command}
+	return &Entry{
+		Result: model.Result{
+			Process:  p,
+			Ancestry: []model.Process{{PID: 1, Command: "systemd"}, p},
+			Source:   src,
+			Findings: findings,
+		},
+		Listeners: listeners,
+	}
+}
+
+func fixtureReport() *Report {
+	public := model.Finding{RuleID: "public-bind", Severity: model.SeverityMedium, Message: "Process is listening on a public interface"}
+	root := model.Finding{RuleID: "root", Severity: model.SeverityLow, Message: "Process is running as root"}
+	deleted := model.Finding{RuleID: "deleted-binary", Severity: model.SeverityHigh, Message: "Process is running from a deleted binary"}
+	entries := map[int]*Entry{
+		812: entry(812, "nginx", model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
+			[]model.OpenPort{{PID: 812, Port: 80, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"}}, public, root),
+		700: entry(700, "sshd", model.Source{Type: model.SourceSystemd, Name: "ssh.service"},
+			[]model.OpenPort{{PID: 700, Port: 22, Address: "::", Protocol: "TCP6", State: "LISTEN"}}, public, root),
+		4100: entry(4100, "python3", model.Source{Type: model.SourceShell, Name: "bash"},
+			[]model.OpenPort{{PID: 4100, Port: 8000, Address: "127.0.0.1", Protocol: "TCP", State: "LISTEN"}}),
+		4000: entry(4000, "backdoor|x", model.Source{Type: model.SourceUnknown},
+			[]model.OpenPort{{PID: 4000, Port: 9999, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"}}, deleted),
+		900: entry(900, "worker", model.Source{Type: "procman", Name: "procman"}, nil),
+		901: nil, // vanished before analysis
+	}
+	rep := &Report{Hostname: "web-1", GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	rep.Groups = group(entries)
+	rep.Summary.summarize(rep.Groups)
+	rep.OrphanListeners = []model.OpenPort{{Port: 25, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"}}
+	return rep
+}
+
+func TestGroupAndSummarize(t *testing.T) {
+	rep := fixtureReport()
+
+	var titles []string
+	for _, g := range rep.Groups {
+		titles = append(titles, g.Title)
+	}
+	if got, want := strings.Join(titles, ","), "systemd units,interactive shells,unknown source,procman"; got != want {
+		t.Errorf("groups = %s, want %s", got, want)
+	}
+	if sd := rep.Groups[0].Entries; sd[0].Result.Source.Name != "nginx.service" || sd[1].Result.Source.Name != "ssh.service" {
+		t.Errorf("systemd entries not sorted by unit: %s, %s", sd[0].Result.Source.Name, sd[1].Result.Source.Name)
+	}
+
+	s := rep.Summary
+	if s.Processes != 5 {
+		t.Errorf("Processes = %d, want 5", s.Processes)
+	}
+	if len(s.Unexplained) != 1 || s.Unexplained[0].Address != "TCP 0.0.0.0:9999" || s.Unexplained[0].PID != 4000 {
+		t.Errorf("Unexplained = %+v", s.Unexplained)
+	}
+	if len(s.Unsupervised) != 1 || s.Unsupervised[0].Address != "TCP 127.0.0.1:8000" {
+		t.Errorf("Unsupervised = %+v", s.Unsupervised)
+	}
+
+	var rules []string
+	for _, rc := range s.Warnings {
+		rules = append(rules, rc.RuleID)
+	}
+	if got, want := strings.Join(rules, ","), "deleted-binary,public-bind,root"; got != want {
+		t.Errorf("warning roll-up = %s, want %s (most severe first)", got, want)
+	}
+	if s.Warnings[1].Count != 2 || len(s.Warnings[1].PIDs) != 2 {
+		t.Errorf("public-bind = %+v, want 2 processes", s.Warnings[1])
+	}
+	if rep.WarningCount() != 5 {
+		t.Errorf("WarningCount = %d, want 5", rep.WarningCount())
+	}
+}
+
+func TestListening(t *testing.T) {
+	got := listening([]model.OpenPort{
+		{Port: 443, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+		{Port: 51000, Address: "10.0.0.5", Protocol: "TCP", State: "ESTABLISHED"},
+		{Port: 53, Address: "127.0.0.53", Protocol: "UDP", State: "CLOSE"},
+		{Port: 68, Address: "0.0.0.0", Protocol: "UDP", State: "ESTABLISHED"},
+		{Port: 22, Address: "::", Protocol: "TCP6", State: "LISTEN"},
+	})
+	var ports []int
+	for _, p := range got {
+		ports = append(ports, p.Port)
+	}
+	if len(ports) != 3 || ports[0] != 22 || ports[1] != 53 || ports[2] != 443 {
+		t.Errorf("listening ports = %v, want [22 53 443]", ports)
+	}
+}
+
+func TestIsDaemonCandidate(t *testing.T) {
+	byPID := map[int]model.Process{
+		600: {PID: 600, Command: "services.exe"},
+		601: {PID: 601, PPID: 600, Command: "svchost.exe"},
+		700: {PID: 700, PPID: 1, Command: "sshd"},
+		701: {PID: 701, PPID: 700, Command: "sshd"},
+	}
+	for pid, want := range map[int]bool{1: false, 601: true, 700: true, 701: false} {
+		if got := isDaemonCandidate(byPID[pid], byPID); got != want {
+			t.Errorf("isDaemonCandidate(%d) = %v, want %v", pid, got, want)
+		}
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	var buf bytes.Buffer
+	RenderText(&buf, fixtureReport(), false)
+	out := buf.String()
+	for _, want := range []string{
+		"Audit of web-1 at 2026-01-02 03:04:05 UTC",
+		"systemd units (2)",
+		"nginx.service: nginx  (pid 812)  TCP 0.0.0.0:80",
+		"! Process is running as root [root low]",
+		"listeners with no visible owner (1)",
+		"Unsupervised : 1",
+		"TCP 127.0.0.1:8000  python3 (pid 4100) from an interactive shell (bash)",
+		"public-bind              medium   2 processes",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text report lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, fixtureReport())
+	out := buf.String()
+	for _, want := range []string{
+		"# witr audit: web-1",
+		"| Unexplained listeners | 1 |",
+		"| `TCP 0.0.0.0:9999` | backdoor\\|x (pid 4000) | no known source |",
+		"## systemd units (2)",
+		"| `deleted-binary` | high | 1 | 4000 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown report lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const stamp = "2006-01-02 15:04:05 MST"
+
+// RenderText prints the report for a terminal: one section per source type,
+// each process with its listeners, containers and warnings, then the summary.
+func RenderText(w io.Writer, r *Report, colorEnabled bool) {
+	outp := output.NewPrinter(w)
+	heading := func(s string) {
+		if colorEnabled {
+			outp.Printf("%s%s%s\n", output.ColorBlue, s, output.ColorReset)
+		} else {
+			outp.Printf("%s\n", s)
+		}
+	}
+
+	outp.Printf("Audit of %s at %s\n", r.Hostname, r.GeneratedAt.Format(stamp))
+
+	for _, g := range r.Groups {
+		outp.Println()
+		heading(fmt.Sprintf("%s (%d)", g.Title, len(g.Entries)))
+		for _, e := range g.Entries {
+			proc := e.Result.Process
+			name := output.SanitizeTerminal(entryName(e))
+			if colorEnabled {
+				outp.Printf("  %s%s%s  %s(pid %d)%s", output.ColorGreen, name, output.ColorReset, output.ColorDim, proc.PID, output.ColorReset)
+			} else {
+				outp.Printf("  %s  (pid %d)", name, proc.PID)
+			}
+			if ls := listenerList(e.Listeners); ls != "" {
+				outp.Printf("  %s", ls)
+			}
+			outp.Println()
+			for _, c := range e.Containers {
+				outp.Printf("      container : %s\n", output.SanitizeTerminal(output.FormatContainerLine(c)))
+			}
+			for _, f := range e.Result.Findings {
+				if colorEnabled {
+					outp.Printf("      %s⚠%s %s [%s %s]\n", output.ColorRed, output.ColorReset, output.SanitizeTerminal(f.Message), f.RuleID, f.Severity)
+				} else {
+					outp.Printf("      ! %s [%s %s]\n", output.SanitizeTerminal(f.Message), f.RuleID, f.Severity)
+				}
+			}
+		}
+	}
+
+	if len(r.HiddenContainers) > 0 {
+		outp.Println()
+		heading(fmt.Sprintf("containers not visible on the host (%d)", len(r.HiddenContainers)))
+		for _, c := range r.HiddenContainers {
+			outp.Printf("  %s\n", output.SanitizeTerminal(output.FormatContainerLine(c)))
+		}
+	}
+	if len(r.OrphanListeners) > 0 {
+		outp.Println()
+		heading(fmt.Sprintf("listeners with no visible owner (%d)", len(r.OrphanListeners)))
+		for _, p := range r.OrphanListeners {
+			outp.Printf("  %s\n", listenAddress(p))
+		}
+		outp.Println("  (run as root to attribute them)")
+	}
+
+	s := r.Summary
+	outp.Println()
+	heading("Summary")
+	outp.Printf("  Processes    : %d\n", s.Processes)
+	outp.Printf("  Listeners    : %d (%d on all interfaces)\n", s.Listeners, s.PublicListeners)
+	outp.Printf("  Containers   : %d\n", s.Containers)
+	outp.Printf("  Unexplained  : %d\n", len(s.Unexplained))
+	for _, l := range s.Unexplained {
+		outp.Printf("    %s  %s (pid %d)\n", l.Address, output.SanitizeTerminal(l.Process), l.PID)
+	}
+	outp.Printf("  Unsupervised : %d\n", len(s.Unsupervised))
+	for _, l := range s.Unsupervised {
+		outp.Printf("    %s  %s (pid %d) from %s\n", l.Address, output.SanitizeTerminal(l.Process), l.PID, sourcePhrase(l.Source))
+	}
+	if len(s.Warnings) == 0 {
+		outp.Println("  Warnings     : none")
+		return
+	}
+	outp.Printf("  Warnings     : %d\n", r.WarningCount())
+	for _, rc := range s.Warnings {
+		outp.Printf("    %-24s %-8s %d %s\n", rc.RuleID, rc.Severity, rc.Count, plural(rc.Count, "process", "processes"))
+	}
+}
+
+// RenderMarkdown prints the report as a Markdown document for tickets and
+// review notes.
+func RenderMarkdown(w io.Writer, r *Report) {
+	outp := output.NewPrinter(w)
+	outp.Printf("# witr audit: %s\n\n", mdEscape(r.Hostname))
+	outp.Printf("Generated %s.\n", r.GeneratedAt.Format(stamp))
+
+	s := r.Summary
+	outp.Printf("\n## Summary\n\n")
+	outp.Printf("| | |\n|---|---|\n")
+	outp.Printf("| Processes | %d |\n", s.Processes)
+	outp.Printf("| Listeners | %d (%d on all interfaces) |\n", s.Listeners, s.PublicListeners)
+	outp.Printf("| Containers | %d |\n", s.Containers)
+	outp.Printf("| Unexplained listeners | %d |\n", len(s.Unexplained))
+	outp.Printf("| Unsupervised listeners | %d |\n", len(s.Unsupervised))
+	outp.Printf("| Warnings | %d |\n", r.WarningCount())
+
+	if len(s.Unexplained) > 0 || len(s.Unsupervised) > 0 {
+		outp.Printf("\n### Listeners needing review\n\n")
+		outp.Printf("| Listener | Process | Why |\n|---|---|---|\n")
+		for _, l := range s.Unexplained {
+			outp.Printf("| `%s` | %s (pid %d) | no known source |\n", l.Address, mdEscape(l.Process), l.PID)
+		}
+		for _, l := range s.Unsupervised {
+			outp.Printf("| `%s` | %s (pid %d) | started by hand from %s |\n", l.Address, mdEscape(l.Process), l.PID, mdEscape(sourcePhrase(l.Source)))
+		}
+	}
+	if len(s.Warnings) > 0 {
+		outp.Printf("\n### Warnings\n\n")
+		outp.Printf("| Rule | Severity | Processes | PIDs |\n|---|---|---|---|\n")
+		for _, rc := range s.Warnings {
+			outp.Printf("| `%s` | %s | %d | %s |\n", rc.RuleID, rc.Severity, rc.Count, joinInts(rc.PIDs))
+		}
+	}
+
+	for _, g := range r.Groups {
+		outp.Printf("\n## %s (%d)\n\n", mdEscape(g.Title), len(g.Entries))
+		outp.Printf("| Source | Process | Listeners | Ancestry | Warnings |\n|---|---|---|---|---|\n")
+		for _, e := range g.Entries {
+			var warnings []string
+			for _, f := range e.Result.Findings {
+				warnings = append(warnings, fmt.Sprintf("`%s` (%s)", f.RuleID, f.Severity))
+			}
+			outp.Printf("| %s | %s (pid %d) | %s | %s | %s |\n",
+				mdEscape(sourceName(e.Result.Source)),
+				mdEscape(e.Result.Process.Command), e.Result.Process.PID,
+				mdEscape(listenerList(e.Listeners)),
+				mdEscape(ancestry(e.Result.Ancestry)),
+				strings.Join(warnings, ", "))
+		}
+	}
+
+	if len(r.HiddenContainers) > 0 {
+		outp.Printf("\n## Containers not visible on the host (%d)\n\n", len(r.HiddenContainers))
+		for _, c := range r.HiddenContainers {
+			outp.Printf("- %s\n", mdEscape(output.FormatContainerLine(c)))
+		}
+	}
+	if len(r.OrphanListeners) > 0 {
+		outp.Printf("\n## Listeners with no visible owner (%d)\n\n", len(r.OrphanListeners))
+		for _, p := range r.OrphanListeners {
+			outp.Printf("- `%s`\n", listenAddress(p))
+		}
+		outp.Printf("\nRun as root to attribute them.\n")
+	}
+}
+
+// entryName labels an entry by its source name when it has one
+// ("nginx.service: nginx"), else by command.
+func entryName(e Entry) string {
+	cmd := e.Result.Process.Command
+	if n := e.Result.Source.Name; n != "" && n != cmd && n != string(e.Result.Source.Type) {
+		return n + ": " + cmd
+	}
+	return cmd
+}
+
+func sourceName(s model.Source) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return string(s.Type)
+}
+
+func sourcePhrase(s model.Source) string {
+	switch s.Type {
+	case model.SourceSSH:
+		return "an SSH session"
+	case model.SourceShell:
+		if s.Name != "" {
+			return "an interactive shell (" + s.Name + ")"
+		}
+		return "an interactive shell"
+	}
+	return sourceName(s)
+}
+
+func listenerList(ports []model.OpenPort) string {
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = listenAddress(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func ancestry(procs []model.Process) string {
+	parts := make([]string, len(procs))
+	for i, p := range procs {
+		parts[i] = output.ChainName(p)
+	}
+	return strings.Join(parts, " → ")
+}
+
+func joinInts(ns []int) string {
+	parts := make([]string, len(ns))
+	for i, n := range ns {
+		parts[i] = fmt.Sprint(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// mdEscape keeps process-controlled strings from breaking table cells or
+// injecting markup.
+func mdEscape(s string) string {
+	s = output.SanitizeTerminal(s)
+	r := strings.NewReplacer("|", `\|`, "\n", " ", "`", "'", "<", "&lt;", ">", "&gt;", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`)
+	return r.Replace(s)
+}
@@ -13,6 +13,9 @@ func containerSourceLabel(match *model.ContainerMatch) string {
 	if match.Runtime == "docker" && match.ComposeProject != "" && match.ComposeService != "" {
 		return fmt.Sprintf("docker-compose: %s/%s", match.ComposeProject, match.ComposeService)
 	}
+	if workload := match.Workload(); workload != "" {
+		return "kubernetes: " + workload
+	}
 	if match.Runtime != "" {
 		return match.Runtime
 	}
@@ -20,7 +23,7 @@ func containerSourceLabel(match *model.ContainerMatch) string {
 }
 
 // containerChain returns the conceptual ancestry segments for a container:
-// runtime → [compose project] → container.
+// runtime → [compose project | pod owner → pod] → container.
 func containerChain(match *model.ContainerMatch) []string {
 	runtime := match.Runtime
 	if runtime == "" {
@@ -30,6 +33,12 @@ func containerChain(match *model.ContainerMatch) []string {
 	if match.ComposeProject != "" {
 		segs = append(segs, match.ComposeProject+" (docker-compose)")
 	}
+	if match.PodOwnerKind != "" && match.PodOwnerKind != "Node" && match.PodOwnerName != "" {
+		segs = append(segs, match.PodOwnerName+" ("+match.PodOwnerKind+")")
+	}
+	if match.PodName != "" {
+		segs = append(segs, match.PodName+" (pod)")
+	}
 	segs = append(segs, match.Name)
 	return segs
 }
@@ -127,6 +136,22 @@ func RenderContainerFallback(w io.Writer, targetLabel string, match *model.Conta
 		}
 	}
 
+	if match.PodName != "" {
+		pod := match.PodName
+		if match.PodNamespace != "" {
+			pod = match.PodNamespace + "/" + pod
+		}
+		if match.PodQOSClass != "" {
+			pod += " (" + match.PodQOSClass + " QoS)"
+		}
+		pod = SanitizeTerminal(pod)
+		if colorEnabled {
+			out.Printf("%sPod%s         : %s\n", ColorBlue, ColorReset, pod)
+		} else {
+			out.Printf("Pod         : %s\n", pod)
+		}
+	}
+
 	if command != "" {
 		if colorEnabled {
 			out.Printf("%sCommand%s     : %s\n", ColorBlue, ColorReset, command)
@@ -305,6 +330,12 @@ func ContainerFallbackToJSON(targetLabel string, match *model.ContainerMatch) (s
 		ComposeService    string `json:",omitempty"`
 		ComposeConfigFile string `json:",omitempty"`
 		ComposeWorkingDir string `json:",omitempty"`
+		PodName           string `json:",omitempty"`
+		PodNamespace      string `json:",omitempty"`
+		PodUID            string `json:",omitempty"`
+		PodQOSClass       string `json:",omitempty"`
+		PodOwnerKind      string `json:",omitempty"`
+		PodOwnerName      string `json:",omitempty"`
 		Source            string
 		Chain             []string
 		Note              string
@@ -338,6 +369,12 @@ func ContainerFallbackToJSON(targetLabel string, match *model.ContainerMatch) (s
 		ComposeService:    match.ComposeService,
 		ComposeConfigFile: match.ComposeConfigFile,
 		ComposeWorkingDir: match.ComposeWorkingDir,
+		PodName:           match.PodName,
+		PodNamespace:      match.PodNamespace,
+		PodUID:            match.PodUID,
+		PodQOSClass:       match.PodQOSClass,
+		PodOwnerKind:      match.PodOwnerKind,
+		PodOwnerName:      match.PodOwnerName,
 		Source:            containerSourceLabel(match),
 		Chain:             containerChain(match),
 		Note:              "The owning process is not visible in this environment. This is common when the runtime runs in a separate namespace (e.g., Docker Desktop, WSL2 distro, macOS VM).",
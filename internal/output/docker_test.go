@@ -75,6 +75,34 @@ func TestRenderContainerFallbackRuntimeLabel(t *testing.T) {
 	}
 }
 
+func TestRenderContainerFallbackKubernetes(t *testing.T) {
+	match := &model.ContainerMatch{
+		Runtime:      "k8s",
+		ID:           "3f4e9b2a1c0d8e7f",
+		Name:         "nginx",
+		Image:        "nginx:1.27",
+		PodName:      "web-7d9c8b5f4-x2x4q",
+		PodNamespace: "shop",
+		PodQOSClass:  "Burstable",
+		PodOwnerKind: "Deployment",
+		PodOwnerName: "web",
+	}
+
+	var buf bytes.Buffer
+	RenderContainerFallback(&buf, "container nginx", match, false, false)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Pod         : shop/web-7d9c8b5f4-x2x4q (Burstable QoS)",
+		"k8s → web (Deployment) → web-7d9c8b5f4-x2x4q (pod) → nginx",
+		"Source      : kubernetes: Deployment shop/web",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderContainerFallback output missing %q\nGot:\n%s", want, out)
+		}
+	}
+}
+
 func TestRenderContainerFallbackShort(t *testing.T) {
 	match := &model.ContainerMatch{
 		Runtime: "docker",
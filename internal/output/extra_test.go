@@ -33,6 +33,16 @@ func TestRenderWarnings(t *testing.T) {
 			t.Errorf("expected the no-warnings message; got:\n%s", buf.String())
 		}
 	})
+
+	t.Run("findings show rule and severity", func(t *testing.T) {
+		r2 := r
+		r2.Findings = []model.Finding{{RuleID: "root", Severity: model.SeverityLow, Message: "Process is running as root"}}
+		var buf bytes.Buffer
+		RenderWarnings(&buf, r2, false)
+		if !strings.Contains(buf.String(), "Process is running as root [root low]") {
+			t.Errorf("expected the rule id and severity; got:\n%s", buf.String())
+		}
+	})
 }
 
 func TestFormatContainerLine(t *testing.T) {
@@ -65,6 +65,7 @@ func ToWarningsJSON(r model.Result) (string, error) {
 		Process  string
 		Command  string
 		Warnings []string
+		Findings []model.Finding
 	}
 
 	procName := "unknown"
@@ -84,11 +85,17 @@ func ToWarningsJSON(r model.Result) (string, error) {
 		warnings = []string{}
 	}
 
+	findings := r.Findings
+	if findings == nil {
+		findings = []model.Finding{}
+	}
+
 	res := warningResult{
 		PID:      r.Process.PID,
 		Process:  procName,
 		Command:  cmdLine,
 		Warnings: warnings,
+		Findings: findings,
 	}
 
 	data, err := json.MarshalIndent(res, "", "  ")
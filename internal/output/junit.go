@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ToJUnit renders the findings of a --warnings run as JUnit XML: a test suite
+// per target and, for each process it resolved to, a failed test case per
+// finding (named by rule ID, typed by severity) or a single passing one when
+// there are none. A target that couldn't be analyzed is an errored case.
+func ToJUnit(reports []WarningReport) (string, error) {
+	root := junitTestSuites{Name: "witr"}
+	for _, rep := range reports {
+		suite := junitTestSuite{Name: rep.Label}
+		if rep.Err != "" {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "analyze",
+				ClassName: "witr." + rep.Label,
+				Error:     &junitProblem{Message: rep.Err, Type: "error"},
+			})
+			suite.Errors++
+		}
+		for _, r := range rep.Results {
+			proc := reportProcess(r)
+			className := fmt.Sprintf("witr.%s.%s (pid %d)", rep.Label, ChainName(proc), proc.PID)
+			findings := resultFindings(r)
+			if len(findings) == 0 {
+				suite.Cases = append(suite.Cases, junitTestCase{Name: "no warnings", ClassName: className})
+				continue
+			}
+			context := strings.Join([]string{
+				"target: " + rep.Label,
+				fmt.Sprintf("pid: %d", proc.PID),
+				"exe: " + proc.Exe,
+				"ancestry: " + ancestryLine(r),
+				"source: " + sourceLine(r),
+			}, "\n")
+			for _, f := range findings {
+				suite.Cases = append(suite.Cases, junitTestCase{
+					Name:      f.RuleID,
+					ClassName: className,
+					Failure:   &junitProblem{Message: f.Message, Type: string(f.Severity), Body: context},
+				})
+				suite.Failures++
+			}
+		}
+		suite.Tests = len(suite.Cases)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.Suites = append(root.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// LoginResult is every process of a --user, --session or --tty target with
+// its analysis, in PID order.
+type LoginResult struct {
+	Target    model.Target
+	Processes []model.Result
+}
+
+// RenderLogin prints what the target matched, then the ancestries of all its
+// processes merged into one forest.
+func RenderLogin(w io.Writer, l LoginResult, colorEnabled bool) {
+	out := NewPrinter(w)
+	label := map[model.TargetType]string{
+		model.TargetUser:    "User",
+		model.TargetSession: "Session",
+		model.TargetTTY:     "TTY",
+	}[l.Target.Type]
+	value := SanitizeTerminal(l.Target.Value)
+	if colorEnabled {
+		out.Printf("%s%s%s%s: %s%s%s\n", ColorBlue, label, ColorReset, strings.Repeat(" ", 12-len(label)), ColorGreen, value, ColorReset)
+		out.Printf("%sProcesses%s   : %d\n", ColorCyan, ColorReset, len(l.Processes))
+	} else {
+		out.Printf("%s%s: %s\n", label, strings.Repeat(" ", 12-len(label)), value)
+		out.Printf("Processes   : %d\n", len(l.Processes))
+	}
+	out.Println()
+	RenderForest(w, l.Processes, colorEnabled)
+}
+
+// RenderForest prints the ancestries of several processes merged into one
+// tree, so a login shell, sshd or service manager shared by many of them is
+// shown once. The processes themselves are highlighted and annotated with
+// the source that explains them.
+func RenderForest(w io.Writer, results []model.Result, colorEnabled bool) {
+	out := NewPrinter(w)
+
+	procs := make(map[int]model.Process)
+	children := make(map[int][]int)
+	matched := make(map[int]model.Source)
+	var roots []int
+	for _, r := range results {
+		if len(r.Ancestry) == 0 {
+			continue
+		}
+		for i, p := range r.Ancestry {
+			if _, seen := procs[p.PID]; seen {
+				continue
+			}
+			procs[p.PID] = p
+			if i == 0 {
+				roots = append(roots, p.PID)
+			} else {
+				parent := r.Ancestry[i-1].PID
+				children[parent] = append(children[parent], p.PID)
+			}
+		}
+		matched[r.Ancestry[len(r.Ancestry)-1].PID] = r.Source
+	}
+	sort.Ints(roots)
+
+	var walk func(pid int, prefix string, connector string)
+	walk = func(pid int, prefix string, connector string) {
+		p := procs[pid]
+		name := SanitizeTerminal(ChainName(p))
+		src, isMatch := matched[pid]
+		annotation := ""
+		if isMatch && src.Type != "" && src.Type != model.SourceUnknown {
+			annotation = " [" + SourceLabel(src)
+			if src.Name != "" {
+				annotation += " " + src.Name
+			}
+			annotation = SanitizeTerminal(annotation + "]")
+		}
+		switch {
+		case colorEnabled && isMatch:
+			out.Printf("%s%s%s%s%s%s (%spid %d%s)%s%s%s\n", prefix, ColorMagenta, connector, ColorReset, ColorGreen, name, ColorDim, pid, ColorReset, ColorDim, annotation, ColorReset)
+		case colorEnabled:
+			out.Printf("%s%s%s%s%s (%spid %d%s)\n", prefix, ColorMagenta, connector, ColorReset, name, ColorDim, pid, ColorReset)
+		default:
+			out.Printf("%s%s%s (pid %d)%s\n", prefix, connector, name, pid, annotation)
+		}
+
+		kids := children[pid]
+		sort.Ints(kids)
+		childPrefix := prefix
+		switch connector {
+		case "├─ ":
+			childPrefix += "│  "
+		case "└─ ":
+			childPrefix += "   "
+		}
+		for i, kid := range kids {
+			if i == len(kids)-1 {
+				walk(kid, childPrefix, "└─ ")
+			} else {
+				walk(kid, childPrefix, "├─ ")
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+}
+
+// LoginToJSON renders the target and the full analysis of each process.
+func LoginToJSON(l LoginResult) (string, error) {
+	res := struct {
+		Target    model.Target
+		Processes []model.Result
+	}{Target: l.Target, Processes: l.Processes}
+	if res.Processes == nil {
+		res.Processes = []model.Result{}
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func loginFixture() LoginResult {
+	systemd := model.Process{PID: 1, Command: "systemd"}
+	sshd := model.Process{PID: 800, PPID: 1, Command: "sshd"}
+	session := model.Process{PID: 4100, PPID: 800, Command: "sshd"}
+	bash := model.Process{PID: 4102, PPID: 4100, Command: "bash"}
+	return LoginResult{
+		Target: model.Target{Type: model.TargetUser, Value: "alice"},
+		Processes: []model.Result{
+			{Ancestry: []model.Process{systemd, sshd, session}, Source: model.Source{Type: model.SourceSSH, Name: "sshd"}},
+			{Ancestry: []model.Process{systemd, sshd, session, bash}, Source: model.Source{Type: model.SourceShell, Name: "bash"}},
+			{Ancestry: []model.Process{systemd, sshd, session, bash, {PID: 4200, PPID: 4102, Command: "vim"}}, Source: model.Source{Type: model.SourceShell, Name: "bash"}},
+			{Ancestry: []model.Process{systemd, {PID: 5000, PPID: 1, Command: "node"}}, Source: model.Source{Type: model.SourceSystemd, Name: "app.service"}},
+		},
+	}
+}
+
+func TestRenderLogin(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	RenderLogin(&buf, loginFixture(), false)
+
+	want := strings.Join([]string{
+		"User        : alice",
+		"Processes   : 4",
+		"",
+		"systemd (pid 1)",
+		"├─ sshd (pid 800)",
+		"│  └─ sshd (pid 4100) [ssh sshd]",
+		"│     └─ bash (pid 4102) [shell bash]",
+		"│        └─ vim (pid 4200) [shell bash]",
+		"└─ node (pid 5000) [systemd app.service]",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("RenderLogin =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLoginToJSON(t *testing.T) {
+	t.Parallel()
+
+	got, err := LoginToJSON(LoginResult{Target: model.Target{Type: model.TargetTTY, Value: "pts/9"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Type": "tty"`, `"Processes": []`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("LoginToJSON lacks %s:\n%s", want, got)
+		}
+	}
+}
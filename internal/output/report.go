@@ -0,0 +1,55 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// WarningReport is what a --warnings run found for one target, the input to
+// ToSARIF and ToJUnit. Err is set when the target couldn't be analyzed.
+type WarningReport struct {
+	Label   string
+	Target  model.Target
+	Results []model.Result
+	Err     string
+}
+
+// reportProcess is the analyzed process of r.
+func reportProcess(r model.Result) model.Process {
+	if len(r.Ancestry) > 0 {
+		return r.Ancestry[len(r.Ancestry)-1]
+	}
+	return r.Process
+}
+
+// ancestryLine renders r's ancestry as "systemd (pid 1) → nginx (pid 812)".
+func ancestryLine(r model.Result) string {
+	parts := make([]string, len(r.Ancestry))
+	for i, p := range r.Ancestry {
+		parts[i] = fmt.Sprintf("%s (pid %d)", ChainName(p), p.PID)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// sourceLine renders r's source as "systemd nginx.service".
+func sourceLine(r model.Result) string {
+	if r.Source.Name != "" && r.Source.Name != string(r.Source.Type) {
+		return fmt.Sprintf("%s %s", SourceLabel(r.Source), r.Source.Name)
+	}
+	return SourceLabel(r.Source)
+}
+
+// resultFindings returns r's findings, or unranked ones built from its
+// warnings when it carries none.
+func resultFindings(r model.Result) []model.Finding {
+	if len(r.Findings) > 0 || len(r.Warnings) == 0 {
+		return r.Findings
+	}
+	findings := make([]model.Finding, len(r.Warnings))
+	for i, w := range r.Warnings {
+		findings[i] = model.Finding{RuleID: "warning", Severity: model.SeverityMedium, Message: w}
+	}
+	return findings
+}
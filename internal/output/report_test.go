@@ -0,0 +1,125 @@
+package output
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func reportFixture() []WarningReport {
+	nginx := model.Process{PID: 812, Command: "nginx", Exe: "/usr/sbin/nginx", User: "root"}
+	return []WarningReport{
+		{
+			Label:  "name: nginx",
+			Target: model.Target{Type: model.TargetName, Value: "nginx"},
+			Results: []model.Result{{
+				Process:  nginx,
+				Ancestry: []model.Process{{PID: 1, Command: "systemd"}, nginx},
+				Source:   model.Source{Type: model.SourceSystemd, Name: "nginx.service"},
+				Warnings: []string{"Process is running as root", "Process is listening on a public interface"},
+				Findings: []model.Finding{
+					{RuleID: "root", Severity: model.SeverityLow, Message: "Process is running as root"},
+					{RuleID: "public-bind", Severity: model.SeverityHigh, Message: "Process is listening on a public interface"},
+				},
+			}},
+		},
+		{
+			Label:   "pid: 7",
+			Target:  model.Target{Type: model.TargetPID, Value: "7"},
+			Results: []model.Result{{Process: model.Process{PID: 7, Command: "sshd"}, Ancestry: []model.Process{{PID: 7, Command: "sshd"}}}},
+		},
+		{Label: "port: 1", Target: model.Target{Type: model.TargetPort, Value: "1"}, Err: "no process listening on port 1"},
+	}
+}
+
+func TestToSARIF(t *testing.T) {
+	describe := func(id string) string {
+		return map[string]string{"root": "Process is running as root"}[id]
+	}
+	s, err := ToSARIF(reportFixture(), "v1.2.3", describe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(s), &log); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, s)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("rules = %d, results = %d; want 2 and 2", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+	// Rules are described by describe, not by a finding's message.
+	for i, want := range []string{"Process is running as root", "public-bind"} {
+		if got := run.Tool.Driver.Rules[i].ShortDescription.Text; got != want {
+			t.Errorf("rule %s description = %q, want %q", run.Tool.Driver.Rules[i].ID, got, want)
+		}
+	}
+
+	res := run.Results[1]
+	if res.RuleID != "public-bind" || res.Level != "error" {
+		t.Errorf("result = %s/%s, want public-bind/error", res.RuleID, res.Level)
+	}
+	if loc := res.Locations[0]; loc.PhysicalLocation == nil || loc.PhysicalLocation.ArtifactLocation.URI != "file:///usr/sbin/nginx" {
+		t.Errorf("location = %+v, want the executable", loc)
+	}
+	p := res.Properties
+	if p.Target != "name: nginx" || p.PID != 812 || p.Source != "systemd nginx.service" || !strings.Contains(p.Ancestry, "systemd (pid 1) → nginx (pid 812)") {
+		t.Errorf("properties = %+v", p)
+	}
+	if res.PartialFingerprints["witrFinding/v1"] == run.Results[0].PartialFingerprints["witrFinding/v1"] {
+		t.Error("different rules share a fingerprint")
+	}
+
+	inv := run.Invocations[0]
+	if inv.ExecutionSuccessful || len(inv.ToolExecutionNotifications) != 1 || !strings.Contains(inv.ToolExecutionNotifications[0].Message.Text, "port: 1") {
+		t.Errorf("invocation = %+v, want the failed target reported", inv)
+	}
+}
+
+func TestToSARIFNoFindings(t *testing.T) {
+	s, err := ToSARIF(nil, "", func(string) string { return "" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Uploaders reject a null results array.
+	if !strings.Contains(s, `"results": []`) || !strings.Contains(s, `"rules": []`) {
+		t.Errorf("empty report should carry empty arrays:\n%s", s)
+	}
+}
+
+func TestToJUnit(t *testing.T) {
+	s, err := ToJUnit(reportFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(s, "<?xml") {
+		t.Errorf("missing XML header:\n%s", s)
+	}
+	var root junitTestSuites
+	if err := xml.Unmarshal([]byte(s), &root); err != nil {
+		t.Fatalf("not XML: %v\n%s", err, s)
+	}
+	if root.Tests != 4 || root.Failures != 2 || root.Errors != 1 || len(root.Suites) != 3 {
+		t.Fatalf("totals = %d tests, %d failures, %d errors, %d suites", root.Tests, root.Failures, root.Errors, len(root.Suites))
+	}
+
+	c := root.Suites[0].Cases[1]
+	if c.Name != "public-bind" || c.ClassName != "witr.name: nginx.nginx (pid 812)" || c.Failure == nil || c.Failure.Type != "high" {
+		t.Errorf("case = %+v", c)
+	}
+	if !strings.Contains(c.Failure.Body, "exe: /usr/sbin/nginx") || !strings.Contains(c.Failure.Body, "source: systemd nginx.service") {
+		t.Errorf("failure body lacks context: %q", c.Failure.Body)
+	}
+	if pass := root.Suites[1].Cases[0]; pass.Failure != nil || pass.Name != "no warnings" {
+		t.Errorf("a clean process should pass, got %+v", pass)
+	}
+	if e := root.Suites[2].Cases[0].Error; e == nil || e.Message != "no process listening on port 1" {
+		t.Errorf("failed target should be an errored case, got %+v", root.Suites[2].Cases[0])
+	}
+}
@@ -0,0 +1,221 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// SARIF 2.1.0, trimmed to the properties code-scanning dashboards read.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfig   `json:"defaultConfiguration"`
+	Properties           map[string]string `json:"properties"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifProperties struct {
+	Target   string         `json:"target"`
+	PID      int            `json:"pid"`
+	Exe      string         `json:"exe,omitempty"`
+	Ancestry string         `json:"ancestry"`
+	Source   string         `json:"source"`
+	Severity model.Severity `json:"severity"`
+}
+
+// sarifLevel maps a severity onto SARIF's three result levels.
+func sarifLevel(s model.Severity) string {
+	switch {
+	case s.Rank() >= model.SeverityHigh.Rank():
+		return "error"
+	case s == model.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// securitySeverity is the CVSS-style score GitHub code scanning ranks
+// alerts by.
+var securitySeverity = map[model.Severity]string{
+	model.SeverityInfo:     "1.0",
+	model.SeverityLow:      "3.0",
+	model.SeverityMedium:   "5.5",
+	model.SeverityHigh:     "8.0",
+	model.SeverityCritical: "9.5",
+}
+
+// ToSARIF renders the findings of a --warnings run as a SARIF 2.1.0 log.
+// Each finding is a result located at the process's executable, with the
+// target label, PID, ancestry and source as context; targets that failed are
+// tool notifications. describe gives each rule's description, the same for
+// every finding of it; a rule it doesn't know is described by its ID.
+func ToSARIF(reports []WarningReport, version string, describe func(ruleID string) string) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "witr",
+			Version:        version,
+			InformationURI: "https://github.com/pranshuparmar/witr",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	seenRules := make(map[string]bool)
+
+	for _, rep := range reports {
+		if rep.Err != "" {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("%s: %s", rep.Label, rep.Err)},
+			})
+			continue
+		}
+		for _, r := range rep.Results {
+			proc := reportProcess(r)
+			for _, f := range resultFindings(r) {
+				if !seenRules[f.RuleID] {
+					seenRules[f.RuleID] = true
+					description := describe(f.RuleID)
+					if description == "" {
+						description = f.RuleID
+					}
+					run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+						ID:                   f.RuleID,
+						ShortDescription:     sarifMessage{Text: description},
+						DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(f.Severity)},
+						Properties:           map[string]string{"security-severity": securitySeverity[f.Severity]},
+					})
+				}
+				run.Results = append(run.Results, sarifResult{
+					RuleID:  f.RuleID,
+					Level:   sarifLevel(f.Severity),
+					Message: sarifMessage{Text: fmt.Sprintf("%s (%s, pid %d)", f.Message, ChainName(proc), proc.PID)},
+					Locations: []sarifLocation{{
+						PhysicalLocation: exeLocation(proc.Exe),
+						LogicalLocations: []sarifLogicalLocation{{
+							Name:               fmt.Sprintf("%s (pid %d)", ChainName(proc), proc.PID),
+							FullyQualifiedName: ancestryLine(r),
+							Kind:               "process",
+						}},
+					}},
+					// PIDs change across runs; the rule, target and binary
+					// identify the same alert.
+					PartialFingerprints: map[string]string{"witrFinding/v1": fingerprint(f.RuleID, rep.Label, proc.Exe, ChainName(proc))},
+					Properties: sarifProperties{
+						Target:   rep.Label,
+						PID:      proc.PID,
+						Exe:      proc.Exe,
+						Ancestry: ancestryLine(r),
+						Source:   sourceLine(r),
+						Severity: f.Severity,
+					},
+				})
+			}
+		}
+	}
+	run.Invocations = []sarifInvocation{invocation}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// exeLocation returns exe as a file URI location, or nil when unknown.
+func exeLocation(exe string) *sarifPhysicalLocation {
+	if exe == "" || !filepath.IsAbs(exe) {
+		return nil
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(exe)}
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path // Windows drive paths
+	}
+	return &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: u.String()}}
+}
+
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
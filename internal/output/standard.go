@@ -19,6 +19,27 @@ var detailLabels = map[string]string{
 	"plist":     "              Plist",
 	"triggers":  "              Trigger",
 	"keepalive": "              KeepAlive",
+	"restart":   "              Restart",
+	"detach":    "              Detached",
+	"pod":       "              Pod",
+	"container": "              Container",
+	"qos":       "              QoS Class",
+	"session":   "              Session",
+	"job":       "              Job",
+	"url":       "              URL",
+	"matched":   "              Matched",
+	"detector":  "              Detector",
+	"transient": "              Transient",
+	"exec":      "              ExecStart",
+}
+
+// SourceLabel names a source's type for display: "systemd --user" for a unit
+// of a per-user service manager, else the type itself.
+func SourceLabel(s model.Source) string {
+	if s.Type == model.SourceSystemd && s.Details["manager"] == "user" {
+		return "systemd --user"
+	}
+	return string(s.Type)
 }
 
 func formatDetailLabel(key string) string {
@@ -65,14 +86,34 @@ func RenderWarnings(w io.Writer, r model.Result, colorEnabled bool) {
 
 	if colorEnabled {
 		out.Printf("%sWarnings%s    :\n", ColorRed, ColorReset)
-		for _, w := range r.Warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
-		}
 	} else {
 		out.Println("Warnings    :")
+	}
+	if len(r.Findings) == 0 {
 		for _, w := range r.Warnings {
 			out.Printf("  • %s\n", SanitizeTerminal(w))
 		}
+		return
+	}
+	// Show the rule and severity so allow-lists and CI gates can be written
+	// against them.
+	for _, f := range r.Findings {
+		if colorEnabled {
+			out.Printf("  • %s %s[%s %s]%s\n", SanitizeTerminal(f.Message), severityColor(f.Severity), f.RuleID, f.Severity, ColorReset)
+		} else {
+			out.Printf("  • %s [%s %s]\n", SanitizeTerminal(f.Message), f.RuleID, f.Severity)
+		}
+	}
+}
+
+func severityColor(s model.Severity) ansiString {
+	switch {
+	case s.Rank() >= model.SeverityHigh.Rank():
+		return ColorRed
+	case s == model.SeverityMedium:
+		return ColorDimYellow
+	default:
+		return ColorDim
 	}
 }
 
@@ -169,8 +210,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		out.Printf("Started     : %s (%s)\n", rel, dtStr)
 	}
 
-	// Restart count (sourced from systemd's NRestarts); shown only when the
-	// managing system has restarted the unit at least once.
+	// Restart count (sourced from systemd's NRestarts, or pm2's restart
+	// counter); shown only when the manager has restarted it at least once.
 	if r.RestartCount > 0 {
 		if colorEnabled {
 			out.Printf("%sRestarts%s    : %d\n", ColorMagenta, ColorReset, r.RestartCount)
@@ -216,7 +257,7 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 	}
 
 	// Source
-	sourceLabel := string(r.Source.Type)
+	sourceLabel := SourceLabel(r.Source)
 	sourceName := SanitizeTerminal(r.Source.Name)
 	if colorEnabled {
 		if r.Source.Name != "" && r.Source.Name != sourceLabel {
@@ -251,6 +292,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			label = "Registry Key"
 		case model.SourceBsdRc:
 			label = "Rc Script"
+		case model.SourceCron:
+			label = "Job File"
 		}
 
 		var pad string
@@ -267,10 +310,74 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	// Activation chain: the target or trigger that pulled a systemd unit in.
+	if chain, ok := r.Source.Details["activation"]; ok {
+		if colorEnabled {
+			out.Printf("%sActivation%s  : %s\n", ColorCyan, ColorReset, SanitizeTerminal(chain))
+		} else {
+			out.Printf("Activation  : %s\n", SanitizeTerminal(chain))
+		}
+	}
+
+	// Socket activation: systemd holds the port and starts the service on demand.
+	if r.SocketInfo != nil && r.SocketInfo.Activation != nil {
+		line := SanitizeTerminal(formatSocketActivation(r.SocketInfo.Activation))
+		if colorEnabled {
+			out.Printf("%sSocket Unit%s : %s\n", ColorCyan, ColorReset, line)
+		} else {
+			out.Printf("Socket Unit : %s\n", line)
+		}
+	}
+
+	// A port found in another network namespace than witr's own.
+	if r.SocketInfo != nil && r.SocketInfo.Namespace != nil {
+		ns := SanitizeTerminal(formatNetNamespace(r.SocketInfo.Namespace))
+		if colorEnabled {
+			out.Printf("%sNetwork NS%s  : %s\n", ColorCyan, ColorReset, ns)
+		} else {
+			out.Printf("Network NS  : %s\n", ns)
+		}
+	}
+
+	// Unix socket: who holds it and who is connected to it.
+	if us := r.UnixSocket; us != nil {
+		sock := SanitizeTerminal(formatUnixSocket(us))
+		if colorEnabled {
+			out.Printf("%sUnix Socket%s : %s\n", ColorCyan, ColorReset, sock)
+		} else {
+			out.Printf("Unix Socket : %s\n", sock)
+		}
+		if len(us.Peers) > 0 {
+			peers := SanitizeTerminal(formatUnixPeers(us.Peers))
+			if colorEnabled {
+				out.Printf("%sConnected%s   : %s\n", ColorCyan, ColorReset, peers)
+			} else {
+				out.Printf("Connected   : %s\n", peers)
+			}
+		}
+	}
+
+	// Connections to a --remote endpoint.
+	for i, c := range r.Connections {
+		if i >= MaxDisplayItems {
+			out.Printf("              ... and %d more\n", len(r.Connections)-i)
+			break
+		}
+		line := SanitizeTerminal(formatConnection(c))
+		switch {
+		case i == 0 && colorEnabled:
+			out.Printf("%sConnection%s  : %s\n", ColorCyan, ColorReset, line)
+		case i == 0:
+			out.Printf("Connection  : %s\n", line)
+		default:
+			out.Printf("              %s\n", line)
+		}
+	}
+
 	// Source details (launchd triggers, plist path, etc.)
 	if len(r.Source.Details) > 0 {
 		// Display in consistent order
-		detailKeys := []string{"type", "plist", "triggers", "keepalive"}
+		detailKeys := []string{"type", "plist", "triggers", "keepalive", "restart", "detach", "session", "pod", "container", "qos", "transient", "exec", "job", "url", "matched", "detector"}
 		for _, key := range detailKeys {
 			if val, ok := r.Source.Details[key]; ok {
 				label := formatDetailLabel(key)
@@ -570,6 +677,94 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 	}
 }
 
+// formatSocketActivation summarizes a systemd .socket unit and the service it
+// starts, e.g. "nginx.socket on [::]:80 → nginx.service (active)".
+func formatSocketActivation(a *model.SocketActivation) string {
+	s := a.SocketUnit
+	if len(a.Listen) > 0 {
+		s += " on " + strings.Join(a.Listen, ", ")
+	}
+	if a.Service == "" {
+		return s
+	}
+	s += " → " + a.Service
+	if a.Accept {
+		return s + fmt.Sprintf(" per connection (%d running)", len(a.Instances))
+	}
+	state := a.ServiceState
+	if state == "" {
+		state = "unknown"
+	}
+	if !a.Activated() {
+		state += ", starts on first connection"
+	}
+	return s + " (" + state + ")"
+}
+
+// formatNetNamespace names a network namespace by what it belongs to, e.g.
+// "container redis, netns blue (net:[4026532345])".
+func formatNetNamespace(ns *model.NetNamespace) string {
+	var owners []string
+	if ns.Container != "" {
+		owners = append(owners, "container "+ns.Container)
+	}
+	if ns.Name != "" {
+		owners = append(owners, "netns "+ns.Name)
+	}
+	id := "net:[" + ns.Inode + "]"
+	if len(owners) == 0 {
+		return fmt.Sprintf("%s (first pid %d)", id, ns.PID)
+	}
+	return strings.Join(owners, ", ") + " (" + id + ")"
+}
+
+// formatUnixSocket describes a --socket target, e.g.
+// "/run/docker.sock (stream, listening)".
+func formatUnixSocket(us *model.UnixSocketInfo) string {
+	var parts []string
+	if us.Type != "" {
+		parts = append(parts, us.Type)
+	}
+	switch us.State {
+	case "LISTEN":
+		parts = append(parts, "listening")
+	case "":
+	default:
+		parts = append(parts, strings.ToLower(us.State))
+	}
+	if len(parts) == 0 {
+		return us.Path
+	}
+	return us.Path + " (" + strings.Join(parts, ", ") + ")"
+}
+
+// formatUnixPeers lists the processes connected to a unix socket, up to
+// MaxDisplayItems of them.
+func formatUnixPeers(peers []model.UnixPeer) string {
+	var names []string
+	for i, p := range peers {
+		if i == MaxDisplayItems {
+			names = append(names, fmt.Sprintf("and %d more", len(peers)-i))
+			break
+		}
+		names = append(names, fmt.Sprintf("%s (pid %d)", ChainName(model.Process{PID: p.PID, Command: p.Command}), p.PID))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatConnection renders a connection as
+// "<local> → <remote> (<PROTO> | <STATE>)".
+func formatConnection(s model.Socket) string {
+	proto := s.Protocol
+	if proto == "" {
+		proto = "?"
+	}
+	return fmt.Sprintf("%s → %s (%s | %s)",
+		net.JoinHostPort(s.Address, strconv.Itoa(s.Port)),
+		net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort)),
+		proto, displayState(s.State))
+}
+
 // formatBytes renders a byte count with a binary unit (KB/MB/GB/...), keeping
 // large values readable. Values below 1 KB are shown in bytes.
 func formatBytes(n uint64) string {
@@ -595,7 +790,17 @@ func formatSocket(s model.Socket) string {
 		proto = "?"
 	}
 	state := displayState(s.State)
-	return fmt.Sprintf("%s (%s | %s)", hostPort, proto, state)
+	line := fmt.Sprintf("%s (%s | %s)", hostPort, proto, state)
+	if s.PeerPID == 0 {
+		return line
+	}
+	// A connection between two local processes: name the one at the other end.
+	peer := fmt.Sprintf("pid %d", s.PeerPID)
+	if s.PeerSource != "" {
+		peer += ", " + s.PeerSource
+	}
+	name := ChainName(model.Process{PID: s.PeerPID, Command: s.PeerCommand})
+	return fmt.Sprintf("%s → %s %s (%s)", line, net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort)), name, peer)
 }
 
 // displayState pretty-prints socket states. The kernel-style "LISTEN" reads
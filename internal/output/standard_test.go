@@ -1,7 +1,9 @@
 package output
 
 import (
+	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -64,6 +66,12 @@ func TestFormatSocket(t *testing.T) {
 			s:    model.Socket{Address: "127.0.0.1", Port: 43525, Protocol: "TCP", State: "ESTABLISHED"},
 			want: "127.0.0.1:43525 (TCP | ESTABLISHED)",
 		},
+		{
+			name: "established to a local peer",
+			s: model.Socket{Address: "127.0.0.1", Port: 41234, Protocol: "TCP", State: "ESTABLISHED",
+				RemoteAddress: "127.0.0.1", RemotePort: 6432, PeerPID: 812, PeerCommand: "pgbouncer", PeerSource: "systemd pgbouncer.service"},
+			want: "127.0.0.1:41234 (TCP | ESTABLISHED) → 127.0.0.1:6432 pgbouncer (pid 812, systemd pgbouncer.service)",
+		},
 		{
 			name: "ipv6 wraps host:port via JoinHostPort",
 			s:    model.Socket{Address: "::1", Port: 8080, Protocol: "TCP6", State: "LISTEN"},
@@ -174,3 +182,149 @@ func TestSortSocketsStableWithinSameRank(t *testing.T) {
 		t.Errorf("expected lower port first within same address; got %+v", in)
 	}
 }
+
+func TestRenderStandardActivation(t *testing.T) {
+	t.Parallel()
+
+	p := model.Process{PID: 812, PPID: 1, Command: "nginx"}
+	r := model.Result{
+		Process:  p,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, p},
+		Source: model.Source{Type: model.SourceSystemd, Name: "nginx.service", Details: map[string]string{
+			"activation": "sockets.target → nginx.socket → nginx.service",
+		}},
+	}
+	var buf bytes.Buffer
+	RenderStandard(&buf, r, false, false)
+	if want := "Activation  : sockets.target → nginx.socket → nginx.service\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("standard output lacks %q:\n%s", want, buf.String())
+	}
+}
+
+func TestRenderStandardUserTransientUnit(t *testing.T) {
+	t.Parallel()
+
+	p := model.Process{PID: 4242, PPID: 1700, Command: "sleep"}
+	r := model.Result{
+		Process:  p,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 1700, PPID: 1, Command: "systemd"}, p},
+		Source: model.Source{Type: model.SourceSystemd, Name: "run-u12.service", Description: "/usr/bin/sleep 600", Details: map[string]string{
+			"manager": "user", "uid": "1000", "transient": "systemd-run", "exec": "/usr/bin/sleep 600",
+		}},
+	}
+	var buf bytes.Buffer
+	RenderStandard(&buf, r, false, false)
+	for _, want := range []string{
+		"Source      : run-u12.service (systemd --user)\n",
+		"Transient : systemd-run\n",
+		"ExecStart : /usr/bin/sleep 600\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("standard output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFormatSocketActivation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    model.SocketActivation
+		want string
+	}{
+		{
+			"running service",
+			model.SocketActivation{SocketUnit: "nginx.socket", Listen: []string{"[::]:80"}, Service: "nginx.service", ServiceState: "active", ServicePID: 812},
+			"nginx.socket on [::]:80 → nginx.service (active)",
+		},
+		{
+			"not started yet",
+			model.SocketActivation{SocketUnit: "cups.socket", Listen: []string{"127.0.0.1:631"}, Service: "cups.service", ServiceState: "inactive"},
+			"cups.socket on 127.0.0.1:631 → cups.service (inactive, starts on first connection)",
+		},
+		{
+			"accept per connection",
+			model.SocketActivation{SocketUnit: "sshd.socket", Listen: []string{"[::]:22"}, Service: "sshd@.service", Accept: true, Instances: []string{"sshd@3-10.0.0.1:22-10.0.0.9:51234.service"}},
+			"sshd.socket on [::]:22 → sshd@.service per connection (1 running)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := formatSocketActivation(&tt.a); got != tt.want {
+				t.Errorf("formatSocketActivation = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStandardUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	p := model.Process{PID: 900, PPID: 1, Command: "dockerd"}
+	r := model.Result{
+		Process:  p,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, p},
+		Source:   model.Source{Type: model.SourceSystemd, Name: "docker.service"},
+		UnixSocket: &model.UnixSocketInfo{
+			Path: "/run/docker.sock", Type: "stream", State: "LISTEN", Owners: []int{900},
+			Peers: []model.UnixPeer{{PID: 4242, Command: "docker"}, {PID: 5150, Command: "containerd"}},
+		},
+	}
+	var buf bytes.Buffer
+	RenderStandard(&buf, r, false, false)
+	for _, want := range []string{
+		"Unix Socket : /run/docker.sock (stream, listening)\n",
+		"Connected   : docker (pid 4242), containerd (pid 5150)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("standard output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRenderStandardConnections(t *testing.T) {
+	t.Parallel()
+
+	p := model.Process{PID: 900, PPID: 1, Command: "api"}
+	r := model.Result{
+		Process:  p,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, p},
+		Source:   model.Source{Type: model.SourceSystemd, Name: "api.service"},
+		Connections: []model.Socket{
+			{Address: "10.0.0.2", Port: 51234, RemoteAddress: "10.0.0.5", RemotePort: 5432, Protocol: "TCP", State: "ESTABLISHED"},
+			{Address: "fd00::2", Port: 40000, RemoteAddress: "fd00::5", RemotePort: 5432, Protocol: "TCP6", State: "CLOSE_WAIT"},
+		},
+	}
+	var buf bytes.Buffer
+	RenderStandard(&buf, r, false, false)
+	for _, want := range []string{
+		"Connection  : 10.0.0.2:51234 → 10.0.0.5:5432 (TCP | ESTABLISHED)\n",
+		"              [fd00::2]:40000 → [fd00::5]:5432 (TCP6 | CLOSE_WAIT)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("standard output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFormatNetNamespace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ns   model.NetNamespace
+		want string
+	}{
+		{model.NetNamespace{Inode: "4026532345", PID: 300, Container: "redis"}, "container redis (net:[4026532345])"},
+		{model.NetNamespace{Inode: "4026532345", PID: 300, Name: "blue"}, "netns blue (net:[4026532345])"},
+		{model.NetNamespace{Inode: "4026532345", PID: 300, Container: "redis", Name: "blue"}, "container redis, netns blue (net:[4026532345])"},
+		{model.NetNamespace{Inode: "4026532345", PID: 300}, "net:[4026532345] (first pid 300)"},
+	}
+	for _, tt := range tests {
+		if got := formatNetNamespace(&tt.ns); got != tt.want {
+			t.Errorf("formatNetNamespace(%+v) = %q, want %q", tt.ns, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// UnitResult is a systemd unit with the analysis of each of its processes,
+// in the order of Unit.Members.
+type UnitResult struct {
+	Unit      model.Unit
+	Processes []model.Result
+}
+
+// Roles returns the role of each process, parallel to Processes.
+func (u UnitResult) Roles() []model.UnitRole {
+	roles := make(map[int]model.UnitRole, len(u.Unit.Members))
+	for _, m := range u.Unit.Members {
+		roles[m.PID] = m.Role
+	}
+	out := make([]model.UnitRole, len(u.Processes))
+	for i, r := range u.Processes {
+		out[i] = roles[r.Process.PID]
+	}
+	return out
+}
+
+// RenderUnit prints the unit's own explanation once, then each of its
+// processes with its role in the unit.
+func RenderUnit(w io.Writer, u UnitResult, colorEnabled bool) {
+	out := NewPrinter(w)
+	field := func(color ansiString, label, value string) {
+		if value == "" {
+			return
+		}
+		pad := " "
+		if len(label) < 12 {
+			pad = strings.Repeat(" ", 12-len(label))
+		}
+		if colorEnabled {
+			out.Printf("%s%s%s%s: %s\n", color, label, ColorReset, pad, SanitizeTerminal(value))
+		} else {
+			out.Printf("%s%s: %s\n", label, pad, SanitizeTerminal(value))
+		}
+	}
+
+	RenderUnitHeader(w, u.Unit, colorEnabled)
+	field(ColorCyan, "Processes", fmt.Sprint(len(u.Processes)))
+
+	roles := u.Roles()
+	for i, r := range u.Processes {
+		out.Println()
+		proc := reportProcess(r)
+		renderUnitMemberLine(out, roles[i], proc, colorEnabled)
+		if proc.Cmdline != "" {
+			field(ColorBlue, "  Command", proc.Cmdline)
+		}
+		if proc.User != "" && proc.User != "unknown" {
+			field(ColorBlue, "  User", proc.User)
+		}
+		rel, dtStr := FormatStartedAt(proc.StartedAt)
+		field(ColorMagenta, "  Started", fmt.Sprintf("%s (%s)", rel, dtStr))
+		if roles[i] != model.UnitRoleMain && len(r.Ancestry) > 1 {
+			parent := r.Ancestry[len(r.Ancestry)-2]
+			field(ColorMagenta, "  Parent", fmt.Sprintf("%s (pid %d)", ChainName(parent), parent.PID))
+		}
+		visible := visibleSockets(proc.Sockets)
+		sortSockets(visible)
+		for j, s := range visible {
+			if j >= MaxDisplayItems {
+				out.Printf("              ... and %d more\n", len(visible)-j)
+				break
+			}
+			if j == 0 {
+				field(ColorGreen, "  Sockets", formatSocket(s))
+			} else {
+				out.Printf("              %s\n", SanitizeTerminal(formatSocket(s)))
+			}
+		}
+		warnings := r.Warnings
+		if len(r.Findings) > 0 {
+			warnings = make([]string, len(r.Findings))
+			for j, f := range r.Findings {
+				warnings[j] = fmt.Sprintf("%s [%s %s]", f.Message, f.RuleID, f.Severity)
+			}
+		}
+		for j, warning := range warnings {
+			if j == 0 {
+				field(ColorRed, "  Warnings", warning)
+			} else {
+				out.Printf("              %s\n", SanitizeTerminal(warning))
+			}
+		}
+	}
+}
+
+// RenderUnitHeader prints what systemd says about the unit: its state,
+// description, unit file, what pulls it in and what triggers it.
+func RenderUnitHeader(w io.Writer, u model.Unit, colorEnabled bool) {
+	out := NewPrinter(w)
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		pad := strings.Repeat(" ", 12-len(label))
+		if colorEnabled {
+			out.Printf("%s%s%s%s: %s\n", ColorCyan, label, ColorReset, pad, SanitizeTerminal(value))
+		} else {
+			out.Printf("%s%s: %s\n", label, pad, SanitizeTerminal(value))
+		}
+	}
+
+	name := SanitizeTerminal(u.Name)
+	state := u.ActiveState
+	if u.SubState != "" && u.SubState != u.ActiveState {
+		state += ", " + u.SubState
+	}
+	switch {
+	case colorEnabled && state != "":
+		out.Printf("%sUnit%s        : %s%s%s (%s)\n", ColorBlue, ColorReset, ColorGreen, name, ColorReset, SanitizeTerminal(state))
+	case colorEnabled:
+		out.Printf("%sUnit%s        : %s%s%s\n", ColorBlue, ColorReset, ColorGreen, name, ColorReset)
+	case state != "":
+		out.Printf("Unit        : %s (%s)\n", name, SanitizeTerminal(state))
+	default:
+		out.Printf("Unit        : %s\n", name)
+	}
+	field("Description", u.Description)
+	field("Unit File", u.UnitFile)
+	field("Wanted By", strings.Join(u.WantedBy, ", "))
+	field("Required By", strings.Join(u.RequiredBy, ", "))
+	field("Triggered By", strings.Join(u.TriggeredBy, ", "))
+	field("Activation", strings.Join(u.Activation, " → "))
+	field("Schedule", u.Schedule)
+}
+
+// RenderUnitMember prints the "[role] command (pid N)" line that heads each
+// process of a unit in the short, tree and warnings views.
+func RenderUnitMember(w io.Writer, role model.UnitRole, r model.Result, colorEnabled bool) {
+	renderUnitMemberLine(NewPrinter(w), role, reportProcess(r), colorEnabled)
+}
+
+func renderUnitMemberLine(out Printer, role model.UnitRole, proc model.Process, colorEnabled bool) {
+	name := SanitizeTerminal(ChainName(proc))
+	if colorEnabled {
+		out.Printf("%s[%s]%s %s%s%s (%spid %d%s)\n", ColorMagenta, role, ColorReset, ColorGreen, name, ColorReset, ColorDim, proc.PID, ColorReset)
+	} else {
+		out.Printf("[%s] %s (pid %d)\n", role, name, proc.PID)
+	}
+}
+
+// UnitToJSON renders a unit and its processes, each process annotated with
+// its role.
+func UnitToJSON(u UnitResult) (string, error) {
+	type unitProcess struct {
+		Role model.UnitRole
+		model.Result
+	}
+	res := struct {
+		Unit      model.Unit
+		Processes []unitProcess
+	}{Unit: u.Unit, Processes: []unitProcess{}}
+	roles := u.Roles()
+	for i, r := range u.Processes {
+		res.Processes = append(res.Processes, unitProcess{Role: roles[i], Result: r})
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
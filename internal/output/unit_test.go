@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func unitFixture() UnitResult {
+	initProc := model.Process{PID: 1, Command: "systemd"}
+	main := model.Process{PID: 812, PPID: 1, Command: "nginx", Cmdline: "nginx: master process /usr/sbin/nginx", User: "root",
+		Sockets: []model.Socket{{Protocol: "TCP", Address: "0.0.0.0", Port: 80, State: "LISTEN"}}}
+	worker := model.Process{PID: 813, PPID: 812, Command: "nginx", Cmdline: "nginx: worker process", User: "www-data"}
+	return UnitResult{
+		Unit: model.Unit{
+			Name:        "nginx.service",
+			Description: "A high performance web server",
+			UnitFile:    "/lib/systemd/system/nginx.service",
+			ActiveState: "active",
+			SubState:    "running",
+			WantedBy:    []string{"multi-user.target"},
+			TriggeredBy: []string{"nginx.socket"},
+			Activation:  []string{"sockets.target", "nginx.socket", "nginx.service"},
+			MainPID:     812,
+			Members:     []model.UnitMember{{PID: 812, Role: model.UnitRoleMain}, {PID: 813, Role: model.UnitRoleHelper}},
+		},
+		Processes: []model.Result{
+			{
+				Process:  main,
+				Ancestry: []model.Process{initProc, main},
+				Warnings: []string{"Process is running as root"},
+				Findings: []model.Finding{{RuleID: "root", Severity: model.SeverityLow, Message: "Process is running as root"}},
+			},
+			{Process: worker, Ancestry: []model.Process{initProc, main, worker}},
+		},
+	}
+}
+
+func TestRenderUnit(t *testing.T) {
+	var buf bytes.Buffer
+	RenderUnit(&buf, unitFixture(), false)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Unit        : nginx.service (active, running)\n",
+		"Description : A high performance web server\n",
+		"Wanted By   : multi-user.target\n",
+		"Triggered By: nginx.socket\n",
+		"Activation  : sockets.target → nginx.socket → nginx.service\n",
+		"Processes   : 2\n",
+		"[main] nginx (pid 812)\n",
+		"  Sockets   : 0.0.0.0:80 (TCP | LISTENING)\n",
+		"  Warnings  : Process is running as root [root low]\n",
+		"[helper] nginx (pid 813)\n",
+		"  Parent    : nginx (pid 812)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("unit view lacks %q:\n%s", want, out)
+		}
+	}
+	// The unit is explained once, not once per process.
+	if n := strings.Count(out, "Description"); n != 1 {
+		t.Errorf("description printed %d times, want 1", n)
+	}
+}
+
+func TestUnitToJSON(t *testing.T) {
+	data, err := UnitToJSON(unitFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Unit      model.Unit
+		Processes []struct {
+			Role    model.UnitRole
+			Process model.Process
+		}
+	}
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if got.Unit.Name != "nginx.service" || len(got.Processes) != 2 {
+		t.Fatalf("decoded %+v", got)
+	}
+	if got.Processes[0].Role != model.UnitRoleMain || got.Processes[1].Role != model.UnitRoleHelper || got.Processes[1].Process.PID != 813 {
+		t.Errorf("roles = %s/%s", got.Processes[0].Role, got.Processes[1].Role)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/policy"
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/internal/source"
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -14,6 +15,7 @@ type AnalyzeConfig struct {
 	PID     int
 	Verbose bool
 	Tree    bool
+	Peers   bool
 	Target  model.Target
 }
 
@@ -23,7 +25,7 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		return model.Result{}, err
 	}
 
-	src := source.Detect(ancestry)
+	src := detectSource(ancestry)
 
 	// ReadProcess labels lxc.payload cgroups generically as "lxc-based:" since
 	// it can't see the ancestry. source.Detect knows the actual runtime via the
@@ -50,6 +52,15 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		resolvedTarget = proc.Command
 	}
 
+	// Name the local process at the other end of each established
+	// connection, and what started it. Finding it scans every process's fds
+	// and detects each peer's source, so only callers that show it ask.
+	if cfg.Peers && len(proc.Sockets) > 0 {
+		proc.Sockets = procpkg.ResolveSocketPeers(proc.Sockets)
+		labelPeerSources(proc.Sockets)
+		ancestry[len(ancestry)-1].Sockets = proc.Sockets
+	}
+
 	// Resolve the target container's healthcheck so the warning only fires when
 	// the runtime confirms none is configured.
 	if proc.ContainerID != "" {
@@ -79,7 +90,11 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		}
 	}
 
-	if cfg.Verbose && len(ancestry) > 0 {
+	// Extended stats and resource/file context are read live per process and
+	// aren't part of a captured snapshot.
+	live := procpkg.ActiveReplay() == nil
+
+	if cfg.Verbose && live && len(ancestry) > 0 {
 		memInfo, ioStats, fileDescs, fdCount, fdLimit, threadCount, err := procpkg.ReadExtendedInfo(cfg.PID)
 		if err == nil {
 			proc.Memory = memInfo
@@ -95,13 +110,13 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 
 	var resCtx *model.ResourceContext
 	var fileCtx *model.FileContext
-	if cfg.Verbose {
+	if cfg.Verbose && live {
 		resCtx = procpkg.GetResourceContext(cfg.PID)
 		fileCtx = procpkg.GetFileContext(cfg.PID)
 	}
 
 	restartCount := 0
-	if src.Type == model.SourceSystemd {
+	if src.Type == model.SourceSystemd || src.Type == model.SourceSupervisor {
 		if v, ok := src.Details["NRestarts"]; ok {
 			if count, err := strconv.Atoi(v); err == nil {
 				restartCount = count
@@ -116,11 +131,48 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		RestartCount:    restartCount,
 		Ancestry:        ancestry,
 		Source:          src,
-		Warnings:        source.Warnings(ancestry, restartCount, src.Type),
 		ResourceContext: resCtx,
 		FileContext:     fileCtx,
 		Children:        childProcesses,
 	}
+	policy.Apply(&res)
 
 	return res, nil
 }
+
+// labelPeerSources sets PeerSource on each socket with a peer process, e.g.
+// "systemd postgresql.service", detecting each peer's source once.
+func labelPeerSources(sockets []model.Socket) {
+	labels := make(map[int]string)
+	for i, s := range sockets {
+		if s.PeerPID == 0 {
+			continue
+		}
+		label, ok := labels[s.PeerPID]
+		if !ok {
+			if ancestry, err := procpkg.ResolveAncestry(s.PeerPID); err == nil {
+				if src := detectSource(ancestry); src.Type != "" && src.Type != model.SourceUnknown {
+					label = strings.TrimSpace(string(src.Type) + " " + src.Name)
+				}
+			}
+			labels[s.PeerPID] = label
+		}
+		sockets[i].PeerSource = label
+	}
+}
+
+// detectSource explains the ancestry. When replaying a snapshot it returns the
+// source detected at capture time: detection consults the captured host's
+// cgroups, service manager and config files, none of which exist where the
+// snapshot is being read.
+func detectSource(ancestry []model.Process) model.Source {
+	if r := procpkg.ActiveReplay(); r != nil {
+		if len(ancestry) > 0 {
+			if src, ok := r.Source(ancestry[len(ancestry)-1].PID); ok {
+				return src
+			}
+		}
+		return model.Source{Type: model.SourceUnknown}
+	}
+	return source.Detect(ancestry)
+}
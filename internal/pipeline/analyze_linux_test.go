@@ -0,0 +1,50 @@
+//go:build linux
+
+package pipeline
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+// TestAnalyzePID_Peers connects to a listener of our own and checks the peer
+// of the connection is only looked up when asked for.
+func TestAnalyzePID_Peers(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	self := os.Getpid()
+	peers := func(cfg AnalyzeConfig) int {
+		res, err := AnalyzePID(cfg)
+		if err != nil {
+			t.Fatalf("AnalyzePID(%+v): %v", cfg, err)
+		}
+		n := 0
+		for _, s := range res.Process.Sockets {
+			if s.PeerPID == self {
+				n++
+			}
+		}
+		return n
+	}
+	if n := peers(AnalyzeConfig{PID: self}); n != 0 {
+		t.Errorf("%d sockets with a peer without Peers, want 0", n)
+	}
+	if n := peers(AnalyzeConfig{PID: self, Peers: true}); n == 0 {
+		t.Error("no socket with a peer with Peers")
+	}
+}
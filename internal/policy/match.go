@@ -0,0 +1,246 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Match is a condition over the analyzed process (the last entry of the
+// ancestry) and its result. Every field that is set must hold; a field given
+// a list holds when any entry does. Patterns are case-sensitive globs.
+type Match struct {
+	User       patterns `yaml:"user"`
+	Command    patterns `yaml:"command"`
+	Exe        patterns `yaml:"exe"`
+	WorkingDir patterns `yaml:"working_dir"`
+	// Cmdline is a regular expression over the full command line.
+	Cmdline string `yaml:"cmdline"`
+	// Capabilities holds when the process has any of the listed capabilities.
+	Capabilities patterns `yaml:"capabilities"`
+	// Env entries are KEY or KEY=<glob>.
+	Env stringList `yaml:"env"`
+	// Listen matches a listening socket as "<address>:<port>", e.g.
+	// "0.0.0.0:*" or "*:5432". IPv6 addresses are bracketed: "[::]:443".
+	Listen     patterns `yaml:"listen"`
+	Source     patterns `yaml:"source"`
+	SourceName patterns `yaml:"source_name"`
+	Service    patterns `yaml:"service"`
+	// Container matches the container description; "*" is any container.
+	Container patterns `yaml:"container"`
+	// Uptime and Restarts compare with <, <=, > or >=, e.g. ">90d", "<5m",
+	// ">3". Uptime accepts Go durations plus a "d" (day) unit.
+	Uptime   string `yaml:"uptime"`
+	Restarts string `yaml:"restarts"`
+
+	cmdline  *regexp.Regexp
+	uptime   *comparison
+	restarts *comparison
+}
+
+// patterns is a list of globs, given in YAML as a single string or a list.
+// "*" matches any run of characters (including "/") and "?" any one.
+type patterns []*regexp.Regexp
+
+func (p *patterns) UnmarshalYAML(node *yaml.Node) error {
+	var list stringList
+	if err := list.UnmarshalYAML(node); err != nil {
+		return err
+	}
+	*p = nil
+	for _, glob := range list {
+		*p = append(*p, compileGlob(glob))
+	}
+	return nil
+}
+
+// stringList is a list of strings, given in YAML as a single string or a list.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = stringList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+func compileGlob(glob string) *regexp.Regexp {
+	re := regexp.QuoteMeta(glob)
+	re = strings.ReplaceAll(re, `\*`, ".*")
+	re = strings.ReplaceAll(re, `\?`, ".")
+	return regexp.MustCompile("^" + re + "$")
+}
+
+func (p patterns) match(s string) bool {
+	for _, re := range p {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p patterns) matchAny(values []string) bool {
+	for _, v := range values {
+		if p.match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+type comparison struct {
+	op    string
+	value float64
+}
+
+func (c comparison) holds(v float64) bool {
+	switch c.op {
+	case "<":
+		return v < c.value
+	case "<=":
+		return v <= c.value
+	case ">":
+		return v > c.value
+	default:
+		return v >= c.value
+	}
+}
+
+// parseComparison splits ">90d" into its operator and value, which parse
+// converts to a number.
+func parseComparison(expr string, parse func(string) (float64, error)) (*comparison, error) {
+	expr = strings.TrimSpace(expr)
+	var op string
+	for _, candidate := range []string{"<=", ">=", "<", ">"} {
+		if strings.HasPrefix(expr, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("%q needs a leading <, <=, > or >=", expr)
+	}
+	v, err := parse(strings.TrimSpace(expr[len(op):]))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", expr, err)
+	}
+	return &comparison{op: op, value: v}, nil
+}
+
+func parseDuration(s string) (float64, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return n * 24 * float64(time.Hour), nil
+	}
+	d, err := time.ParseDuration(s)
+	return float64(d), err
+}
+
+func parseCount(s string) (float64, error) {
+	n, err := strconv.Atoi(s)
+	return float64(n), err
+}
+
+func (m *Match) empty() bool {
+	return len(m.User) == 0 && len(m.Command) == 0 && len(m.Exe) == 0 &&
+		len(m.WorkingDir) == 0 && m.Cmdline == "" && len(m.Capabilities) == 0 &&
+		len(m.Env) == 0 && len(m.Listen) == 0 && len(m.Source) == 0 &&
+		len(m.SourceName) == 0 && len(m.Service) == 0 && len(m.Container) == 0 &&
+		m.Uptime == "" && m.Restarts == ""
+}
+
+func (m *Match) compile() error {
+	var err error
+	if m.Cmdline != "" {
+		if m.cmdline, err = regexp.Compile(m.Cmdline); err != nil {
+			return fmt.Errorf("cmdline: %w", err)
+		}
+	}
+	if m.Uptime != "" {
+		if m.uptime, err = parseComparison(m.Uptime, parseDuration); err != nil {
+			return fmt.Errorf("uptime: %w", err)
+		}
+	}
+	if m.Restarts != "" {
+		if m.restarts, err = parseComparison(m.Restarts, parseCount); err != nil {
+			return fmt.Errorf("restarts: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Match) matches(res model.Result) bool {
+	p := res.Process
+	if len(res.Ancestry) > 0 {
+		p = res.Ancestry[len(res.Ancestry)-1]
+	}
+
+	switch {
+	case len(m.User) > 0 && !m.User.match(p.User),
+		len(m.Command) > 0 && !m.Command.match(p.Command),
+		len(m.Exe) > 0 && !m.Exe.match(p.Exe),
+		len(m.WorkingDir) > 0 && !m.WorkingDir.match(p.WorkingDir),
+		m.cmdline != nil && !m.cmdline.MatchString(p.Cmdline),
+		len(m.Capabilities) > 0 && !m.Capabilities.matchAny(p.Capabilities),
+		len(m.Env) > 0 && !m.matchEnv(p.Env),
+		len(m.Listen) > 0 && !m.Listen.matchAny(listenAddrs(p.Sockets)),
+		len(m.Source) > 0 && !m.Source.match(string(res.Source.Type)),
+		len(m.SourceName) > 0 && !m.SourceName.match(res.Source.Name),
+		len(m.Service) > 0 && !m.Service.match(p.Service),
+		len(m.Container) > 0 && (p.Container == "" || !m.Container.match(p.Container)),
+		m.restarts != nil && !m.restarts.holds(float64(res.RestartCount)):
+		return false
+	}
+	if m.uptime != nil {
+		if p.StartedAt.IsZero() || !m.uptime.holds(float64(time.Since(p.StartedAt))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Match) matchEnv(env []string) bool {
+	for _, want := range m.Env {
+		wantKey, wantValue, withValue := strings.Cut(want, "=")
+		for _, e := range env {
+			k, v, _ := strings.Cut(e, "=")
+			if k != wantKey {
+				continue
+			}
+			if !withValue {
+				return true
+			}
+			if compileGlob(wantValue).MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func listenAddrs(sockets []model.Socket) []string {
+	var addrs []string
+	for _, s := range sockets {
+		if s.State == "LISTEN" {
+			addrs = append(addrs, net.JoinHostPort(s.Address, strconv.Itoa(s.Port)))
+		}
+	}
+	return addrs
+}
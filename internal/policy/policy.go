@@ -0,0 +1,226 @@
+// Package policy decides which warnings witr reports and how severe they are.
+// The built-in rules live in the source package; a policy file tunes them
+// (severity, allow-lists, disabling) and adds rules of its own:
+//
+//	rules:
+//	  - id: long-uptime
+//	    allow:
+//	      - command: postgres
+//	  - id: public-bind
+//	    allow:
+//	      - command: nginx
+//	  - id: tmp-binary
+//	    severity: high
+//	    message: Process runs a binary from /tmp
+//	    match:
+//	      exe: /tmp/*
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// policyFile is the policy.yaml layout.
+type policyFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule either tunes a built-in rule (its ID is one of the source.Rule*
+// constants and it has no Match) or declares a new one.
+type Rule struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+	// Severity overrides a built-in rule's default; new rules default to
+	// medium.
+	Severity model.Severity `yaml:"severity"`
+	// Message is reported for a new rule's findings; the ID when empty.
+	Message  string `yaml:"message"`
+	Disabled bool   `yaml:"disabled"`
+	// Match is required for a new rule and not allowed on a built-in one.
+	Match *Match `yaml:"match"`
+	// Allow suppresses the rule for any process matching one of its entries.
+	Allow []Match `yaml:"allow"`
+
+	file string
+}
+
+var (
+	// overrides holds the tuning for built-in rules, by ID.
+	overrides = map[string]*Rule{}
+	// custom holds the new rules, in declaration order.
+	custom []*Rule
+)
+
+// DefaultPaths returns the policy files witr reads when --policy isn't given,
+// system-wide first so a user's file can override a rule of the same ID.
+func DefaultPaths() []string {
+	var paths []string
+	if runtime.GOOS != "windows" {
+		paths = append(paths, "/etc/witr/policy.yaml")
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "witr", "policy.yaml"))
+	}
+	return paths
+}
+
+// Load reads the given policy files, skipping any that don't exist, and
+// installs their rules for Evaluate. A rule in a later file replaces one with
+// the same ID from an earlier file. Calling it with no readable files
+// restores the built-in behavior.
+func Load(paths ...string) error {
+	var rules []*Rule
+	byID := make(map[string]int)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var f policyFile
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for i := range f.Rules {
+			r := &f.Rules[i]
+			r.file = path
+			if err := r.validate(); err != nil {
+				return fmt.Errorf("%s: rule %q: %w", path, r.ID, err)
+			}
+			if idx, ok := byID[r.ID]; ok {
+				rules[idx] = r
+				continue
+			}
+			byID[r.ID] = len(rules)
+			rules = append(rules, r)
+		}
+	}
+
+	newOverrides := map[string]*Rule{}
+	var newCustom []*Rule
+	for _, r := range rules {
+		if _, ok := source.BuiltinRuleSeverity[r.ID]; ok {
+			newOverrides[r.ID] = r
+		} else {
+			newCustom = append(newCustom, r)
+		}
+	}
+	overrides, custom = newOverrides, newCustom
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if r.ID == "" {
+		return errors.New("missing id")
+	}
+	if r.Severity != "" && r.Severity.Rank() == 0 {
+		return fmt.Errorf("invalid severity %q (want info, low, medium, high or critical)", r.Severity)
+	}
+	_, builtin := source.BuiltinRuleSeverity[r.ID]
+	switch {
+	case builtin && r.Match != nil:
+		return errors.New("match can't be set on a built-in rule; declare a new rule instead")
+	case !builtin && r.Match == nil && !r.Disabled:
+		return errors.New("not a built-in rule, so it needs a match")
+	}
+	if r.Match != nil {
+		if r.Match.empty() {
+			return errors.New("match has no conditions")
+		}
+		if err := r.Match.compile(); err != nil {
+			return fmt.Errorf("match: %w", err)
+		}
+	}
+	for i := range r.Allow {
+		if r.Allow[i].empty() {
+			return fmt.Errorf("allow entry %d has no conditions", i+1)
+		}
+		if err := r.Allow[i].compile(); err != nil {
+			return fmt.Errorf("allow entry %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the findings for res: the built-in rules with the policy's
+// severities and allow-lists applied, followed by the policy's own rules.
+func Evaluate(res model.Result) []model.Finding {
+	var out []model.Finding
+	for _, f := range source.Findings(res.Ancestry, res.RestartCount, res.Source.Type) {
+		if r, ok := overrides[f.RuleID]; ok {
+			if r.Disabled || r.allows(res) {
+				continue
+			}
+			if r.Severity != "" {
+				f.Severity = r.Severity
+			}
+		}
+		out = append(out, f)
+	}
+
+	for _, r := range custom {
+		if r.Disabled || !r.Match.matches(res) || r.allows(res) {
+			continue
+		}
+		severity := r.Severity
+		if severity == "" {
+			severity = model.SeverityMedium
+		}
+		msg := r.Message
+		if msg == "" {
+			msg = r.ID
+		}
+		out = append(out, model.Finding{RuleID: r.ID, Severity: severity, Message: msg})
+	}
+	return out
+}
+
+// RuleDescription says what the rule with id flags: a policy rule's
+// description, else its message, or a built-in rule's fixed description.
+// It's "" for a rule neither defines.
+func RuleDescription(id string) string {
+	for _, r := range custom {
+		if r.ID != id {
+			continue
+		}
+		if r.Description != "" {
+			return r.Description
+		}
+		return r.Message
+	}
+	return source.BuiltinRuleDescription[id]
+}
+
+// Apply sets res's Findings and Warnings from Evaluate.
+func Apply(res *model.Result) {
+	res.Findings = Evaluate(*res)
+	res.Warnings = nil
+	for _, f := range res.Findings {
+		res.Warnings = append(res.Warnings, f.Message)
+	}
+}
+
+func (r *Rule) allows(res model.Result) bool {
+	for i := range r.Allow {
+		if r.Allow[i].matches(res) {
+			return true
+		}
+	}
+	return false
+}
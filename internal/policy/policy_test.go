@@ -0,0 +1,234 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writePolicy(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func load(t *testing.T, paths ...string) error {
+	t.Helper()
+	t.Cleanup(func() { Load() })
+	return Load(paths...)
+}
+
+// result returns a systemd-supervised process that raises the public-bind,
+// long-uptime and root built-in rules.
+func result(command string) model.Result {
+	p := model.Process{
+		PID:        4242,
+		Command:    command,
+		Exe:        "/usr/sbin/" + command,
+		User:       "root",
+		StartedAt:  time.Now().Add(-100 * 24 * time.Hour),
+		WorkingDir: "/var/lib/" + command,
+		Sockets:    []model.Socket{{Address: "0.0.0.0", Port: 80, State: "LISTEN"}},
+	}
+	return model.Result{
+		Process:  p,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, p},
+		Source:   model.Source{Type: model.SourceSystemd, Name: command + ".service"},
+	}
+}
+
+func ruleIDs(findings []model.Finding) string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.RuleID+"/"+string(f.Severity))
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestEvaluateBuiltins(t *testing.T) {
+	if err := load(t); err != nil {
+		t.Fatal(err)
+	}
+	want := "public-bind/medium,root/low,long-uptime/info"
+	if got := ruleIDs(Evaluate(result("nginx"))); got != want {
+		t.Errorf("findings = %s, want %s", got, want)
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	path := writePolicy(t, `
+rules:
+  - id: long-uptime
+    allow:
+      - command: postgres
+  - id: public-bind
+    severity: high
+    allow:
+      - command: nginx
+        listen: "0.0.0.0:80"
+  - id: root
+    disabled: true
+  - id: root-web
+    severity: critical
+    message: Web server runs as root
+    match:
+      user: root
+      command: [nginx, httpd]
+      source: systemd
+      uptime: ">30d"
+`)
+	if err := load(t, path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"nginx", "long-uptime/info,root-web/critical"},
+		{"postgres", "public-bind/high"},
+		{"httpd", "public-bind/high,long-uptime/info,root-web/critical"},
+	}
+	for _, tt := range tests {
+		res := result(tt.command)
+		if got := ruleIDs(Evaluate(res)); got != tt.want {
+			t.Errorf("%s: findings = %s, want %s", tt.command, got, tt.want)
+		}
+	}
+
+	res := result("nginx")
+	Apply(&res)
+	if len(res.Warnings) != len(res.Findings) || res.Warnings[1] != "Web server runs as root" {
+		t.Errorf("Apply: warnings = %q, findings = %+v", res.Warnings, res.Findings)
+	}
+
+	for id, want := range map[string]string{
+		"root-web":    "Web server runs as root",
+		"long-uptime": source.BuiltinRuleDescription["long-uptime"],
+		"unknown":     "",
+	} {
+		if got := RuleDescription(id); got != want {
+			t.Errorf("RuleDescription(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestMatchFields(t *testing.T) {
+	res := result("app")
+	res.RestartCount = 7
+	last := &res.Ancestry[1]
+	last.Env = []string{"APP_ENV=production-eu"}
+	last.Capabilities = []string{"CAP_NET_ADMIN"}
+	last.Container = "docker: app (registry.example.com/team/app:1.2)"
+	last.Cmdline = "/usr/sbin/app --config /etc/app.yaml"
+	last.Sockets = append(last.Sockets, model.Socket{Address: "::", Port: 443, State: "LISTEN"})
+
+	tests := []struct {
+		match string
+		want  bool
+	}{
+		{"exe: /usr/*", true},
+		{"exe: /opt/*", false},
+		{"env: APP_ENV", true},
+		{"env: APP_ENV=production-*", true},
+		{"env: APP_ENV=staging", false},
+		{"capabilities: [CAP_SYS_ADMIN, CAP_NET_*]", true},
+		{"container: '*'", true},
+		{"container: '*team/app*'", true},
+		{"listen: '[::]:443'", true},
+		{"listen: '*:22'", false},
+		{"cmdline: '--config \\S+\\.yaml'", true},
+		{"source_name: app.service", true},
+		{"working_dir: /tmp", false},
+		{"restarts: '>5'", true},
+		{"restarts: '<=5'", false},
+		{"uptime: '>90d'", true},
+		{"uptime: '<1h'", false},
+		{"{user: root, command: other}", false},
+	}
+	for _, tt := range tests {
+		path := writePolicy(t, "rules:\n  - id: probe\n    match: "+indentMatch(tt.match))
+		if err := load(t, path); err != nil {
+			t.Fatalf("%s: %v", tt.match, err)
+		}
+		got := strings.Contains(ruleIDs(Evaluate(res)), "probe/")
+		if got != tt.want {
+			t.Errorf("match %s = %v, want %v", tt.match, got, tt.want)
+		}
+	}
+
+	// A process outside any container never matches a container pattern.
+	path := writePolicy(t, "rules:\n  - id: probe\n    match:\n      container: '*'\n")
+	if err := load(t, path); err != nil {
+		t.Fatal(err)
+	}
+	if ids := ruleIDs(Evaluate(result("app"))); strings.Contains(ids, "probe/") {
+		t.Errorf("container: '*' matched a host process: %s", ids)
+	}
+}
+
+// indentMatch turns a one-line "key: value" match into a YAML mapping.
+func indentMatch(m string) string {
+	if strings.HasPrefix(m, "{") {
+		return m + "\n"
+	}
+	return "\n      " + m + "\n"
+}
+
+func TestLoadOverride(t *testing.T) {
+	system := writePolicy(t, "rules:\n  - id: root\n    severity: high\n  - id: probe\n    match: {user: root}\n")
+	user := writePolicy(t, "rules:\n  - id: root\n    severity: info\n  - id: probe\n    disabled: true\n")
+	if err := load(t, system, filepath.Join(t.TempDir(), "missing.yaml"), user); err != nil {
+		t.Fatal(err)
+	}
+	want := "public-bind/medium,root/info,long-uptime/info"
+	if got := ruleIDs(Evaluate(result("nginx"))); got != want {
+		t.Errorf("findings = %s, want %s", got, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"missing id", "rules:\n  - severity: high\n", "missing id"},
+		{"bad severity", "rules:\n  - id: root\n    severity: urgent\n", "invalid severity"},
+		{"match on builtin", "rules:\n  - id: root\n    match: {user: root}\n", "built-in rule"},
+		{"custom without match", "rules:\n  - id: mine\n", "needs a match"},
+		{"empty match", "rules:\n  - id: mine\n    match: {}\n", "no conditions"},
+		{"empty allow", "rules:\n  - id: root\n    allow: [{}]\n", "allow entry 1"},
+		{"bad regexp", "rules:\n  - id: mine\n    match: {cmdline: '('}\n", "cmdline"},
+		{"bad uptime", "rules:\n  - id: mine\n    match: {uptime: '90d'}\n", "needs a leading"},
+		{"bad restarts", "rules:\n  - id: mine\n    match: {restarts: '>many'}\n", "restarts"},
+		{"unknown field", "rules:\n  - id: mine\n    match: {username: root}\n", "username"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := load(t, writePolicy(t, tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Every built-in rule ID must stay matchable by policy files.
+func TestBuiltinRulesKnown(t *testing.T) {
+	for id := range source.BuiltinRuleSeverity {
+		if source.BuiltinRuleDescription[id] == "" {
+			t.Errorf("%s has no description", id)
+		}
+		path := writePolicy(t, "rules:\n  - id: "+id+"\n    severity: critical\n")
+		if err := load(t, path); err != nil {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+}
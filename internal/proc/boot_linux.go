@@ -11,7 +11,7 @@ import (
 )
 
 func bootTime() time.Time {
-	f, err := os.Open("/proc/stat")
+	f, err := os.Open(ProcPath("stat"))
 	if err != nil {
 		return time.Now()
 	}
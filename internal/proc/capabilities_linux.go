@@ -3,7 +3,6 @@
 package proc
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -56,7 +55,7 @@ var capNames = map[int]string{
 
 // ReadCapabilities reads the effective capabilities of a process from /proc/<pid>/status.
 func ReadCapabilities(pid int) []string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	data, err := os.ReadFile(pidPath(pid, "status"))
 	if err != nil {
 		return nil
 	}
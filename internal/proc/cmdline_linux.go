@@ -3,14 +3,13 @@
 package proc
 
 import (
-	"fmt"
 	"os"
 	"strings"
 )
 
 // GetCmdline returns the command line for a given PID
 func GetCmdline(pid int) string {
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	cmdlineBytes, err := os.ReadFile(pidPath(pid, "cmdline"))
 	if err != nil {
 		return "(unknown)"
 	}
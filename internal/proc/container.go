@@ -14,6 +14,9 @@ import (
 // ResolveContainerByPort queries the Docker CLI for a container publishing
 // the given port. Returns nil if Docker is unavailable or no container matches.
 func ResolveContainerByPort(port int) *model.ContainerMatch {
+	if activeReplay != nil {
+		return nil
+	}
 	if _, err := exec.LookPath("docker"); err != nil {
 		return nil
 	}
@@ -161,6 +164,9 @@ func resolveContainerName(id, runtime string) string {
 // healthcheck configured: "present", "absent", or "" when undeterminable
 // (runtime unavailable, inspect error, or unsupported runtime).
 func ContainerHealthcheckStatus(id, runtime string) string {
+	if activeReplay != nil {
+		return activeReplay.ContainerHealthcheck(id)
+	}
 	if !isValidContainerID(id) || (runtime != "docker" && runtime != "podman") {
 		return ""
 	}
@@ -28,20 +28,8 @@ func registerRuntime(rt ContainerRuntime) {
 func ResolveContainer(query string, exact bool) []*model.ContainerMatch {
 	q := strings.ToLower(query)
 	var out []*model.ContainerMatch
-	seen := make(map[string]bool)
-	for _, rt := range registeredRuntimes {
-		if !rt.Available() {
-			continue
-		}
-		for _, c := range rt.List() {
-			if !matchContainer(c, q, exact) {
-				continue
-			}
-			key := rt.Name() + "|" + c.ID
-			if seen[key] {
-				continue
-			}
-			seen[key] = true
+	for _, c := range ListAllContainers() {
+		if matchContainer(c, q, exact) {
 			out = append(out, c)
 		}
 	}
@@ -74,6 +62,9 @@ func matchContainer(c *model.ContainerMatch, query string, exact bool) bool {
 // ListAllContainers returns every container reported by every available
 // runtime, deduped by runtime|id. Used by the TUI's Containers tab.
 func ListAllContainers() []*model.ContainerMatch {
+	if activeReplay != nil {
+		return activeReplay.Containers()
+	}
 	var out []*model.ContainerMatch
 	seen := make(map[string]bool)
 	for _, rt := range registeredRuntimes {
@@ -96,6 +87,9 @@ func ListAllContainers() []*model.ContainerMatch {
 // the host. Returns 0 if the runtime can't be reached or the PID isn't
 // available (container not running, namespaced PID, etc.).
 func ResolveContainerHostPID(runtime, id string) int {
+	if activeReplay != nil {
+		return activeReplay.ContainerHostPID(runtime, id)
+	}
 	for _, rt := range registeredRuntimes {
 		if rt.Name() == runtime && rt.Available() {
 			return rt.HostPID(id)
@@ -114,9 +108,10 @@ type enrichingRuntime interface {
 
 // EnrichContainer asks the originating runtime to fill in any extra fields
 // available via a per-container query (e.g. crictl inspect). No-op when the
-// runtime doesn't expose extra detail.
+// runtime doesn't expose extra detail, and when replaying a snapshot (the
+// captured listing is already enriched).
 func EnrichContainer(match *model.ContainerMatch) {
-	if match == nil {
+	if match == nil || activeReplay != nil {
 		return
 	}
 	for _, rt := range registeredRuntimes {
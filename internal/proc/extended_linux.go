@@ -22,7 +22,7 @@ func ReadExtendedInfo(pid int) (model.MemoryInfo, model.IOStats, []string, int,
 	var fdLimit uint64
 
 	// Read memory info from /proc/[pid]/statm
-	if statmData, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid)); err == nil {
+	if statmData, err := os.ReadFile(pidPath(pid, "statm")); err == nil {
 		fields := strings.Fields(string(statmData))
 		if len(fields) >= 7 {
 			pageSize := uint64(os.Getpagesize())
@@ -51,7 +51,7 @@ func ReadExtendedInfo(pid int) (model.MemoryInfo, model.IOStats, []string, int,
 	}
 
 	// Read I/O stats from /proc/[pid]/io
-	if ioData, err := os.ReadFile(fmt.Sprintf("/proc/%d/io", pid)); err == nil {
+	if ioData, err := os.ReadFile(pidPath(pid, "io")); err == nil {
 		lines := strings.Split(string(ioData), "\n")
 		for _, line := range lines {
 			if strings.HasPrefix(line, "read_bytes:") {
@@ -75,10 +75,10 @@ func ReadExtendedInfo(pid int) (model.MemoryInfo, model.IOStats, []string, int,
 	}
 
 	// Read file descriptors from /proc/[pid]/fd
-	if fdDir, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid)); err == nil {
+	if fdDir, err := os.ReadDir(pidPath(pid, "fd")); err == nil {
 		fdCount = len(fdDir)
 		for _, fdEntry := range fdDir {
-			fdPath := fmt.Sprintf("/proc/%d/fd/%s", pid, fdEntry.Name())
+			fdPath := pidPath(pid, "fd", fdEntry.Name())
 			if linkTarget, err := os.Readlink(fdPath); err == nil {
 				fileDescs = append(fileDescs, fmt.Sprintf("%s -> %s", fdEntry.Name(), linkTarget))
 			}
@@ -89,7 +89,7 @@ func ReadExtendedInfo(pid int) (model.MemoryInfo, model.IOStats, []string, int,
 	fdLimit = uint64(getFileLimit(pid))
 
 	// Get thread count from /proc/[pid]/status
-	if statusData, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid)); err == nil {
+	if statusData, err := os.ReadFile(pidPath(pid, "status")); err == nil {
 		lines := strings.Split(string(statusData), "\n")
 		for _, line := range lines {
 			if strings.HasPrefix(line, "Threads:") {
@@ -5,14 +5,13 @@ package proc
 import (
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 )
 
 func socketsForPID(pid int) []string {
 	var inodes []string
 	seen := make(map[string]bool)
-	fdPath := "/proc/" + strconv.Itoa(pid) + "/fd"
+	fdPath := pidPath(pid, "fd")
 
 	entries, err := os.ReadDir(fdPath)
 	if err != nil {
@@ -3,7 +3,6 @@
 package proc
 
 import (
-	"fmt"
 	"os"
 	"sort"
 	"strconv"
@@ -20,7 +19,7 @@ import (
 func GetFileContext(pid int) *model.FileContext {
 	var fileContext model.FileContext
 
-	fdDir := fmt.Sprintf("/proc/%v/fd", pid)
+	fdDir := pidPath(pid, "fd")
 	fdFiles, err := os.ReadDir(fdDir)
 	if err != nil {
 		return nil
@@ -37,7 +36,7 @@ func getFileLimit(pid int) int {
 	var linuxDefaultMaxOpenFile = getDefaultMaxOpenFiles()
 
 	// Read /proc/<pid>/limits for file limit
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%v/limits", pid))
+	data, err := os.ReadFile(pidPath(pid, "limits"))
 	if err != nil {
 		return linuxDefaultMaxOpenFile
 	}
@@ -89,7 +88,7 @@ func getDefaultMaxOpenFiles() int {
 // the process's own open fds, keeping the work bounded to this one process.
 // (lslocks resolves every lock on the system and blocks on slow mounts.)
 func getLockedFiles(pid int) []string {
-	data, err := os.ReadFile("/proc/locks")
+	data, err := os.ReadFile(ProcPath("locks"))
 	if err != nil {
 		return nil
 	}
@@ -123,7 +122,7 @@ func getLockedFiles(pid int) []string {
 	// Resolve paths from the process's own fds; keep the raw identifier for any
 	// lock that can't be matched to an open fd (e.g. an unlinked file).
 	paths := map[fileID]string{}
-	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+	fdDir := pidPath(pid, "fd")
 	if entries, err := os.ReadDir(fdDir); err == nil {
 		for _, e := range entries {
 			if len(paths) == len(ids) {
@@ -0,0 +1,286 @@
+package proc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// podCache holds resolved pods by container, since source detection asks
+// again for every analysis (and, in --audit and --watch, for every process
+// on every pass) and each lookup runs crictl twice. A container's pod never
+// changes, so entries stay until the container is gone: once the cache
+// reaches podCachePruneAt, entries for containers no process runs in any
+// more are dropped.
+var (
+	podCache        = make(map[string]model.ContainerMatch)
+	podCachePruneAt = minPodCachePrune
+	podCacheMu      sync.Mutex
+)
+
+const minPodCachePrune = 64
+
+// kubeletRoot is the filesystem the kubelet's pod log and state directories
+// are read from. It's "/" unless a test points it at a fixture tree.
+var kubeletRoot = "/"
+
+// KubernetesPod resolves the pod a kubepods cgroup puts a process in: the
+// pod UID and QoS class from the cgroup path, then the pod's name, namespace
+// and owner and the container's name from crictl, or from the kubelet's
+// /var/log/pods, /var/log/containers and /var/lib/kubelet/pods directories
+// when crictl isn't installed. Returns nil when the cgroup isn't a pod's.
+func KubernetesPod(cgroup string) *model.ContainerMatch {
+	uid, qos := parsePodCgroup(cgroup)
+	if uid == "" {
+		return nil
+	}
+	match := &model.ContainerMatch{
+		Runtime:     "k8s",
+		ID:          findLongHexID(cgroup),
+		PodUID:      uid,
+		PodQOSClass: qos,
+	}
+	if activeReplay != nil {
+		return match
+	}
+	key := uid + "/" + match.ID
+	podCacheMu.Lock()
+	cached, ok := podCache[key]
+	podCacheMu.Unlock()
+	if ok {
+		return &cached
+	}
+	// Resolved without the lock, so one slow crictl call doesn't hold up
+	// lookups of other containers; two racing lookups of the same one both
+	// resolve it.
+	if isValidContainerID(match.ID) && binAvailable("crictl") {
+		if payload, ok := crictlInspect(match.ID); ok {
+			match.Name = payload.Status.Metadata.Name
+			applyPodLabels(match, payload.Status.Labels)
+			crictlPodOwner(match, payload.Info.SandboxID)
+		}
+	}
+	if match.PodName == "" || match.Name == "" {
+		kubeletPod(match)
+	}
+	if match.PodName != "" {
+		// Left uncached when unresolved, so a lookup that failed (crictl
+		// timing out, the pod's log directory not yet created) is retried.
+		podCacheMu.Lock()
+		podCache[key] = *match
+		prune := len(podCache) >= podCachePruneAt
+		podCacheMu.Unlock()
+		if prune {
+			prunePodCache()
+		}
+	}
+	return match
+}
+
+// prunePodCache drops the pods of containers no process runs in any more,
+// and raises the size the next prune waits for to twice what's left, so a
+// node running many containers isn't rescanned on every new one.
+func prunePodCache() {
+	live := make(map[string]bool)
+	entries, _ := os.ReadDir(ProcPath())
+	for _, entry := range entries {
+		if pid, err := strconv.Atoi(entry.Name()); err == nil {
+			if id := findLongHexID(readCgroup(pid)); id != "" {
+				live[id] = true
+			}
+		}
+	}
+	podCacheMu.Lock()
+	defer podCacheMu.Unlock()
+	for key, match := range podCache {
+		if !live[match.ID] {
+			delete(podCache, key)
+		}
+	}
+	podCachePruneAt = max(minPodCachePrune, 2*len(podCache))
+}
+
+// parsePodCgroup extracts the pod UID and QoS class from a kubepods cgroup,
+// in both the cgroupfs layout (/kubepods/burstable/pod<uid>/<id>) and the
+// systemd one (/kubepods.slice/kubepods-burstable.slice/
+// kubepods-burstable-pod<uid with _ for ->.slice/cri-containerd-<id>.scope).
+// Guaranteed pods sit directly under kubepods, without a QoS tier.
+func parsePodCgroup(cgroup string) (uid, qos string) {
+	for _, line := range strings.Split(cgroup, "\n") {
+		if !strings.Contains(line, "kubepods") {
+			continue
+		}
+		qos = "Guaranteed"
+		for _, seg := range strings.Split(line, "/") {
+			switch {
+			case strings.Contains(seg, "besteffort"):
+				qos = "BestEffort"
+			case strings.Contains(seg, "burstable"):
+				qos = "Burstable"
+			}
+			if rest, ok := strings.CutSuffix(seg, ".slice"); ok {
+				if i := strings.LastIndex(rest, "-pod"); i >= 0 {
+					uid = strings.ReplaceAll(rest[i+len("-pod"):], "_", "-")
+				}
+			} else if rest, ok := strings.CutPrefix(seg, "pod"); ok {
+				uid = rest
+			}
+		}
+		if isPodUID(uid) {
+			return uid, qos
+		}
+		uid = ""
+	}
+	return "", ""
+}
+
+// isPodUID reports whether s looks like a pod UID: a UUID for API pods, or
+// the 32-digit hash the kubelet gives static pods.
+func isPodUID(s string) bool {
+	if len(s) != 36 && len(s) != 32 {
+		return false
+	}
+	for _, c := range s {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isStaticPodUID reports whether uid is a static pod's: the kubelet hashes
+// the manifest into a bare hex UID instead of the API server's UUID.
+func isStaticPodUID(uid string) bool {
+	return len(uid) == 32 && !strings.Contains(uid, "-")
+}
+
+// applyPodLabels fills match's pod fields from the io.kubernetes.* labels
+// the kubelet puts on every container and sandbox.
+func applyPodLabels(match *model.ContainerMatch, labels map[string]string) {
+	if v := labels["io.kubernetes.pod.name"]; v != "" {
+		match.PodName = v
+	}
+	if v := labels["io.kubernetes.pod.namespace"]; v != "" {
+		match.PodNamespace = v
+	}
+	if v := labels["io.kubernetes.pod.uid"]; v != "" {
+		match.PodUID = v
+	}
+	if v := labels["io.kubernetes.container.name"]; v != "" && match.Name == "" {
+		match.Name = v
+	}
+}
+
+type crictlInspectPodPayload struct {
+	Status struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+			UID       string `json:"uid"`
+		} `json:"metadata"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"status"`
+}
+
+// crictlPodOwner reads the pod sandbox's labels and annotations with
+// `crictl inspectp` and names the controller that owns the pod.
+func crictlPodOwner(match *model.ContainerMatch, sandboxID string) {
+	if !isValidContainerID(sandboxID) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeQueryTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "crictl", "inspectp", "-o", "json", sandboxID).Output()
+	if err != nil {
+		return
+	}
+	var pod crictlInspectPodPayload
+	if json.Unmarshal(out, &pod) != nil {
+		return
+	}
+	if m := pod.Status.Metadata; m.Name != "" {
+		match.PodName, match.PodNamespace = m.Name, m.Namespace
+		if m.UID != "" {
+			match.PodUID = m.UID
+		}
+	}
+	match.PodOwnerKind, match.PodOwnerName = podOwner(match.PodName, match.PodUID, pod.Status.Labels, pod.Status.Annotations)
+}
+
+// podOwner names the controller behind a pod from the labels each one
+// stamps on the pods it creates. Pods don't carry their ownerReferences
+// below the API server, so the owner's name comes from the label, or from
+// the pod name the controller generated from its own.
+func podOwner(podName, uid string, labels, annotations map[string]string) (kind, name string) {
+	switch {
+	case annotations["kubernetes.io/config.source"] == "file" || annotations["kubernetes.io/config.source"] == "http" || isStaticPodUID(uid):
+		return "Node", ""
+	case labels["batch.kubernetes.io/job-name"] != "":
+		return "Job", labels["batch.kubernetes.io/job-name"]
+	case labels["job-name"] != "":
+		return "Job", labels["job-name"]
+	case labels["pod-template-hash"] != "":
+		// <deployment>-<pod-template-hash>-<random>, through a ReplicaSet
+		// named <deployment>-<pod-template-hash>.
+		rs := trimNameSuffix(podName)
+		if deployment, ok := strings.CutSuffix(rs, "-"+labels["pod-template-hash"]); ok && deployment != "" {
+			return "Deployment", deployment
+		}
+		return "ReplicaSet", rs
+	case labels["statefulset.kubernetes.io/pod-name"] != "":
+		// <statefulset>-<ordinal>
+		return "StatefulSet", trimNameSuffix(podName)
+	case labels["controller-revision-hash"] != "" && labels["pod-template-generation"] != "":
+		// <daemonset>-<random>
+		return "DaemonSet", trimNameSuffix(podName)
+	}
+	return "", ""
+}
+
+// trimNameSuffix drops the last dash-separated part of a generated name.
+func trimNameSuffix(name string) string {
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// kubeletPod fills in what the kubelet's directories tell about a pod
+// without a container runtime to ask: /var/log/pods/<namespace>_<pod>_<uid>
+// names the pod, the /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+// link names the container, and /var/lib/kubelet/pods/<uid>/containers holds
+// one directory per container.
+func kubeletPod(match *model.ContainerMatch) {
+	if dirs, _ := filepath.Glob(filepath.Join(kubeletRoot, "var/log/pods", "*_*_"+match.PodUID)); len(dirs) > 0 {
+		if parts := strings.SplitN(filepath.Base(dirs[0]), "_", 3); len(parts) == 3 {
+			match.PodNamespace, match.PodName = parts[0], parts[1]
+		}
+	}
+	if match.Name == "" && match.ID != "" {
+		suffix := "-" + match.ID + ".log"
+		if logs, _ := filepath.Glob(filepath.Join(kubeletRoot, "var/log/containers", "*"+suffix)); len(logs) > 0 {
+			if parts := strings.SplitN(strings.TrimSuffix(filepath.Base(logs[0]), suffix), "_", 3); len(parts) == 3 {
+				match.Name = parts[2]
+				if match.PodName == "" {
+					match.PodName, match.PodNamespace = parts[0], parts[1]
+				}
+			}
+		}
+	}
+	if match.Name == "" {
+		if entries, err := os.ReadDir(filepath.Join(kubeletRoot, "var/lib/kubelet/pods", match.PodUID, "containers")); err == nil && len(entries) == 1 {
+			match.Name = entries[0].Name()
+		}
+	}
+	if match.PodOwnerKind == "" && isStaticPodUID(match.PodUID) {
+		match.PodOwnerKind = "Node"
+	}
+}
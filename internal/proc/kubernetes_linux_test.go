@@ -0,0 +1,43 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestPrunePodCache keeps the pods of containers a process still runs in and
+// drops the rest.
+func TestPrunePodCache(t *testing.T) {
+	resetPodCache(t)
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "42"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cgroup := "0::/kubepods/besteffort/pod0c1d2e3f-4a5b-4c6d-8e9f-a0b1c2d3e4f5/" + testContainerID + "\n"
+	if err := os.WriteFile(filepath.Join(root, "42", "cgroup"), []byte(cgroup), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetProcRoot(root)
+	t.Cleanup(func() { SetProcRoot("") })
+
+	gone := strings.Repeat("ab", 32)
+	podCache["live"] = model.ContainerMatch{ID: testContainerID, PodName: "api"}
+	podCache["gone"] = model.ContainerMatch{ID: gone, PodName: "old"}
+	prunePodCache()
+
+	if _, ok := podCache["live"]; !ok {
+		t.Error("pod of a running container was pruned")
+	}
+	if _, ok := podCache["gone"]; ok {
+		t.Error("pod of an exited container was kept")
+	}
+	if podCachePruneAt != minPodCachePrune {
+		t.Errorf("podCachePruneAt = %d, want %d", podCachePruneAt, minPodCachePrune)
+	}
+}
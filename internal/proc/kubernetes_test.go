@@ -0,0 +1,166 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const testContainerID = "3f4e9b2a1c0d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"
+
+func TestParsePodCgroup(t *testing.T) {
+	tests := []struct {
+		name, cgroup, uid, qos string
+	}{
+		{
+			name:   "systemd driver, burstable",
+			cgroup: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8d2f6c1e_4b7a_4c1d_9e2f_0a1b2c3d4e5f.slice/cri-containerd-" + testContainerID + ".scope\n",
+			uid:    "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f",
+			qos:    "Burstable",
+		},
+		{
+			name:   "systemd driver, guaranteed",
+			cgroup: "0::/kubepods.slice/kubepods-pod8d2f6c1e_4b7a_4c1d_9e2f_0a1b2c3d4e5f.slice/crio-" + testContainerID + ".scope",
+			uid:    "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f",
+			qos:    "Guaranteed",
+		},
+		{
+			name:   "cgroupfs driver, besteffort, cgroup v1",
+			cgroup: "12:pids:/kubepods/besteffort/pod8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f/" + testContainerID + "\n1:name=systemd:/kubepods/besteffort/pod8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f/" + testContainerID,
+			uid:    "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f",
+			qos:    "BestEffort",
+		},
+		{
+			name:   "static pod",
+			cgroup: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod6b1d4f0e9a8c7b6d5e4f3a2b1c0d9e8f.slice/cri-containerd-" + testContainerID + ".scope",
+			uid:    "6b1d4f0e9a8c7b6d5e4f3a2b1c0d9e8f",
+			qos:    "Burstable",
+		},
+		{name: "not a pod", cgroup: "0::/system.slice/docker-" + testContainerID + ".scope"},
+		{name: "pod tier without a pod", cgroup: "0::/kubepods.slice/kubepods-besteffort.slice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, qos := parsePodCgroup(tt.cgroup)
+			if uid != tt.uid || qos != tt.qos {
+				t.Errorf("parsePodCgroup = %q, %q, want %q, %q", uid, qos, tt.uid, tt.qos)
+			}
+		})
+	}
+}
+
+func TestPodOwner(t *testing.T) {
+	const uid = "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f"
+	tests := []struct {
+		name        string
+		pod         string
+		uid         string
+		labels      map[string]string
+		annotations map[string]string
+		kind, owner string
+	}{
+		{name: "deployment", pod: "web-7d9c8b5f4-x2x4q", labels: map[string]string{"app": "web", "pod-template-hash": "7d9c8b5f4"}, kind: "Deployment", owner: "web"},
+		{name: "truncated replicaset", pod: "a-very-long-name-x2x4q", labels: map[string]string{"pod-template-hash": "7d9c8b5f4"}, kind: "ReplicaSet", owner: "a-very-long-name"},
+		{name: "statefulset", pod: "postgres-2", labels: map[string]string{"controller-revision-hash": "postgres-6c9f8", "statefulset.kubernetes.io/pod-name": "postgres-2"}, kind: "StatefulSet", owner: "postgres"},
+		{name: "daemonset", pod: "fluent-bit-h7k2p", labels: map[string]string{"controller-revision-hash": "5d8c7", "pod-template-generation": "3"}, kind: "DaemonSet", owner: "fluent-bit"},
+		{name: "job", pod: "backup-28312345-q8wz2", labels: map[string]string{"batch.kubernetes.io/job-name": "backup-28312345", "job-name": "backup-28312345"}, kind: "Job", owner: "backup-28312345"},
+		{name: "static pod by annotation", pod: "kube-apiserver-node1", annotations: map[string]string{"kubernetes.io/config.source": "file"}, kind: "Node"},
+		{name: "static pod by uid", pod: "etcd-node1", uid: "6b1d4f0e9a8c7b6d5e4f3a2b1c0d9e8f", kind: "Node"},
+		{name: "bare pod", pod: "debug", labels: map[string]string{"run": "debug"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podUID := tt.uid
+			if podUID == "" {
+				podUID = uid
+			}
+			kind, owner := podOwner(tt.pod, podUID, tt.labels, tt.annotations)
+			if kind != tt.kind || owner != tt.owner {
+				t.Errorf("podOwner = %q, %q, want %q, %q", kind, owner, tt.kind, tt.owner)
+			}
+		})
+	}
+}
+
+func TestKubeletPod(t *testing.T) {
+	root := t.TempDir()
+	const uid = "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f"
+	for _, dir := range []string{
+		"var/log/pods/shop_web-7d9c8b5f4-x2x4q_" + uid + "/nginx",
+		"var/log/pods/shop_web-7d9c8b5f4-x2x4q_" + uid + "/istio-proxy",
+		"var/log/containers",
+		"var/lib/kubelet/pods/" + uid + "/containers/nginx",
+		"var/lib/kubelet/pods/" + uid + "/containers/istio-proxy",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(root, "var/log/containers", "web-7d9c8b5f4-x2x4q_shop_nginx-"+testContainerID+".log")
+	if err := os.Symlink("../pods/shop_web-7d9c8b5f4-x2x4q_"+uid+"/nginx/0.log", link); err != nil {
+		t.Fatal(err)
+	}
+	kubeletRoot = root
+	t.Cleanup(func() { kubeletRoot = "/" })
+
+	match := &model.ContainerMatch{Runtime: "k8s", ID: testContainerID, PodUID: uid}
+	kubeletPod(match)
+	if match.PodName != "web-7d9c8b5f4-x2x4q" || match.PodNamespace != "shop" || match.Name != "nginx" {
+		t.Errorf("kubeletPod = pod %q/%q, container %q; want shop/web-7d9c8b5f4-x2x4q, nginx", match.PodNamespace, match.PodName, match.Name)
+	}
+
+	// Without the container log link the container is only known when the
+	// pod has a single one.
+	other := &model.ContainerMatch{Runtime: "k8s", ID: "ffff" + testContainerID[4:], PodUID: uid}
+	kubeletPod(other)
+	if other.PodName != "web-7d9c8b5f4-x2x4q" || other.Name != "" {
+		t.Errorf("kubeletPod = pod %q, container %q; want web-7d9c8b5f4-x2x4q and no container", other.PodName, other.Name)
+	}
+}
+
+// TestKubernetesPodCached resolves a pod once and answers repeat lookups of
+// the same container from the cache.
+func TestKubernetesPodCached(t *testing.T) {
+	root := t.TempDir()
+	const uid = "0c1d2e3f-4a5b-4c6d-8e9f-a0b1c2d3e4f5"
+	logDir := filepath.Join(root, "var/log/pods", "jobs_report-28312345-q8wz2_"+uid)
+	if err := os.MkdirAll(filepath.Join(root, "var/lib/kubelet/pods", uid, "containers", "report"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	kubeletRoot = root
+	t.Cleanup(func() { kubeletRoot = "/" })
+	resetPodCache(t)
+
+	cgroup := "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod" + strings.ReplaceAll(uid, "-", "_") + ".slice/cri-containerd-" + testContainerID + ".scope\n"
+	first := KubernetesPod(cgroup)
+	if first == nil || first.PodName != "report-28312345-q8wz2" || first.PodNamespace != "jobs" || first.Name != "report" || first.PodQOSClass != "BestEffort" {
+		t.Fatalf("KubernetesPod = %+v, want jobs/report-28312345-q8wz2, container report, BestEffort", first)
+	}
+
+	// The pod's directories going away doesn't matter once it's resolved.
+	if err := os.RemoveAll(filepath.Join(root, "var")); err != nil {
+		t.Fatal(err)
+	}
+	if again := KubernetesPod(cgroup); again == nil || *again != *first {
+		t.Errorf("KubernetesPod again = %+v, want the cached %+v", again, first)
+	}
+}
+
+// resetPodCache empties the pod cache before and after a test.
+func resetPodCache(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		podCacheMu.Lock()
+		podCache = make(map[string]model.ContainerMatch)
+		podCachePruneAt = minPodCachePrune
+		podCacheMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
@@ -10,10 +10,10 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListLockedFiles uses `lsof -l` to surface file locks. macOS has no
+// listLockedFiles uses `lsof -l` to surface file locks. macOS has no
 // /proc/locks equivalent; this is best-effort coverage and may take a
 // noticeable beat on busy systems because lsof scans every open fd.
-func ListLockedFiles() []*model.LockedFile {
+func listLockedFiles() []*model.LockedFile {
 	// lsof may exit non-zero when it can't read one of the processes it
 	// scans (permission denied, process exiting mid-scan, etc.) while still
 	// emitting valid rows on stdout for the rest; salvage stdout when present.
@@ -10,10 +10,10 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListLockedFiles returns file locks observable via `fstat`. FreeBSD doesn't
+// listLockedFiles returns file locks observable via `fstat`. FreeBSD doesn't
 // expose a clean system-wide lock table the way /proc/locks does, so this is
 // best-effort: lines whose path or fd flags indicate a lock are emitted.
-func ListLockedFiles() []*model.LockedFile {
+func listLockedFiles() []*model.LockedFile {
 	out, err := exec.Command("fstat").Output()
 	if err != nil {
 		return nil
@@ -25,12 +25,12 @@ func statKey(info os.FileInfo) string {
 	return fmt.Sprintf("%d", st.Ino)
 }
 
-// ListLockedFiles returns every file lock currently held on the system,
+// listLockedFiles returns every file lock currently held on the system,
 // parsed from /proc/locks. Inodes are resolved to paths by scanning the
 // owning process's /proc/<pid>/fd/* — incomplete coverage is acceptable
 // (anonymous fds, vanished processes, etc. just get the device:inode literal).
-func ListLockedFiles() []*model.LockedFile {
-	data, err := os.ReadFile("/proc/locks")
+func listLockedFiles() []*model.LockedFile {
+	data, err := os.ReadFile(ProcPath("locks"))
 	if err != nil {
 		return nil
 	}
@@ -87,7 +87,7 @@ func resolveLockPath(pid int, devInode string, cache map[int]map[string]string)
 	m := make(map[string]string)
 	cache[pid] = m
 
-	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+	fdDir := pidPath(pid, "fd")
 	entries, err := os.ReadDir(fdDir)
 	if err != nil {
 		return ""
@@ -112,7 +112,7 @@ func lockProcessName(pid int, cache map[int]string) string {
 	if name, ok := cache[pid]; ok {
 		return name
 	}
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	data, err := os.ReadFile(pidPath(pid, "comm"))
 	if err != nil {
 		cache[pid] = ""
 		return ""
@@ -10,7 +10,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ListOpenPorts() ([]model.OpenPort, error) {
+func listOpenPorts() ([]model.OpenPort, error) {
 	cmd := exec.Command("lsof", "-i", "-P", "-n")
 	out, err := cmd.Output()
 	if err != nil {
@@ -48,7 +48,13 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 			}
 		}
 
-		nameField := fields[8] // Address:Port
+		nameField := fields[8] // Address:Port, or Local->Remote when connected
+		var remoteAddr string
+		var remotePort int
+		if local, remote, ok := strings.Cut(nameField, "->"); ok {
+			nameField = local
+			remoteAddr, remotePort = parseNetstatAddr(remote)
+		}
 		state := "UNKNOWN"
 		if len(fields) > 9 {
 			state = strings.Trim(fields[9], "()")
@@ -73,11 +79,13 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 
 		if port > 0 {
 			ports = append(ports, model.OpenPort{
-				PID:      pid,
-				Port:     port,
-				Address:  addr,
-				Protocol: protocol,
-				State:    state,
+				PID:           pid,
+				Port:          port,
+				Address:       addr,
+				Protocol:      protocol,
+				State:         state,
+				RemoteAddress: remoteAddr,
+				RemotePort:    remotePort,
 			})
 		}
 	}
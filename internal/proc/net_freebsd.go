@@ -10,8 +10,8 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListOpenPorts returns all open ports
-func ListOpenPorts() ([]model.OpenPort, error) {
+// listOpenPorts returns all open ports
+func listOpenPorts() ([]model.OpenPort, error) {
 	var openPorts []model.OpenPort
 	sockets := make(map[string]model.Socket)
 
@@ -26,11 +26,13 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 
 	for _, s := range sockets {
 		openPorts = append(openPorts, model.OpenPort{
-			PID:      extractPID(s.Inode),
-			Port:     s.Port,
-			Address:  s.Address,
-			Protocol: s.Protocol,
-			State:    s.State,
+			PID:           extractPID(s.Inode),
+			Port:          s.Port,
+			Address:       s.Address,
+			Protocol:      s.Protocol,
+			State:         s.State,
+			RemoteAddress: s.RemoteAddress,
+			RemotePort:    s.RemotePort,
 		})
 	}
 
@@ -86,12 +88,20 @@ func parseSockstatOutput(output string, sockets map[string]model.Socket) {
 		address, port := parseSockstatAddr(localAddr, proto)
 		if port > 0 {
 			inode := pid + ":" + strconv.Itoa(port) + ":" + address
+			// Connections accepted on one listening port share its local
+			// address; the far end tells them apart.
+			remoteAddr, remotePort := parseSockstatAddr(foreignAddr, proto)
+			if remotePort > 0 {
+				inode += "->" + remoteAddr + ":" + strconv.Itoa(remotePort)
+			}
 			sockets[inode] = model.Socket{
-				Inode:    inode,
-				Port:     port,
-				Address:  address,
-				Protocol: protocol,
-				State:    state,
+				Inode:         inode,
+				Port:          port,
+				Address:       address,
+				Protocol:      protocol,
+				State:         state,
+				RemoteAddress: remoteAddr,
+				RemotePort:    remotePort,
 			}
 		}
 	}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -57,6 +58,12 @@ var stateMap = map[string]string{
 }
 
 func readSockets() (map[string]model.Socket, error) {
+	return readSocketTables(ProcPath("net")), nil
+}
+
+// readSocketTables parses the TCP and UDP tables of netDir, a /proc/net or
+// /proc/<pid>/net directory, keyed by socket inode.
+func readSocketTables(netDir string) map[string]model.Socket {
 	sockets := make(map[string]model.Socket)
 
 	parse := func(path, proto string, ipv6 bool) {
@@ -76,6 +83,7 @@ func readSockets() (map[string]model.Socket, error) {
 			}
 
 			local := fields[1]
+			remote := fields[2]
 			stateHex := fields[3]
 			inode := fields[9]
 
@@ -85,22 +93,26 @@ func readSockets() (map[string]model.Socket, error) {
 			}
 
 			addr, port := parseAddr(local, ipv6)
-			sockets[inode] = model.Socket{
+			s := model.Socket{
 				Inode:    inode,
 				Port:     port,
 				Address:  addr,
 				State:    state,
 				Protocol: proto,
 			}
+			if raddr, rport := parseAddr(remote, ipv6); rport != 0 {
+				s.RemoteAddress, s.RemotePort = raddr, rport
+			}
+			sockets[inode] = s
 		}
 	}
 
-	parse("/proc/net/tcp", "TCP", false)
-	parse("/proc/net/tcp6", "TCP6", true)
-	parse("/proc/net/udp", "UDP", false)
-	parse("/proc/net/udp6", "UDP6", true)
+	parse(filepath.Join(netDir, "tcp"), "TCP", false)
+	parse(filepath.Join(netDir, "tcp6"), "TCP6", true)
+	parse(filepath.Join(netDir, "udp"), "UDP", false)
+	parse(filepath.Join(netDir, "udp6"), "UDP6", true)
 
-	return sockets, nil
+	return sockets
 }
 
 func parseAddr(raw string, ipv6 bool) (string, int) {
@@ -144,16 +156,28 @@ func parseAddr(raw string, ipv6 bool) (string, int) {
 	return ip, int(port)
 }
 
-func ListOpenPorts() ([]model.OpenPort, error) {
+func listOpenPorts() ([]model.OpenPort, error) {
 	sockets, err := readSockets()
 	if err != nil {
 		return nil, err
 	}
+	// /proc/net only shows witr's own network namespace; every other one's
+	// tables are read through a process inside it. Socket inodes are unique
+	// across namespaces, so the fd scan below matches them all.
+	namespaceOf := make(map[string]string)
+	for _, ns := range ForeignNetNamespaces() {
+		for inode, s := range readSocketTables(ProcPath(strconv.Itoa(ns.PID), "net")) {
+			if _, ok := sockets[inode]; !ok {
+				sockets[inode] = s
+				namespaceOf[inode] = ns.Inode
+			}
+		}
+	}
 
 	var openPorts []model.OpenPort
 
 	// Scan proc
-	procs, err := os.ReadDir("/proc")
+	procs, err := os.ReadDir(ProcPath())
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +192,7 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 		}
 
 		// Scan fds
-		fdPath := fmt.Sprintf("/proc/%d/fd", pid)
+		fdPath := pidPath(pid, "fd")
 		fds, err := os.ReadDir(fdPath)
 		if err != nil {
 			continue
@@ -183,11 +207,14 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 				inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
 				if s, ok := sockets[inode]; ok {
 					openPorts = append(openPorts, model.OpenPort{
-						PID:      pid,
-						Port:     s.Port,
-						Address:  s.Address,
-						Protocol: s.Protocol,
-						State:    s.State,
+						PID:           pid,
+						Port:          s.Port,
+						Address:       s.Address,
+						Protocol:      s.Protocol,
+						State:         s.State,
+						RemoteAddress: s.RemoteAddress,
+						RemotePort:    s.RemotePort,
+						NetNamespace:  namespaceOf[inode],
 					})
 				}
 			}
@@ -9,7 +9,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ListOpenPorts() ([]model.OpenPort, error) {
+func listOpenPorts() ([]model.OpenPort, error) {
 	out, err := exec.Command("netstat", "-ano").Output()
 	if err != nil {
 		return nil, err
@@ -63,14 +63,17 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 
 		port, err := strconv.Atoi(portStr)
 		if err == nil {
-			key := fmt.Sprintf("%d|%d|%s", pid, port, ip)
+			remoteIP, remotePort := netstatRemote(fields[2])
+			key := fmt.Sprintf("%d|%d|%s|%s|%d", pid, port, ip, remoteIP, remotePort)
 			if !seen[key] {
 				ports = append(ports, model.OpenPort{
-					PID:      pid,
-					Port:     port,
-					Address:  ip,
-					Protocol: proto,
-					State:    state,
+					PID:           pid,
+					Port:          port,
+					Address:       ip,
+					Protocol:      proto,
+					State:         state,
+					RemoteAddress: remoteIP,
+					RemotePort:    remotePort,
 				})
 				seen[key] = true
 			}
@@ -79,6 +82,21 @@ func ListOpenPorts() ([]model.OpenPort, error) {
 	return ports, nil
 }
 
+// netstatRemote parses netstat's foreign address column. Listening and UDP
+// sockets show a wildcard ("0.0.0.0:0", "[::]:0", "*:*"), returned as empty.
+func netstatRemote(addr string) (string, int) {
+	lastColon := strings.LastIndex(addr, ":")
+	if lastColon == -1 {
+		return "", 0
+	}
+	port, err := strconv.Atoi(addr[lastColon+1:])
+	if err != nil || port == 0 {
+		return "", 0
+	}
+	ip := strings.TrimSuffix(strings.TrimPrefix(addr[:lastColon], "["), "]")
+	return ip, port
+}
+
 // GetSocketsForPID returns every IP socket owned by a PID, including
 // non-listening sockets, by parsing `netstat -ano`.
 func GetSocketsForPID(pid int) []model.Socket {
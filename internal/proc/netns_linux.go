@@ -0,0 +1,97 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// netnsDir is where `ip netns add` bind-mounts named network namespaces.
+var netnsDir = "/run/netns"
+
+// netNamespaceOf returns the inode of a process's network namespace, from
+// the "net:[4026531840]" link of /proc/<pid>/ns/net, or "" when it can't be
+// read (other users' processes need root).
+func netNamespaceOf(pid string) string {
+	link, err := os.Readlink(ProcPath(pid, "ns", "net"))
+	if err != nil {
+		return ""
+	}
+	return parseNamespaceLink(link)
+}
+
+// parseNamespaceLink extracts the inode from a namespace link such as
+// "net:[4026531840]".
+func parseNamespaceLink(link string) string {
+	_, rest, ok := strings.Cut(link, ":[")
+	if !ok {
+		return ""
+	}
+	inode, ok := strings.CutSuffix(rest, "]")
+	if !ok {
+		return ""
+	}
+	return inode
+}
+
+// ForeignNetNamespaces lists the network namespaces other than witr's own,
+// each with the lowest PID in it, through whose /proc/<pid>/net the
+// namespace's socket tables can be read. /proc/net only shows witr's own
+// namespace, so ports bound inside containers that don't publish them, or
+// inside `ip netns` namespaces, are found only this way.
+func ForeignNetNamespaces() []model.NetNamespace {
+	own := netNamespaceOf("self")
+	if own == "" {
+		// witr isn't in the pid namespace of a --proc-root procfs.
+		own = netNamespaceOf("1")
+	}
+
+	first := make(map[string]int)
+	entries, _ := os.ReadDir(ProcPath())
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		ns := netNamespaceOf(entry.Name())
+		if ns == "" || ns == own {
+			continue
+		}
+		if cur, ok := first[ns]; !ok || pid < cur {
+			first[ns] = pid
+		}
+	}
+
+	namespaces := make([]model.NetNamespace, 0, len(first))
+	for inode, pid := range first {
+		namespaces = append(namespaces, model.NetNamespace{Inode: inode, PID: pid})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].PID < namespaces[j].PID })
+	return namespaces
+}
+
+// DescribeNetNamespace fills in the `ip netns` name bound to ns, if any, and
+// the container its representative process runs in.
+func DescribeNetNamespace(ns *model.NetNamespace) {
+	entries, _ := os.ReadDir(netnsDir)
+	for _, entry := range entries {
+		var st syscall.Stat_t
+		if syscall.Stat(filepath.Join(netnsDir, entry.Name()), &st) != nil {
+			continue
+		}
+		if strconv.FormatUint(uint64(st.Ino), 10) == ns.Inode {
+			ns.Name = entry.Name()
+			break
+		}
+	}
+	if p, err := readProcess(ns.PID); err == nil {
+		ns.Container = p.Container
+	}
+}
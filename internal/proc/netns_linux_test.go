@@ -0,0 +1,107 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseNamespaceLink(t *testing.T) {
+	for link, want := range map[string]string{
+		"net:[4026531840]": "4026531840",
+		"net:[":            "",
+		"garbage":          "",
+	} {
+		if got := parseNamespaceLink(link); got != want {
+			t.Errorf("parseNamespaceLink(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+// fakeNetnsProcfs builds a procfs where witr and init share one network
+// namespace and a container (pids 300 and 301) has its own, with a listener
+// on 8080 that only the container's tables show.
+func fakeNetnsProcfs(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	link := func(rel, target string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+	link("self/ns/net", "net:[4026531840]")
+	link("1/ns/net", "net:[4026531840]")
+	link("301/ns/net", "net:[4026532345]")
+	link("300/ns/net", "net:[4026532345]")
+	write("net/tcp", header)
+	write("net/tcp6", header)
+	write("300/net/tcp", header+"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 777 1 0000000000000000 100 0 0 10 0\n")
+	write("300/net/tcp6", header)
+	write("300/net/udp", header)
+	write("300/net/udp6", header)
+	link("301/fd/3", "socket:[777]")
+	return root
+}
+
+func TestForeignNetNamespaces(t *testing.T) {
+	SetProcRoot(fakeNetnsProcfs(t))
+	t.Cleanup(func() { SetProcRoot("") })
+
+	got := ForeignNetNamespaces()
+	want := []model.NetNamespace{{Inode: "4026532345", PID: 300}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ForeignNetNamespaces() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSocketStateInForeignNamespace(t *testing.T) {
+	SetProcRoot(fakeNetnsProcfs(t))
+	t.Cleanup(func() { SetProcRoot("") })
+	netnsDir = t.TempDir()
+	t.Cleanup(func() { netnsDir = "/run/netns" })
+
+	info := getSocketStateForPort(8080)
+	if info == nil || info.State != "LISTEN" {
+		t.Fatalf("getSocketStateForPort(8080) = %+v, want the container's listener", info)
+	}
+	if info.Namespace == nil || info.Namespace.Inode != "4026532345" || info.Namespace.PID != 300 {
+		t.Errorf("Namespace = %+v, want the container's namespace", info.Namespace)
+	}
+	if info := getSocketStateForPort(9090); info != nil {
+		t.Errorf("getSocketStateForPort(9090) = %+v, want nil", info)
+	}
+}
+
+func TestListOpenPortsInForeignNamespace(t *testing.T) {
+	SetProcRoot(fakeNetnsProcfs(t))
+	t.Cleanup(func() { SetProcRoot("") })
+
+	ports, err := listOpenPorts()
+	if err != nil {
+		t.Fatalf("listOpenPorts: %v", err)
+	}
+	want := []model.OpenPort{{PID: 301, Port: 8080, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN", NetNamespace: "4026532345"}}
+	if !reflect.DeepEqual(ports, want) {
+		t.Errorf("listOpenPorts() = %+v, want %+v", ports, want)
+	}
+}
@@ -10,11 +10,11 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListAllOpenFiles uses `lsof` to enumerate open files across all processes.
+// listAllOpenFiles uses `lsof` to enumerate open files across all processes.
 // Kernel-internal entries (sockets, pipes, kqueue, etc.) are filtered out so
 // the result roughly resembles "files a user would recognize on disk". This
 // can take a noticeable beat on busy systems.
-func ListAllOpenFiles() []*model.LockedFile {
+func listAllOpenFiles() []*model.LockedFile {
 	// lsof may exit non-zero when it can't read one of the processes it
 	// scans (permission denied, process exiting mid-scan, etc.) while still
 	// emitting valid rows on stdout for the rest; salvage stdout when present.
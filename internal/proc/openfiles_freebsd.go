@@ -10,10 +10,10 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListAllOpenFiles uses `fstat` to enumerate open files across all processes.
+// listAllOpenFiles uses `fstat` to enumerate open files across all processes.
 // Non-file entries (pipes, sockets, kqueue) and obvious noise are dropped so
 // the result resembles "files a user would recognize on disk".
-func ListAllOpenFiles() []*model.LockedFile {
+func listAllOpenFiles() []*model.LockedFile {
 	out, err := exec.Command("fstat").Output()
 	if err != nil {
 		return nil
@@ -3,7 +3,6 @@
 package proc
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -11,7 +10,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListAllOpenFiles walks /proc/<pid>/fd for every visible process and
+// listAllOpenFiles walks /proc/<pid>/fd for every visible process and
 // returns one LockedFile entry per open fd that resolves to a real file
 // path. Kernel-internal fds (sockets, pipes, anon_inodes, /proc, /sys,
 // /dev/null, /memfd) are dropped — they're virtually never what a user is
@@ -19,8 +18,8 @@ import (
 //
 // Type is set to "OPEN" and Mode is derived from /proc/<pid>/fdinfo flags
 // when readable; missing fdinfo just leaves Mode empty.
-func ListAllOpenFiles() []*model.LockedFile {
-	procDirs, err := os.ReadDir("/proc")
+func listAllOpenFiles() []*model.LockedFile {
+	procDirs, err := os.ReadDir(ProcPath())
 	if err != nil {
 		return nil
 	}
@@ -37,7 +36,7 @@ func ListAllOpenFiles() []*model.LockedFile {
 			continue
 		}
 
-		fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+		fdDir := pidPath(pid, "fd")
 		entries, err := os.ReadDir(fdDir)
 		if err != nil {
 			continue // permission denied or process gone
@@ -85,7 +84,7 @@ func isInterestingFile(target string) bool {
 // fdMode reads /proc/<pid>/fdinfo/<fd> for the O_ACCMODE flag bits and
 // returns "R", "W", or "RW". Returns "" if the file isn't readable.
 func fdMode(pid int, fd string) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd))
+	data, err := os.ReadFile(pidPath(pid, "fdinfo", fd))
 	if err != nil {
 		return ""
 	}
@@ -14,7 +14,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ReadProcess(pid int) (model.Process, error) {
+func readProcess(pid int) (model.Process, error) {
 	if pid <= 0 {
 		return model.Process{}, fmt.Errorf("invalid pid %d", pid)
 	}
@@ -14,7 +14,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ReadProcess(pid int) (model.Process, error) {
+func readProcess(pid int) (model.Process, error) {
 	// Reject PID 0 (and negatives): on FreeBSD `ps -p 0` returns the kernel
 	// swapper, which is not a real userland target. Matches the other platforms.
 	if pid <= 0 {
@@ -13,18 +13,18 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ReadProcess(pid int) (model.Process, error) {
+func readProcess(pid int) (model.Process, error) {
 	if pid <= 0 {
 		return model.Process{}, fmt.Errorf("invalid pid %d", pid)
 	}
 	// Verify process still exists before reading
-	if _, err := os.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(err) {
+	if _, err := os.Stat(pidPath(pid)); os.IsNotExist(err) {
 		return model.Process{}, fmt.Errorf("process %d does not exist", pid)
 	}
 
 	// Read all proc files in a logical order to minimize TOCTOU issues
 	// Start with stat file which is most likely to fail if process disappears
-	statPath := fmt.Sprintf("/proc/%d/stat", pid)
+	statPath := pidPath(pid, "stat")
 	stat, err := os.ReadFile(statPath)
 	if err != nil {
 		return model.Process{}, fmt.Errorf("process %d disappeared during read", pid)
@@ -32,7 +32,7 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Read environment variables
 	env := []string{}
-	envBytes, errEnv := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+	envBytes, errEnv := os.ReadFile(pidPath(pid, "environ"))
 	if errEnv == nil {
 		for _, e := range strings.Split(string(envBytes), "\x00") {
 			if e != "" {
@@ -44,7 +44,7 @@ func ReadProcess(pid int) (model.Process, error) {
 	health := "healthy"
 
 	// Working directory
-	var cwd, cwdErr = os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+	var cwd, cwdErr = os.Readlink(pidPath(pid, "cwd"))
 	if cwdErr != nil {
 		cwd = "unknown"
 	} else if cwd == "" {
@@ -53,10 +53,11 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Container detection
 	container := ""
-	var containerID, containerRuntime string
-	cgroupFile := fmt.Sprintf("/proc/%d/cgroup", pid)
+	var containerID, containerRuntime, session string
+	cgroupFile := pidPath(pid, "cgroup")
 	if cgroupData, err := os.ReadFile(cgroupFile); err == nil {
 		cgroupStr := string(cgroupData)
+		session = sessionFromCgroup(cgroupStr)
 		switch {
 		case strings.Contains(cgroupStr, "docker"):
 			container = "docker"
@@ -163,6 +164,9 @@ func ReadProcess(pid int) (model.Process, error) {
 	}
 
 	ppid, _ := strconv.Atoi(fields[1])
+	pgid, _ := strconv.Atoi(fields[2])
+	sid, _ := strconv.Atoi(fields[3])
+	ttyNr, _ := strconv.ParseInt(fields[4], 10, 64)
 	state := processState(fields)
 	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
 
@@ -250,7 +254,7 @@ func ReadProcess(pid int) (model.Process, error) {
 	}
 	// Full command line
 	cmdline := ""
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	cmdlineBytes, err := os.ReadFile(pidPath(pid, "cmdline"))
 	if err == nil {
 		cmd := strings.ReplaceAll(string(cmdlineBytes), "\x00", " ")
 		cmdline = strings.TrimSpace(cmd)
@@ -266,6 +270,8 @@ func ReadProcess(pid int) (model.Process, error) {
 		container = resolveDockerProxyContainer(cmdline)
 	}
 
+	exe, exeDeleted := readExe(pid)
+
 	return model.Process{
 		PID:              pid,
 		PPID:             ppid,
@@ -273,6 +279,11 @@ func ReadProcess(pid int) (model.Process, error) {
 		Cmdline:          cmdline,
 		StartedAt:        startedAt,
 		User:             user,
+		TTY:              ttyName(ttyNr),
+		Session:          session,
+		PGID:             pgid,
+		SID:              sid,
+		IgnoresHUP:       ignoresSignal(pid, 1),
 		CPUPercent:       cpuPercent,
 		MemoryRSS:        uint64(memBytes),
 		MemoryPercent:    memPercent,
@@ -287,7 +298,8 @@ func ReadProcess(pid int) (model.Process, error) {
 		Health:           health,
 		Forked:           forked,
 		Env:              env,
-		ExeDeleted:       isBinaryDeleted(pid),
+		Exe:              exe,
+		ExeDeleted:       exeDeleted,
 		Capabilities:     ReadCapabilities(pid),
 	}, nil
 }
@@ -302,7 +314,7 @@ var (
 // once rather than re-read on every ancestry hop.
 func totalMemoryBytes() uint64 {
 	totalMemOnce.Do(func() {
-		data, err := os.ReadFile("/proc/meminfo")
+		data, err := os.ReadFile(ProcPath("meminfo"))
 		if err != nil {
 			return
 		}
@@ -320,12 +332,31 @@ func totalMemoryBytes() uint64 {
 	return totalMemBytes
 }
 
-func isBinaryDeleted(pid int) bool {
-	exePath, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+// ignoresSignal reports whether the process has set sig to be ignored, from
+// the SigIgn mask in /proc/<pid>/status (bit sig-1).
+func ignoresSignal(pid, sig int) bool {
+	data, err := os.ReadFile(pidPath(pid, "status"))
 	if err != nil {
 		return false
 	}
-	return strings.HasSuffix(exePath, " (deleted)")
+	for line := range strings.Lines(string(data)) {
+		if mask, ok := strings.CutPrefix(line, "SigIgn:"); ok {
+			bits, err := strconv.ParseUint(strings.TrimSpace(mask), 16, 64)
+			return err == nil && bits&(1<<(sig-1)) != 0
+		}
+	}
+	return false
+}
+
+// readExe returns the process's executable path and whether it has been
+// deleted (or replaced) since the process started.
+func readExe(pid int) (string, bool) {
+	exePath, err := os.Readlink(pidPath(pid, "exe"))
+	if err != nil {
+		return "", false
+	}
+	path, deleted := strings.CutSuffix(exePath, " (deleted)")
+	return path, deleted
 }
 
 // The kernel emits the state immediately after the command, so fields[0] always carries it.
@@ -343,7 +374,7 @@ func processState(fields []string) string {
 // isDualStackEnabled checks if /proc/sys/net/ipv6/bindv6only is 0 (or missing),
 // which implies that IPv6 sockets can handle IPv4 traffic by default.
 func isDualStackEnabled() bool {
-	data, err := os.ReadFile("/proc/sys/net/ipv6/bindv6only")
+	data, err := os.ReadFile(ProcPath("sys", "net", "ipv6", "bindv6only"))
 	if err != nil {
 		return true
 	}
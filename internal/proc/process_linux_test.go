@@ -2,7 +2,11 @@
 
 package proc
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestProcessState(t *testing.T) {
 	t.Parallel()
@@ -152,3 +156,31 @@ func TestExtractLXCBasedContainerName(t *testing.T) {
 		})
 	}
 }
+
+func TestIgnoresSignal(t *testing.T) {
+	root := t.TempDir()
+	SetProcRoot(root)
+	t.Cleanup(func() { SetProcRoot("") })
+	for pid, mask := range map[string]string{
+		"10": "0000000000000001", // SIGHUP, as nohup leaves it
+		"11": "0000000000001000", // SIGPIPE only
+	} {
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		status := "Name:\tjob\nSigBlk:\t0000000000000000\nSigIgn:\t" + mask + "\nSigCgt:\t0000000000000000\n"
+		if err := os.WriteFile(filepath.Join(dir, "status"), []byte(status), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !ignoresSignal(10, 1) {
+		t.Error("pid 10 should ignore SIGHUP")
+	}
+	if ignoresSignal(11, 1) || !ignoresSignal(11, 13) {
+		t.Error("pid 11 should ignore SIGPIPE only")
+	}
+	if ignoresSignal(12, 1) {
+		t.Error("a missing process ignores nothing")
+	}
+}
@@ -13,17 +13,17 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListProcesses returns a list of all running processes with basic details (PID, Command, State).
+// listProcesses returns a list of all running processes with basic details (PID, Command, State).
 // This is used by the TUI to display the process list.
-func ListProcesses() ([]model.Process, error) {
-	// Use ps to fetch rich information efficiently: pid, ppid, user, lstart, %cpu, rss, %mem, args.
+func listProcesses() ([]model.Process, error) {
+	// Use ps to fetch rich information efficiently: pid, ppid, user, tty, lstart, %cpu, rss, %mem, args.
 	// comm is excluded from this row because it can contain spaces which breaks
 	// the strings.Fields column parse used below; it is fetched separately via
 	// readPIDCommMap() so the display name comes from an unambiguous source
 	// instead of being re-derived from the space-joined args.
 	// LC_ALL=C is required so lstart yields the expected 5-token English format;
 	// otherwise field offsets shift and %cpu picks up the rss column
-	cmd := exec.Command("ps", "-axo", "pid,ppid,user,lstart,%cpu,rss,%mem,args")
+	cmd := exec.Command("ps", "-axo", "pid,ppid,user,tty,lstart,%cpu,rss,%mem,args")
 	cmd.Env = buildEnvForPS()
 	out, err := cmd.Output()
 	if err != nil {
@@ -47,8 +47,8 @@ func ListProcesses() ([]model.Process, error) {
 		}
 		fields := strings.Fields(line)
 
-		// Expected minimum fields: pid(1) + ppid(1) + user(1) + lstart(5) + cpu(1) + rss(1) + mem(1) = 11
-		if len(fields) < 11 {
+		// Expected minimum fields: pid(1) + ppid(1) + user(1) + tty(1) + lstart(5) + cpu(1) + rss(1) + mem(1) = 12
+		if len(fields) < 12 {
 			continue
 		}
 
@@ -61,20 +61,21 @@ func ListProcesses() ([]model.Process, error) {
 			continue
 		}
 		user := fields[2]
+		tty := psTTY(fields[3])
 
 		// lstart format: "Mon Jan 1 12:00:00 2024" (5 fields)
-		timeStr := strings.Join(fields[3:8], " ")
+		timeStr := strings.Join(fields[4:9], " ")
 		started, _ := time.Parse("Mon Jan 2 15:04:05 2006", timeStr)
 
-		cpu, _ := strconv.ParseFloat(fields[8], 64)
-		rss, _ := strconv.ParseUint(fields[9], 10, 64)
+		cpu, _ := strconv.ParseFloat(fields[9], 64)
+		rss, _ := strconv.ParseUint(fields[10], 10, 64)
 		rss *= 1024
 
-		mem, _ := strconv.ParseFloat(fields[10], 64)
+		mem, _ := strconv.ParseFloat(fields[11], 64)
 
 		cmdline := ""
-		if len(fields) > 11 {
-			cmdline = strings.Join(fields[11:], " ")
+		if len(fields) > 12 {
+			cmdline = strings.Join(fields[12:], " ")
 		}
 
 		// Prefer the separately-captured comm value over an args-based extractor
@@ -83,8 +84,8 @@ func ListProcesses() ([]model.Process, error) {
 		if displayName == "" {
 			displayName = extractExecutableName(cmdline)
 		}
-		if displayName == "" && len(fields) > 11 {
-			displayName = fields[11]
+		if displayName == "" && len(fields) > 12 {
+			displayName = fields[12]
 		}
 		if displayName == "" {
 			continue
@@ -98,6 +99,7 @@ func ListProcesses() ([]model.Process, error) {
 			PPID:          ppid,
 			Command:       displayName,
 			User:          user,
+			TTY:           tty,
 			StartedAt:     started,
 			CPUPercent:    cpu,
 			MemoryRSS:     rss,
@@ -109,9 +111,9 @@ func ListProcesses() ([]model.Process, error) {
 	return processes, nil
 }
 
-// ListProcessSnapshot collects a lightweight view of running processes
+// listProcessSnapshot collects a lightweight view of running processes
 // for child/descendant discovery. We use ps on FreeBSD similar to Darwin.
-func ListProcessSnapshot() ([]model.Process, error) {
+func listProcessSnapshot() ([]model.Process, error) {
 	out, err := exec.Command("ps", "-axo", "pid=,ppid=,comm=").Output()
 	if err != nil {
 		return nil, fmt.Errorf("ps process list: %w", err)
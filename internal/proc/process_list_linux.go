@@ -12,14 +12,14 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ListProcesses returns all running processes with the columns the TUI renders
+// listProcesses returns all running processes with the columns the TUI renders
 // (PID, PPID, command, user, start time, CPU%, RSS, mem%, command line). It
 // reads /proc directly instead of forking `ps -axo`, computing the same
 // lifetime-average CPU% that ps reports — no subprocess per refresh.
-func ListProcesses() ([]model.Process, error) {
-	entries, err := os.ReadDir("/proc")
+func listProcesses() ([]model.Process, error) {
+	entries, err := os.ReadDir(ProcPath())
 	if err != nil {
-		return nil, fmt.Errorf("read /proc: %w", err)
+		return nil, fmt.Errorf("read %s: %w", ProcPath(), err)
 	}
 
 	// Per-list invariants, computed once rather than per process.
@@ -48,7 +48,7 @@ func ListProcesses() ([]model.Process, error) {
 // mirroring the fields the old `ps -axo` invocation produced. Returns ok=false
 // when the process vanished mid-read or its stat is malformed.
 func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize float64) (model.Process, bool) {
-	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	stat, err := os.ReadFile(pidPath(pid, "stat"))
 	if err != nil {
 		return model.Process{}, false
 	}
@@ -65,6 +65,7 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 	}
 
 	ppid, _ := strconv.Atoi(fields[1])
+	ttyNr, _ := strconv.ParseInt(fields[4], 10, 64)
 	utime, _ := strconv.ParseFloat(fields[11], 64)
 	stime, _ := strconv.ParseFloat(fields[12], 64)
 	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
@@ -84,7 +85,7 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 	}
 
 	cmdline := ""
-	if b, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+	if b, err := os.ReadFile(pidPath(pid, "cmdline")); err == nil {
 		cmdline = strings.TrimSpace(strings.ReplaceAll(string(b), "\x00", " "))
 	}
 	displayName := deriveDisplayCommand(comm, cmdline)
@@ -101,6 +102,8 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 		Command:       displayName,
 		Cmdline:       cmdline,
 		User:          readUser(pid),
+		TTY:           ttyName(ttyNr),
+		Session:       sessionFromCgroup(readCgroup(pid)),
 		StartedAt:     startedAt,
 		CPUPercent:    cpuPercent,
 		MemoryRSS:     uint64(memBytes),
@@ -108,13 +111,48 @@ func readProcessListEntry(pid, ticks int, boot time.Time, totalMem, pageSize flo
 	}, true
 }
 
-// ListProcessSnapshot collects a lightweight view of running processes
+// ttyName decodes the tty_nr field of /proc/<pid>/stat into the name ps
+// shows: "pts/3", "tty1", "ttyS0" or "console". It returns "" for processes
+// without a controlling terminal or on a device it doesn't know.
+func ttyName(nr int64) string {
+	if nr <= 0 {
+		return ""
+	}
+	major := (nr >> 8) & 0xfff
+	minor := (nr & 0xff) | ((nr >> 12) & 0xfff00)
+	switch {
+	case major >= 136 && major <= 143:
+		return fmt.Sprintf("pts/%d", (major-136)*256+minor)
+	case major == 4 && minor < 64:
+		return fmt.Sprintf("tty%d", minor)
+	case major == 4:
+		return fmt.Sprintf("ttyS%d", minor-64)
+	case major == 5 && minor == 1:
+		return "console"
+	}
+	return ""
+}
+
+// sessionFromCgroup returns the logind session a process belongs to, from
+// the session-<id>.scope in its cgroup, or "" outside a login session.
+func sessionFromCgroup(content string) string {
+	for line := range strings.Lines(content) {
+		for _, seg := range strings.Split(strings.TrimSpace(line), "/") {
+			if id, ok := strings.CutPrefix(seg, "session-"); ok && strings.HasSuffix(id, ".scope") {
+				return strings.TrimSuffix(id, ".scope")
+			}
+		}
+	}
+	return ""
+}
+
+// listProcessSnapshot collects a lightweight view of running processes
 // for child/descendant discovery. We avoid full ReadProcess calls to keep
 // this path fast and to reduce permission-sensitive reads.
-func ListProcessSnapshot() ([]model.Process, error) {
-	entries, err := os.ReadDir("/proc")
+func listProcessSnapshot() ([]model.Process, error) {
+	entries, err := os.ReadDir(ProcPath())
 	if err != nil {
-		return nil, fmt.Errorf("read /proc: %w", err)
+		return nil, fmt.Errorf("read %s: %w", ProcPath(), err)
 	}
 
 	processes := make([]model.Process, 0, len(entries))
@@ -128,7 +166,7 @@ func ListProcessSnapshot() ([]model.Process, error) {
 			continue
 		}
 
-		statPath := fmt.Sprintf("/proc/%d/stat", pid)
+		statPath := pidPath(pid, "stat")
 		stat, err := os.ReadFile(statPath)
 		if err != nil {
 			continue
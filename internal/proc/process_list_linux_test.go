@@ -40,3 +40,41 @@ func TestParseStatSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func TestTTYName(t *testing.T) {
+	tests := []struct {
+		nr   int64
+		want string
+	}{
+		{0, ""},
+		{136<<8 | 3, "pts/3"},
+		{137<<8 | 4, "pts/260"},
+		{136<<8 | 0x100<<12, "pts/256"},
+		{4<<8 | 1, "tty1"},
+		{4<<8 | 64, "ttyS0"},
+		{5<<8 | 1, "console"},
+		{10<<8 | 1, ""},
+	}
+	for _, tt := range tests {
+		if got := ttyName(tt.nr); got != tt.want {
+			t.Errorf("ttyName(%#x) = %q, want %q", tt.nr, got, tt.want)
+		}
+	}
+}
+
+func TestSessionFromCgroup(t *testing.T) {
+	tests := []struct {
+		cgroup string
+		want   string
+	}{
+		{"0::/user.slice/user-1000.slice/session-3.scope\n", "3"},
+		{"12:pids:/user.slice/user-1000.slice/session-c2.scope\n0::/user.slice/user-1000.slice/session-c2.scope\n", "c2"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service\n", ""},
+		{"0::/system.slice/nginx.service\n", ""},
+	}
+	for _, tt := range tests {
+		if got := sessionFromCgroup(tt.cgroup); got != tt.want {
+			t.Errorf("sessionFromCgroup(%q) = %q, want %q", tt.cgroup, got, tt.want)
+		}
+	}
+}
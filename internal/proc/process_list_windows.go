@@ -3,10 +3,13 @@
 package proc
 
 import (
+	"strconv"
+
 	"github.com/pranshuparmar/witr/pkg/model"
+	"golang.org/x/sys/windows"
 )
 
-// ListProcesses returns all running processes with the columns the TUI renders:
+// listProcesses returns all running processes with the columns the TUI renders:
 // PID/PPID/command name, owner, start time, command line, CPU% and resident
 // memory. Each process is opened to read its metrics; fields that can't be read
 // (protected/system processes without elevation) are left empty rather than
@@ -15,7 +18,7 @@ import (
 // The command line is read via NtQueryInformationProcess (windowsProcessCmdline)
 // rather than a PEB walk, so it performs no remote process-memory access and is
 // safe to call across every process.
-func ListProcesses() ([]model.Process, error) {
+func listProcesses() ([]model.Process, error) {
 	procs, err := enumerateProcesses()
 	if err != nil {
 		return nil, err
@@ -30,6 +33,7 @@ func ListProcesses() ([]model.Process, error) {
 			Command:       p.Exe,
 			Cmdline:       windowsProcessCmdline(p.PID),
 			User:          readUser(p.PID),
+			Session:       windowsSession(p.PID),
 			StartedAt:     started,
 			CPUPercent:    cpu,
 			MemoryRSS:     rss,
@@ -40,10 +44,10 @@ func ListProcesses() ([]model.Process, error) {
 	return out, nil
 }
 
-// ListProcessSnapshot collects a lightweight view of running processes for
+// listProcessSnapshot collects a lightweight view of running processes for
 // child/descendant discovery. Backed by ToolHelp32 (no PowerShell, no WMI) so
 // it never blocks on a stalled CIM provider.
-func ListProcessSnapshot() ([]model.Process, error) {
+func listProcessSnapshot() ([]model.Process, error) {
 	procs, err := enumerateProcesses()
 	if err != nil {
 		return nil, err
@@ -58,3 +62,14 @@ func ListProcessSnapshot() ([]model.Process, error) {
 	}
 	return out, nil
 }
+
+// windowsSession returns the Terminal Services session a process runs in
+// ("0" for services, "1" and up for interactive logons), or "" when it
+// can't be queried.
+func windowsSession(pid int) string {
+	var id uint32
+	if err := windows.ProcessIdToSessionId(uint32(pid), &id); err != nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func ReadProcess(pid int) (model.Process, error) {
+func readProcess(pid int) (model.Process, error) {
 	// PID 0 is the System Idle Process on Windows (and negative PIDs are never
 	// valid), so reject them rather than returning the idle pseudo-process —
 	// this matches the other platforms, where /proc/0 or `ps -p 0` fails.
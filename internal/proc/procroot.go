@@ -0,0 +1,38 @@
+package proc
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// ProcRootEnv names the environment variable that overrides where the Linux
+// readers find procfs, like --proc-root.
+const ProcRootEnv = "WITR_PROC_ROOT"
+
+// procRoot is the procfs mount every Linux reader goes through. It's "/proc"
+// unless witr runs in a sidecar against a bind-mounted host procfs (e.g.
+// /host/proc) or a test points it at a synthetic tree.
+var procRoot = "/proc"
+
+// SetProcRoot changes the procfs mount the Linux readers use. An empty root
+// restores "/proc".
+func SetProcRoot(root string) {
+	if root == "" {
+		root = "/proc"
+	}
+	procRoot = filepath.Clean(root)
+}
+
+// ProcRoot returns the procfs mount the Linux readers use.
+func ProcRoot() string { return procRoot }
+
+// ProcPath joins elem onto the procfs root: ProcPath("net", "tcp") is
+// "/proc/net/tcp" by default.
+func ProcPath(elem ...string) string {
+	return filepath.Join(append([]string{procRoot}, elem...)...)
+}
+
+// pidPath is ProcPath for a per-process entry: pidPath(42, "stat").
+func pidPath(pid int, elem ...string) string {
+	return ProcPath(append([]string{strconv.Itoa(pid)}, elem...)...)
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeProcfs builds a minimal procfs tree: init (pid 1) and a docker-hosted
+// nginx (pid 4242) holding a flock. Only the files the readers under test
+// touch are present.
+func fakeProcfs(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stat := func(pid, comm, ppid string) string {
+		return pid + " (" + comm + ") S " + ppid + " 1 1 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 100 1000 10\n"
+	}
+
+	write("1/stat", stat("1", "systemd", "0"))
+	write("1/comm", "systemd\n")
+	write("1/cmdline", "/sbin/init\x00")
+
+	write("4242/stat", stat("4242", "nginx", "1"))
+	write("4242/comm", "nginx\n")
+	write("4242/cmdline", "nginx\x00-g\x00daemon off;\x00")
+	write("4242/environ", "PATH=/usr/bin\x00NGINX_PORT=8080\x00")
+	write("4242/cgroup", "0::/system.slice/docker-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.scope\n")
+
+	write("locks", "1: FLOCK  ADVISORY  WRITE 4242 08:01:1234 0 EOF\n")
+	write("stat", "btime 1700000000\n")
+	return root
+}
+
+func TestProcRootRedirectsReaders(t *testing.T) {
+	SetProcRoot(fakeProcfs(t))
+	t.Cleanup(func() { SetProcRoot("") })
+
+	procs, err := listProcessSnapshot()
+	if err != nil {
+		t.Fatalf("listProcessSnapshot: %v", err)
+	}
+	if len(procs) != 2 {
+		t.Fatalf("got %d processes from the fake tree, want 2: %+v", len(procs), procs)
+	}
+
+	p, err := readProcess(4242)
+	if err != nil {
+		t.Fatalf("readProcess: %v", err)
+	}
+	if p.PPID != 1 || p.Cmdline != "nginx -g daemon off;" {
+		t.Errorf("readProcess = {PPID:%d Cmdline:%q}, want the fake nginx", p.PPID, p.Cmdline)
+	}
+	if p.ContainerRuntime != "docker" || p.ContainerID == "" {
+		t.Errorf("container = %q/%q, want docker from the fake cgroup", p.ContainerRuntime, p.ContainerID)
+	}
+
+	if !pidBelongsToContainer(4242, "0123456789ab") {
+		t.Error("cgroup membership should be read from the fake tree")
+	}
+
+	locks := listLockedFiles()
+	if len(locks) != 1 || locks[0].PID != 4242 || locks[0].Process != "nginx" {
+		t.Errorf("listLockedFiles = %+v, want nginx's flock", locks)
+	}
+
+	// A live PID that isn't in the fake tree must not leak through.
+	if _, err := readProcess(os.Getpid()); err == nil {
+		t.Error("readProcess of a PID absent from the fake tree should fail")
+	}
+}
+
+func TestSetProcRootEmptyRestoresDefault(t *testing.T) {
+	SetProcRoot("/host/proc/")
+	if got := ProcPath("net", "tcp"); got != "/host/proc/net/tcp" {
+		t.Errorf("ProcPath = %q, want /host/proc/net/tcp", got)
+	}
+	SetProcRoot("")
+	if got := pidPath(7, "stat"); got != "/proc/7/stat" {
+		t.Errorf("pidPath after reset = %q, want /proc/7/stat", got)
+	}
+}
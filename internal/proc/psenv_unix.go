@@ -53,3 +53,12 @@ func readPIDCommMap() map[int]string {
 	}
 	return m
 }
+
+// psTTY normalizes ps's tty column: "??" (macOS) and "-" (FreeBSD) mean the
+// process has no controlling terminal.
+func psTTY(col string) string {
+	if col == "??" || col == "-" {
+		return ""
+	}
+	return col
+}
@@ -0,0 +1,159 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Replay answers the package's host queries from a previously captured
+// snapshot instead of the live system (witr --from-snapshot). Implemented by
+// the snapshot package; kept as an interface here so proc doesn't depend on the
+// archive format.
+type Replay interface {
+	Process(pid int) (model.Process, bool)
+	Processes() []model.Process
+	OpenPorts() []model.OpenPort
+	LockedFiles() []*model.LockedFile
+	OpenFiles() []*model.LockedFile
+	Containers() []*model.ContainerMatch
+	ContainerHostPID(runtime, id string) int
+	ContainerHealthcheck(id string) string
+	Cgroup(pid int) string
+	Source(pid int) (model.Source, bool)
+}
+
+// ErrReplayUnavailable is returned by queries that can only be answered by the
+// live system (unix socket lookups, capturing a snapshot) while a snapshot is
+// being replayed.
+var ErrReplayUnavailable = errors.New("not available when replaying a snapshot")
+
+var activeReplay Replay
+
+// SetReplay routes every host query through r. Passing nil restores live reads.
+func SetReplay(r Replay) { activeReplay = r }
+
+// ActiveReplay returns the snapshot being replayed, or nil when reading the
+// live system.
+func ActiveReplay() Replay { return activeReplay }
+
+// ReadProcess reads everything witr reports about a single process.
+func ReadProcess(pid int) (model.Process, error) {
+	if activeReplay == nil {
+		return readProcess(pid)
+	}
+	if p, ok := activeReplay.Process(pid); ok {
+		return p, nil
+	}
+	return model.Process{}, fmt.Errorf("process %d does not exist", pid)
+}
+
+// ListProcesses returns every process with the columns the TUI list renders.
+func ListProcesses() ([]model.Process, error) {
+	if activeReplay == nil {
+		return listProcesses()
+	}
+	return activeReplay.Processes(), nil
+}
+
+// ListProcessSnapshot returns a lightweight PID/PPID/command view of every
+// process, used for child discovery.
+func ListProcessSnapshot() ([]model.Process, error) {
+	if activeReplay == nil {
+		return listProcessSnapshot()
+	}
+	return activeReplay.Processes(), nil
+}
+
+// ListOpenPorts returns every socket on the host together with its owning PID.
+func ListOpenPorts() ([]model.OpenPort, error) {
+	if activeReplay == nil {
+		return listOpenPorts()
+	}
+	return activeReplay.OpenPorts(), nil
+}
+
+// ResolveSocketPeers fills in the local process on the other end of each
+// established TCP connection in sockets. A snapshot has no fd table to
+// consult, so replayed sockets are returned as they are.
+func ResolveSocketPeers(sockets []model.Socket) []model.Socket {
+	if activeReplay != nil {
+		return sockets
+	}
+	return resolveSocketPeers(sockets)
+}
+
+// ListLockedFiles returns every file lock currently held on the host.
+func ListLockedFiles() []*model.LockedFile {
+	if activeReplay == nil {
+		return listLockedFiles()
+	}
+	return activeReplay.LockedFiles()
+}
+
+// ListAllOpenFiles returns the regular files held open by every process.
+func ListAllOpenFiles() []*model.LockedFile {
+	if activeReplay == nil {
+		return listAllOpenFiles()
+	}
+	return activeReplay.OpenFiles()
+}
+
+// PIDBelongsToContainer reports whether the host process pid runs inside the
+// given container.
+func PIDBelongsToContainer(pid int, containerID string) bool {
+	if activeReplay == nil {
+		return pidBelongsToContainer(pid, containerID)
+	}
+	return pid > 0 && containerID != "" && strings.Contains(activeReplay.Cgroup(pid), containerID)
+}
+
+// ReadCgroup returns the process's raw cgroup membership (Linux
+// /proc/<pid>/cgroup), or "" when unavailable.
+func ReadCgroup(pid int) string {
+	if activeReplay == nil {
+		return readCgroup(pid)
+	}
+	return activeReplay.Cgroup(pid)
+}
+
+// GetSocketStateForPort returns the most relevant socket state for a port. A
+// replayed snapshot only carries each socket's local side, so RemoteAddr is
+// left empty there.
+func GetSocketStateForPort(port int) *model.SocketInfo {
+	if activeReplay == nil {
+		return getSocketStateForPort(port)
+	}
+	var best *model.SocketInfo
+	for _, op := range activeReplay.OpenPorts() {
+		if op.Port != port || !strings.HasPrefix(op.Protocol, "TCP") {
+			continue
+		}
+		info := &model.SocketInfo{Port: port, State: op.State, LocalAddr: op.Address}
+		switch {
+		case best == nil:
+			best = info
+		case replayStateRank(info.State) < replayStateRank(best.State):
+			best = info
+		}
+	}
+	if best != nil {
+		addStateExplanation(best)
+	}
+	return best
+}
+
+// replayStateRank mirrors the live readers' preference: states that explain a
+// stuck port first, then LISTEN, then anything else.
+func replayStateRank(state string) int {
+	switch state {
+	case "TIME_WAIT", "CLOSE_WAIT", "FIN_WAIT_1", "FIN_WAIT_2", "FIN_WAIT1", "FIN_WAIT2":
+		return 0
+	case "LISTEN":
+		return 1
+	default:
+		return 2
+	}
+}
@@ -96,7 +96,7 @@ func checkPreventsSleep(pid int) bool {
 
 // detect if process is in a stopped/suspended state
 func getAppNapped(pid int) bool {
-	statFile := fmt.Sprintf("/proc/%d/stat", pid)
+	statFile := pidPath(pid, "stat")
 	data, err := os.ReadFile(statFile)
 	if err != nil {
 		return false
@@ -43,7 +43,7 @@ func (crictlRuntime) List() []*model.ContainerMatch {
 	var matches []*model.ContainerMatch
 	for _, c := range payload.Containers {
 		started, _ := time.Parse(time.RFC3339Nano, c.CreatedAt)
-		matches = append(matches, &model.ContainerMatch{
+		match := &model.ContainerMatch{
 			Runtime:   "k8s",
 			ID:        c.ID,
 			Name:      c.Metadata.Name,
@@ -51,7 +51,9 @@ func (crictlRuntime) List() []*model.ContainerMatch {
 			State:     strings.TrimPrefix(c.State, "CONTAINER_"),
 			Status:    strings.TrimPrefix(c.State, "CONTAINER_"),
 			StartedAt: started,
-		})
+		}
+		applyPodLabels(match, c.Labels)
+		matches = append(matches, match)
 	}
 	return matches
 }
@@ -62,7 +64,8 @@ func (crictlRuntime) HostPID(id string) int {
 }
 
 // Enrich populates Command, Mounts, and a more precise StartedAt by calling
-// `crictl inspect` for the resolved container. Skips fields the inspect
+// `crictl inspect` for the resolved container, and the pod's QoS class and
+// owner from its cgroup and `crictl inspectp`. Skips fields the inspect
 // payload doesn't carry; partial enrichment is fine.
 func (crictlRuntime) Enrich(match *model.ContainerMatch) {
 	payload, ok := crictlInspect(match.ID)
@@ -88,11 +91,22 @@ func (crictlRuntime) Enrich(match *model.ContainerMatch) {
 			match.StartedAt = t
 		}
 	}
+	applyPodLabels(match, payload.Status.Labels)
+	if payload.Info.Pid > 0 {
+		if _, qos := parsePodCgroup(readCgroup(payload.Info.Pid)); qos != "" {
+			match.PodQOSClass = qos
+		}
+	}
+	crictlPodOwner(match, payload.Info.SandboxID)
 }
 
 type crictlInspectPayload struct {
 	Status struct {
-		StartedAt string `json:"startedAt"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Labels    map[string]string `json:"labels"`
+		StartedAt string            `json:"startedAt"`
 		Mounts    []struct {
 			ContainerPath string `json:"containerPath"`
 			HostPath      string `json:"hostPath"`
@@ -100,7 +114,8 @@ type crictlInspectPayload struct {
 		} `json:"mounts"`
 	} `json:"status"`
 	Info struct {
-		Pid         int `json:"pid"`
+		Pid         int    `json:"pid"`
+		SandboxID   string `json:"sandboxID"`
 		RuntimeSpec struct {
 			Process struct {
 				Args []string `json:"args"`
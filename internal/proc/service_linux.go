@@ -3,7 +3,6 @@
 package proc
 
 import (
-	"fmt"
 	"os"
 	"strings"
 )
@@ -14,7 +13,7 @@ import (
 // Returns "" when the process belongs to a .scope (login session, app scope) or
 // to no systemd unit.
 func serviceFromCgroup(pid int) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	data, err := os.ReadFile(pidPath(pid, "cgroup"))
 	if err != nil {
 		return ""
 	}
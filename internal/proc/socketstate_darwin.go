@@ -60,9 +60,9 @@ func GetSocketStates(port int) ([]model.SocketInfo, error) {
 	return sockets, nil
 }
 
-// GetSocketStateForPort returns the most relevant socket state for a port
+// getSocketStateForPort returns the most relevant socket state for a port
 // Prioritizes non-LISTEN states that explain why a port might be unavailable
-func GetSocketStateForPort(port int) *model.SocketInfo {
+func getSocketStateForPort(port int) *model.SocketInfo {
 	states, err := GetSocketStates(port)
 	if err != nil || len(states) == 0 {
 		return nil
@@ -6,17 +6,48 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// GetSocketStateForPort returns the socket state for a port
-// Linux implementation using /proc/net/tcp and /proc/net/tcp6
-func GetSocketStateForPort(port int) *model.SocketInfo {
+// getSocketStateForPort returns the socket state for a port
+// Linux implementation using /proc/net/tcp and /proc/net/tcp6. A port nothing
+// listens on in witr's own network namespace is looked up in the others, and
+// a listener found there is reported with its namespace.
+func getSocketStateForPort(port int) *model.SocketInfo {
+	states := socketStatesForPort(ProcPath("net"), port)
+	if !hasListener(states) {
+		for _, ns := range ForeignNetNamespaces() {
+			foreign := socketStatesForPort(pidPath(ns.PID, "net"), port)
+			if !hasListener(foreign) {
+				continue
+			}
+			info := pickSocketState(foreign)
+			DescribeNetNamespace(&ns)
+			info.Namespace = &ns
+			return info
+		}
+	}
+	return pickSocketState(states)
+}
+
+func hasListener(states []model.SocketInfo) bool {
+	for _, s := range states {
+		if s.State == "LISTEN" {
+			return true
+		}
+	}
+	return false
+}
+
+// socketStatesForPort reads the TCP sockets on port from the tcp and tcp6
+// tables of netDir, a /proc/net or /proc/<pid>/net directory.
+func socketStatesForPort(netDir string, port int) []model.SocketInfo {
 	// Check both IPv4 and IPv6
-	files := []string{"/proc/net/tcp", "/proc/net/tcp6"}
+	files := []string{filepath.Join(netDir, "tcp"), filepath.Join(netDir, "tcp6")}
 
 	var states []model.SocketInfo
 
@@ -66,6 +97,11 @@ func GetSocketStateForPort(port int) *model.SocketInfo {
 		}()
 	}
 
+	return states
+}
+
+// pickSocketState chooses the state worth reporting among a port's sockets.
+func pickSocketState(states []model.SocketInfo) *model.SocketInfo {
 	if len(states) == 0 {
 		return nil
 	}
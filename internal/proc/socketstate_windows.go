@@ -10,7 +10,7 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func GetSocketStateForPort(port int) *model.SocketInfo {
+func getSocketStateForPort(port int) *model.SocketInfo {
 	// netstat -ano
 	out, err := exec.Command("netstat", "-ano").Output()
 	if err != nil {
@@ -0,0 +1,125 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// resolveSocketPeers finds, for each established TCP connection, the socket
+// with the reverse tuple (local and remote swapped). One only exists when
+// both ends are on this host, so no list of local addresses is needed. The
+// process holding that socket is the peer.
+func resolveSocketPeers(sockets []model.Socket) []model.Socket {
+	table, err := readSocketsCached()
+	if err != nil {
+		return sockets
+	}
+
+	byTuple := make(map[string]string)
+	for inode, s := range table {
+		if s.State == "ESTABLISHED" && s.RemotePort != 0 {
+			byTuple[tupleKey(s.Address, s.Port, s.RemoteAddress, s.RemotePort)] = inode
+		}
+	}
+
+	wanted := make(map[string]bool)
+	out := make([]model.Socket, len(sockets))
+	copy(out, sockets)
+	for i, s := range out {
+		if s.State != "ESTABLISHED" || s.RemotePort == 0 {
+			continue
+		}
+		if peer, ok := byTuple[tupleKey(s.RemoteAddress, s.RemotePort, s.Address, s.Port)]; ok && peer != s.Inode {
+			out[i].Peer = peer
+			wanted[peer] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return out
+	}
+
+	holders := inodeHolders(wanted)
+	for i, s := range out {
+		if pid, ok := holders[s.Peer]; ok {
+			out[i].PeerPID = pid
+			out[i].PeerCommand = commandName(pid)
+		}
+	}
+	return out
+}
+
+// tupleKey identifies a connection by its endpoints. IPv4-mapped IPv6
+// addresses are folded to IPv4, so a dual-stack server's tcp6 socket matches
+// a client's tcp one.
+func tupleKey(addr string, port int, raddr string, rport int) string {
+	norm := func(a string) string {
+		if ip := net.ParseIP(a); ip != nil {
+			if v4 := ip.To4(); v4 != nil {
+				return v4.String()
+			}
+			return ip.String()
+		}
+		return a
+	}
+	return fmt.Sprintf("%s|%d|%s|%d", norm(addr), port, norm(raddr), rport)
+}
+
+// inodeHolders scans every process's fds for the given socket inodes and
+// maps each one found to the first PID holding it.
+func inodeHolders(inodes map[string]bool) map[string]int {
+	holders := make(map[string]int)
+	entries, err := os.ReadDir(ProcPath())
+	if err != nil {
+		return holders
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := pidPath(pid, "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			rest, ok := strings.CutPrefix(link, "socket:[")
+			if !ok {
+				continue
+			}
+			inode := strings.TrimSuffix(rest, "]")
+			if _, seen := holders[inode]; inodes[inode] && !seen {
+				holders[inode] = pid
+			}
+		}
+		if len(holders) == len(inodes) {
+			break
+		}
+	}
+	return holders
+}
+
+// commandName returns the display name of pid, or "" if it has exited.
+func commandName(pid int) string {
+	stat, err := os.ReadFile(pidPath(pid, "stat"))
+	if err != nil {
+		return ""
+	}
+	p, err := parseStatSnapshot(pid, stat)
+	if err != nil {
+		return ""
+	}
+	return p.Command
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package proc
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestTupleKey(t *testing.T) {
+	mapped := tupleKey("::ffff:127.0.0.1", 5432, "::ffff:127.0.0.1", 41234)
+	plain := tupleKey("127.0.0.1", 5432, "127.0.0.1", 41234)
+	if mapped != plain {
+		t.Errorf("IPv4-mapped key %q != IPv4 key %q", mapped, plain)
+	}
+	if tupleKey("::1", 80, "::1", 1) == tupleKey("::1", 1, "::1", 80) {
+		t.Error("reversed tuple must have a different key")
+	}
+}
+
+// TestResolveSocketPeers connects to a listener of its own, so both ends of
+// the connection belong to this process.
+func TestResolveSocketPeers(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// The socket table is cached; make sure it's read after the connection.
+	socketCacheMu.Lock()
+	socketCache = nil
+	socketCacheMu.Unlock()
+
+	p, err := ReadProcess(os.Getpid())
+	if err != nil {
+		t.Skipf("read self: %v", err)
+	}
+	localPort := client.LocalAddr().(*net.TCPAddr).Port
+	for _, s := range resolveSocketPeers(p.Sockets) {
+		if s.Port != localPort || s.State != "ESTABLISHED" {
+			continue
+		}
+		if s.PeerPID != os.Getpid() || s.RemotePort != ln.Addr().(*net.TCPAddr).Port {
+			t.Errorf("client socket peer = pid %d port %d, want pid %d port %d", s.PeerPID, s.RemotePort, os.Getpid(), ln.Addr().(*net.TCPAddr).Port)
+		}
+		return
+	}
+	t.Errorf("client socket on port %d not found among %+v", localPort, p.Sockets)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// resolveSocketPeers returns sockets unchanged: matching the two ends of a
+// local connection needs the Linux socket and fd tables.
+func resolveSocketPeers(sockets []model.Socket) []model.Socket {
+	return sockets
+}
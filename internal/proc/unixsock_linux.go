@@ -0,0 +1,176 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"golang.org/x/sys/unix"
+)
+
+// unixSocketStates maps the St column of /proc/net/unix (SS_* values).
+var unixSocketStates = map[string]string{
+	"01": "UNCONNECTED",
+	"02": "CONNECTING",
+	"03": "CONNECTED",
+	"04": "DISCONNECTING",
+}
+
+// unixSocketTypes maps the Type column of /proc/net/unix.
+var unixSocketTypes = map[string]string{
+	"0001": "stream",
+	"0002": "dgram",
+	"0005": "seqpacket",
+}
+
+// soAcceptCon is the __SO_ACCEPTCON flag /proc/net/unix sets on listening
+// sockets.
+const soAcceptCon = 0x10000
+
+// ReadUnixSockets lists the unix sockets of /proc/net/unix with, where the
+// kernel's sock_diag interface answers, the peer of each connected one.
+// Protocol carries the socket type ("UNIX stream"), and State is LISTEN for
+// listening sockets.
+func ReadUnixSockets() ([]model.Socket, error) {
+	f, err := os.Open(ProcPath("net", "unix"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sockets []model.Socket
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		if s, ok := parseUnixSocketLine(scanner.Text()); ok {
+			sockets = append(sockets, s)
+		}
+	}
+
+	// sock_diag answers for witr's own network namespace, which is only the
+	// one /proc/net/unix describes when reading the default procfs.
+	if ProcRoot() == "/proc" {
+		if peers := unixSocketPeers(); peers != nil {
+			for i := range sockets {
+				sockets[i].Peer = peers[sockets[i].Inode]
+			}
+		}
+	}
+	return sockets, scanner.Err()
+}
+
+// parseUnixSocketLine parses one /proc/net/unix row:
+// "Num RefCount Protocol Flags Type St Inode [Path]". The path may contain
+// spaces, and an abstract socket's leading NUL is shown as '@'.
+func parseUnixSocketLine(line string) (model.Socket, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 {
+		return model.Socket{}, false
+	}
+	flags, err := strconv.ParseUint(fields[3], 16, 32)
+	if err != nil {
+		return model.Socket{}, false
+	}
+	typ, ok := unixSocketTypes[fields[4]]
+	if !ok {
+		typ = fields[4]
+	}
+	state := unixSocketStates[fields[5]]
+	if flags&soAcceptCon != 0 {
+		state = "LISTEN"
+	}
+	s := model.Socket{Inode: fields[6], State: state, Protocol: "UNIX " + typ}
+	if len(fields) > 7 {
+		// Rejoin from the original line so runs of spaces in the path survive.
+		idx := strings.Index(line, " "+fields[6]+" ")
+		if idx >= 0 {
+			s.Path = strings.TrimSpace(line[idx+len(fields[6])+2:])
+		} else {
+			s.Path = strings.Join(fields[7:], " ")
+		}
+	}
+	return s, true
+}
+
+// unixDiag* mirror <linux/unix_diag.h>.
+const (
+	unixDiagShowPeer = 0x4
+	unixDiagPeer     = 2
+	unixDiagMsgLen   = 16
+)
+
+// unixSocketPeers asks the kernel for every unix socket's peer over a
+// NETLINK_SOCK_DIAG dump and returns inode → peer inode for the connected
+// ones, or nil when sock_diag is unavailable.
+func unixSocketPeers() map[string]string {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil
+	}
+	defer syscall.Close(fd)
+
+	// nlmsghdr followed by struct unix_diag_req.
+	req := make([]byte, syscall.NLMSG_HDRLEN+24)
+	binary.NativeEndian.PutUint32(req[0:], uint32(len(req)))
+	binary.NativeEndian.PutUint16(req[4:], unix.SOCK_DIAG_BY_FAMILY)
+	binary.NativeEndian.PutUint16(req[6:], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	binary.NativeEndian.PutUint32(req[8:], 1)
+	req[16] = syscall.AF_UNIX
+	binary.NativeEndian.PutUint32(req[20:], 0xffffffff) // every state
+	binary.NativeEndian.PutUint32(req[28:], unixDiagShowPeer)
+	if err := syscall.Sendto(fd, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil
+	}
+
+	peers := make(map[string]string)
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return peers
+			case syscall.NLMSG_ERROR:
+				return nil
+			}
+			if inode, peer, ok := parseUnixDiagMsg(m.Data); ok && peer != 0 {
+				peers[strconv.FormatUint(uint64(inode), 10)] = strconv.FormatUint(uint64(peer), 10)
+			}
+		}
+	}
+}
+
+// parseUnixDiagMsg reads the inode of a struct unix_diag_msg and the peer
+// inode from its UNIX_DIAG_PEER attribute (0 without one).
+func parseUnixDiagMsg(data []byte) (inode, peer uint32, ok bool) {
+	if len(data) < unixDiagMsgLen {
+		return 0, 0, false
+	}
+	inode = binary.NativeEndian.Uint32(data[4:8])
+	attrs := data[unixDiagMsgLen:]
+	for len(attrs) >= 4 {
+		l := int(binary.NativeEndian.Uint16(attrs[0:2]))
+		typ := binary.NativeEndian.Uint16(attrs[2:4])
+		if l < 4 || l > len(attrs) {
+			break
+		}
+		if typ == unixDiagPeer && l >= 8 {
+			peer = binary.NativeEndian.Uint32(attrs[4:8])
+		}
+		attrs = attrs[min((l+3)&^3, len(attrs)):]
+	}
+	return inode, peer, true
+}
@@ -0,0 +1,83 @@
+//go:build linux
+
+package proc
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUnixSocketLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string // Inode|State|Protocol|Path
+	}{
+		{"0000000000000000: 00000002 00000000 00010000 0001 01 21042 /run/docker.sock", "21042|LISTEN|UNIX stream|/run/docker.sock"},
+		{"0000000000000000: 00000003 00000000 00000000 0001 03 21090 /run/docker.sock", "21090|CONNECTED|UNIX stream|/run/docker.sock"},
+		{"0000000000000000: 00000003 00000000 00000000 0001 03 911", "911|CONNECTED|UNIX stream|"},
+		{"0000000000000000: 00000002 00000000 00000000 0002 01 3344 @/org/kernel/udev", "3344|UNCONNECTED|UNIX dgram|@/org/kernel/udev"},
+		{"0000000000000000: 00000002 00000000 00010000 0005 01 77 /tmp/my agent.sock", "77|LISTEN|UNIX seqpacket|/tmp/my agent.sock"},
+	}
+	for _, tt := range tests {
+		s, ok := parseUnixSocketLine(tt.line)
+		if !ok {
+			t.Errorf("parseUnixSocketLine(%q) failed", tt.line)
+			continue
+		}
+		if got := s.Inode + "|" + s.State + "|" + s.Protocol + "|" + s.Path; got != tt.want {
+			t.Errorf("parseUnixSocketLine(%q) = %s, want %s", tt.line, got, tt.want)
+		}
+	}
+	if _, ok := parseUnixSocketLine("garbage"); ok {
+		t.Error("parseUnixSocketLine accepted a malformed line")
+	}
+}
+
+// TestReadUnixSocketsPeers connects to a listener of its own and expects
+// the accepted end, which shares the listener's path, to report the client
+// as its peer.
+func TestReadUnixSocketsPeers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "witr.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	client, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	sockets, err := ReadUnixSockets()
+	if err != nil {
+		t.Skipf("read /proc/net/unix: %v", err)
+	}
+	if unixSocketPeers() == nil {
+		t.Skip("sock_diag unavailable")
+	}
+	var listening, connected int
+	for _, s := range sockets {
+		if s.Path != path {
+			continue
+		}
+		switch s.State {
+		case "LISTEN":
+			listening++
+		case "CONNECTED":
+			connected++
+			if s.Peer == "" {
+				t.Errorf("accepted socket %s has no peer", s.Inode)
+			}
+		}
+	}
+	if listening != 1 || connected != 1 {
+		t.Errorf("found %d listening and %d connected sockets on %s, want 1 and 1", listening, connected, path)
+	}
+}
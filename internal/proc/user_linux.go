@@ -36,7 +36,7 @@ func loadUserCache() map[int]string {
 }
 
 func readUser(pid int) string {
-	path := "/proc/" + strconv.Itoa(pid)
+	path := pidPath(pid)
 
 	info, err := os.Stat(path)
 	if err != nil {
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Listen opens the listener for addr, which is either "unix:///path/to.sock"
+// or a TCP "host:port". A unix socket is created with mode socketMode so
+// access is controlled by file permissions; a stale socket left behind by a
+// previous run is replaced, but any other file at that path is not. Closing
+// the listener removes the socket.
+func Listen(addr string, socketMode os.FileMode) (net.Listener, error) {
+	path, isUnix := strings.CutPrefix(addr, "unix://")
+	if !isUnix {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid listen address %q: want unix:///path or host:port", addr)
+		}
+		return net.Listen("tcp", addr)
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("invalid listen address %q: missing socket path", addr)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("refusing to replace %s: not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The API has no authentication of its own, so the socket is bound in a
+	// directory only we can enter and only moved to path once socketMode is
+	// set: it never sits there with the umask's looser mode.
+	private, err := os.MkdirTemp(filepath.Dir(path), ".witr-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(private)
+	bound := filepath.Join(private, "s")
+	ln, err := net.Listen("unix", bound)
+	if err != nil {
+		return nil, err
+	}
+	ul := ln.(*net.UnixListener)
+	ul.SetUnlinkOnClose(false)
+	if err := os.Chmod(bound, socketMode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	if err := os.Rename(bound, path); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &unixListener{UnixListener: ul, path: path}, nil
+}
+
+// unixListener removes its socket on Close, from where it was moved to
+// rather than where it was bound.
+type unixListener struct {
+	*net.UnixListener
+	path string
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+// IsLoopback reports whether a TCP listen address only accepts local
+// connections. Unix sockets count as local.
+func IsLoopback(addr string) bool {
+	if strings.HasPrefix(addr, "unix://") {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Serve runs the API on ln until ctx is cancelled, then drains in-flight
+// requests.
+func Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           NewHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,153 @@
+// Package server exposes witr's analyses over HTTP as JSON, for dashboards and
+// bots that want to ask "why is this running" without parsing CLI output.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/pranshuparmar/witr/pkg/witr"
+)
+
+// errorResponse is the body of every non-2xx response. PIDs lists the
+// candidates when a lookup is ambiguous (409), so the caller can retry with
+// /v1/pid/{pid}.
+type errorResponse struct {
+	Error string
+	PIDs  []int `json:",omitempty"`
+}
+
+// NewHandler returns the v1 API. Every endpoint is GET-only; single-target
+// endpoints accept ?verbose=1 and ?tree=1 like the CLI flags, and name and
+// container lookups accept ?exact=1.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/pid/{pid}", func(w http.ResponseWriter, r *http.Request) {
+		analyzeTarget(w, r, model.Target{Type: model.TargetPID, Value: r.PathValue("pid")})
+	})
+	mux.HandleFunc("GET /v1/port/{port}", func(w http.ResponseWriter, r *http.Request) {
+		analyzeTarget(w, r, model.Target{Type: model.TargetPort, Value: r.PathValue("port")})
+	})
+	mux.HandleFunc("GET /v1/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		analyzeTarget(w, r, model.Target{Type: model.TargetName, Value: r.PathValue("name")})
+	})
+	mux.HandleFunc("GET /v1/file", func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Query().Get("path")
+		if path == "" {
+			writeError(w, http.StatusBadRequest, errors.New("missing ?path="))
+			return
+		}
+		analyzeTarget(w, r, model.Target{Type: model.TargetFile, Value: path})
+	})
+	mux.HandleFunc("GET /v1/container/{q}", handleContainer)
+	mux.HandleFunc("GET /v1/processes", func(w http.ResponseWriter, r *http.Request) {
+		procs, err := procpkg.ListProcesses()
+		if err != nil {
+			writeError(w, statusForError(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, procs)
+	})
+	mux.HandleFunc("GET /v1/ports", func(w http.ResponseWriter, r *http.Request) {
+		ports, err := procpkg.ListOpenPorts()
+		if err != nil {
+			writeError(w, statusForError(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ports)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint: %s %s", r.Method, r.URL.Path))
+	})
+	return mux
+}
+
+func client(r *http.Request) *witr.Client {
+	return witr.New(witr.Options{
+		Verbose: queryBool(r, "verbose"),
+		Tree:    queryBool(r, "tree"),
+		Exact:   queryBool(r, "exact"),
+	})
+}
+
+// analyzeTarget returns the analysis of the single process t resolves to.
+func analyzeTarget(w http.ResponseWriter, r *http.Request, t model.Target) {
+	res, err := client(r).Explain(r.Context(), t)
+	if err != nil {
+		var multi *witr.MultipleMatchesError
+		if errors.As(err, &multi) {
+			writeJSON(w, http.StatusConflict, errorResponse{
+				Error: fmt.Sprintf("multiple matching processes found (%d)", len(multi.PIDs)),
+				PIDs:  multi.PIDs,
+			})
+			return
+		}
+		writeError(w, statusForError(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func handleContainer(w http.ResponseWriter, r *http.Request) {
+	res, err := client(r).ExplainContainer(r.Context(), r.PathValue("q"))
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// statusForError maps resolver and pipeline errors to HTTP statuses the way
+// classifyError maps them to exit codes.
+func statusForError(err error) int {
+	if errors.Is(err, target.ErrUnsupported) {
+		return http.StatusNotImplemented
+	}
+	if errors.Is(err, target.ErrSocketOwnerUnknown) {
+		return http.StatusForbidden
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "permission denied") ||
+		strings.Contains(msg, "operation not permitted") ||
+		strings.Contains(msg, "insufficient permissions"):
+		return http.StatusForbidden
+	case strings.Contains(msg, "no matching") ||
+		strings.Contains(msg, "no running process") ||
+		strings.Contains(msg, "not found") ||
+		strings.Contains(msg, "no process") ||
+		strings.Contains(msg, "no container") ||
+		strings.Contains(msg, "does not exist"):
+		return http.StatusNotFound
+	case strings.Contains(msg, "multiple"):
+		return http.StatusConflict
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func queryBool(r *http.Request, name string) bool {
+	v, err := strconv.ParseBool(r.URL.Query().Get(name))
+	return err == nil && v
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
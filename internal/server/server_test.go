@@ -0,0 +1,166 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func get(t *testing.T, path string) (*http.Response, []byte) {
+	t.Helper()
+	srv := httptest.NewServer(NewHandler())
+	t.Cleanup(srv.Close)
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("GET %s: body is not JSON: %v", path, err)
+	}
+	return resp, body
+}
+
+// The success path returns the same model.Result the CLI's --json prints.
+func TestPIDEndpointReturnsResult(t *testing.T) {
+	pid := os.Getpid()
+	resp, body := get(t, "/v1/pid/"+strconv.Itoa(pid))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, body %s", resp.StatusCode, body)
+	}
+	var res model.Result
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Process.PID != pid || len(res.Ancestry) == 0 {
+		t.Errorf("result = %+v, want an analysis of pid %d", res.Process, pid)
+	}
+}
+
+func TestEndpointErrors(t *testing.T) {
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/v1/pid/not-a-number", http.StatusBadRequest},
+		{"/v1/pid/0", http.StatusBadRequest},
+		{"/v1/file", http.StatusBadRequest},
+		{"/v1/container/no-such-container-xyz", http.StatusNotFound},
+		{"/v1/unknown", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, body := get(t, tt.path)
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s = %d, want %d (body %s)", tt.path, resp.StatusCode, tt.want, body)
+		}
+		var e errorResponse
+		if json.Unmarshal(body, &e) != nil || e.Error == "" {
+			t.Errorf("GET %s: error body %s has no Error field", tt.path, body)
+		}
+	}
+}
+
+func TestListEndpoints(t *testing.T) {
+	resp, body := get(t, "/v1/processes")
+	var procs []model.Process
+	if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &procs) != nil || len(procs) == 0 {
+		t.Errorf("/v1/processes = %d, %d processes", resp.StatusCode, len(procs))
+	}
+
+	resp, body = get(t, "/v1/ports")
+	var ports []model.OpenPort
+	if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &ports) != nil {
+		t.Errorf("/v1/ports = %d, body %s", resp.StatusCode, body)
+	}
+}
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("resolve: %w", target.ErrUnsupported), http.StatusNotImplemented},
+		{target.ErrSocketOwnerUnknown, http.StatusForbidden},
+		{errors.New("open /proc/1/environ: permission denied"), http.StatusForbidden},
+		{errors.New("no process listening on port 81"), http.StatusNotFound},
+		{errors.New("process 99 does not exist"), http.StatusNotFound},
+		{errors.New("invalid port"), http.StatusBadRequest},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := statusForError(tt.err); got != tt.want {
+			t.Errorf("statusForError(%q) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes don't gate unix sockets on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "witr.sock")
+
+	ln, err := Listen("unix://"+path, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, want 0600", info.Mode().Perm())
+	}
+	// Bound elsewhere and moved into place: it answers at path, with
+	// nothing left beside it.
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial %s: %v", path, err)
+	}
+	conn.Close()
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("socket directory holds %d entries, want only the socket", len(entries))
+	}
+	ln.Close()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind after Close: %v", err)
+	}
+
+	// A regular file at the path is never clobbered.
+	os.WriteFile(path, []byte("data"), 0o600)
+	if _, err := Listen("unix://"+path, 0o600); err == nil {
+		t.Error("Listen replaced a regular file")
+	}
+
+	if _, err := Listen("8080", 0o600); err == nil {
+		t.Error("a bare port should be rejected")
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"unix:///run/witr.sock": true,
+		"127.0.0.1:7878":        true,
+		"[::1]:7878":            true,
+		"localhost:7878":        true,
+		"0.0.0.0:7878":          false,
+		":7878":                 false,
+		"10.0.0.5:7878":         false,
+	} {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
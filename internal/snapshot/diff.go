@@ -0,0 +1,284 @@
+package snapshot
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Change kinds, in the order Diff reports them.
+const (
+	KindService   = "service"
+	KindProcess   = "process"
+	KindListener  = "listener"
+	KindContainer = "container"
+	KindLock      = "lock"
+)
+
+var kindOrder = map[string]int{KindService: 0, KindProcess: 1, KindListener: 2, KindContainer: 3, KindLock: 4}
+
+// Change is one thing that appeared in or disappeared from the later of two
+// archives, together with the source that explains it (taken from whichever
+// archive the thing exists in).
+type Change struct {
+	Kind    string
+	Added   bool
+	Subject string
+	PID     int    `json:",omitempty"`
+	Process string `json:",omitempty"`
+	Source  model.Source
+	Message string
+}
+
+// Diff compares two archives and reports services, processes, listeners,
+// containers and locks that appeared in b or are gone from a.
+//
+// Things are matched by identity rather than PID: a process is its command
+// line plus the source that started it, a listener is its protocol, address,
+// port and owning command. A service restarted under a new PID, or a whole
+// host rebooted, therefore only shows what actually changed in "what's
+// running and why" instead of every PID.
+func Diff(a, b *Archive) []Change {
+	var changes []Change
+	changes = append(changes, diffSet(KindService, a.services(), b.services())...)
+	changes = append(changes, diffSet(KindProcess, a.processes(), b.processes())...)
+	changes = append(changes, diffSet(KindListener, a.listeners(), b.listeners())...)
+	changes = append(changes, diffSet(KindContainer, a.containers(), b.containers())...)
+	changes = append(changes, diffSet(KindLock, a.locks(), b.locks())...)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return kindOrder[changes[i].Kind] < kindOrder[changes[j].Kind]
+		}
+		if changes[i].Added != changes[j].Added {
+			return !changes[i].Added // removals first
+		}
+		return changes[i].Subject < changes[j].Subject
+	})
+	return changes
+}
+
+// item is one comparable thing in an archive, keyed by its identity.
+type item struct {
+	subject string
+	pid     int
+	process string
+	source  model.Source
+}
+
+func diffSet(kind string, before, after map[string]item) []Change {
+	var changes []Change
+	for key, it := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, newChange(kind, false, it))
+		}
+	}
+	for key, it := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, newChange(kind, true, it))
+		}
+	}
+	return changes
+}
+
+func newChange(kind string, added bool, it item) Change {
+	c := Change{Kind: kind, Added: added, Subject: it.subject, PID: it.pid, Process: it.process, Source: it.source}
+
+	// A service is its own explanation; everything else is explained by the
+	// source of the process behind it.
+	if kind == KindService {
+		what := DescribeSource(it.source)
+		if added {
+			c.Message = fmt.Sprintf("new %s (%s, pid %d)", what, it.process, it.pid)
+		} else {
+			c.Message = fmt.Sprintf("%s is gone (was %s, pid %d)", what, it.process, it.pid)
+		}
+		return c
+	}
+
+	what := kind + " " + it.subject
+	switch {
+	case it.process != "" && kind != KindProcess:
+		what += fmt.Sprintf(" (%s, pid %d)", it.process, it.pid)
+	case it.pid > 0:
+		what += fmt.Sprintf(" (pid %d)", it.pid)
+	}
+	if added {
+		c.Message = fmt.Sprintf("new %s started by %s", what, DescribeSource(it.source))
+	} else {
+		c.Message = fmt.Sprintf("%s is gone (was started by %s)", what, DescribeSource(it.source))
+	}
+	return c
+}
+
+// DescribeSource renders a source as the noun phrase used in diff output,
+// e.g. "systemd unit nginx.service".
+func DescribeSource(s model.Source) string {
+	switch s.Type {
+	case model.SourceSystemd:
+		return "systemd unit " + s.Name
+	case model.SourceLaunchd:
+		return "launchd job " + s.Name
+	case model.SourceBsdRc:
+		return "rc.d service " + s.Name
+	case model.SourceWindowsService:
+		return "Windows service " + s.Name
+	case model.SourceSupervisor:
+		return "supervisor " + s.Name
+	case model.SourceContainer:
+		if s.Name == "kubernetes" && s.Description != "" {
+			return "Kubernetes " + s.Description
+		}
+		return "container runtime " + s.Name
+	case model.SourceCron:
+		return "cron"
+	case model.SourceAutomation:
+		return s.Name + " job"
+	case model.SourceSSH:
+		return "an SSH session"
+	case model.SourceShell:
+		if s.Name != "" {
+			return "an interactive shell (" + s.Name + ")"
+		}
+		return "an interactive shell"
+	case model.SourceInit:
+		return "init"
+	case "", model.SourceUnknown:
+		return "an unknown source"
+	}
+	if s.Name != "" {
+		return string(s.Type) + " " + s.Name
+	}
+	return string(s.Type)
+}
+
+func (a *Archive) source(pid int) model.Source {
+	if s, ok := a.Sources[pid]; ok {
+		return s
+	}
+	return model.Source{Type: model.SourceUnknown}
+}
+
+func (a *Archive) command(pid int) string {
+	if p, ok := a.Details[pid]; ok {
+		return p.Command
+	}
+	for _, p := range a.Processes {
+		if p.PID == pid {
+			return p.Command
+		}
+	}
+	return ""
+}
+
+// services collects every named service-manager unit that had a process.
+func (a *Archive) services() map[string]item {
+	m := make(map[string]item)
+	for _, pid := range sortedKeys(a.Sources) {
+		s := a.Sources[pid]
+		switch s.Type {
+		case model.SourceSystemd, model.SourceLaunchd, model.SourceBsdRc, model.SourceWindowsService, model.SourceSupervisor:
+		default:
+			continue
+		}
+		if s.Name == "" {
+			continue
+		}
+		key := string(s.Type) + "|" + s.Name
+		if _, seen := m[key]; !seen {
+			m[key] = item{subject: s.Name, pid: pid, process: a.command(pid), source: s}
+		}
+	}
+	return m
+}
+
+func (a *Archive) processes() map[string]item {
+	m := make(map[string]item)
+	for _, p := range a.Processes {
+		if p.PID == a.CapturePID {
+			continue
+		}
+		cmdline := p.Cmdline
+		if cmdline == "" {
+			cmdline = p.Command
+		}
+		s := a.source(p.PID)
+		key := cmdline + "\x00" + string(s.Type) + "|" + s.Name
+		if prev, seen := m[key]; !seen || p.PID < prev.pid {
+			m[key] = item{subject: cmdline, pid: p.PID, process: p.Command, source: s}
+		}
+	}
+	return m
+}
+
+func (a *Archive) listeners() map[string]item {
+	m := make(map[string]item)
+	for _, op := range a.OpenPorts {
+		if op.State != "LISTEN" && !strings.HasPrefix(op.Protocol, "UDP") {
+			continue
+		}
+		subject := op.Protocol + " " + net.JoinHostPort(op.Address, strconv.Itoa(op.Port))
+		command := a.command(op.PID)
+		key := subject + "\x00" + command
+		if prev, seen := m[key]; !seen || op.PID < prev.pid {
+			m[key] = item{subject: subject, pid: op.PID, process: command, source: a.source(op.PID)}
+		}
+	}
+	return m
+}
+
+func (a *Archive) containers() map[string]item {
+	m := make(map[string]item)
+	for _, c := range a.Containers {
+		name := c.Name
+		if name == "" {
+			name = c.ID
+		}
+		subject := c.Runtime + " " + name
+		if c.Image != "" {
+			subject += " (" + c.Image + ")"
+		}
+		it := item{subject: subject, source: model.Source{Type: model.SourceContainer, Name: c.Runtime}}
+		if pid := a.ContainerPIDs[c.Runtime+"|"+c.ID]; pid > 0 {
+			it.pid = pid
+			it.process = a.command(pid)
+			if s, ok := a.Sources[pid]; ok {
+				it.source = s
+			}
+		}
+		m[c.Runtime+"|"+name+"|"+c.Image] = it
+	}
+	return m
+}
+
+func (a *Archive) locks() map[string]item {
+	m := make(map[string]item)
+	for _, l := range a.Locks {
+		subject := l.Path
+		if l.Type != "" || l.Mode != "" {
+			subject += " [" + strings.TrimSpace(l.Type+" "+l.Mode) + "]"
+		}
+		command := l.Process
+		if command == "" {
+			command = a.command(l.PID)
+		}
+		key := subject + "\x00" + command
+		if prev, seen := m[key]; !seen || l.PID < prev.pid {
+			m[key] = item{subject: subject, pid: l.PID, process: command, source: a.source(l.PID)}
+		}
+	}
+	return m
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func diffMessages(changes []Change) string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		mark := "-"
+		if c.Added {
+			mark = "+"
+		}
+		lines[i] = mark + " " + c.Message
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffIdenticalArchives(t *testing.T) {
+	if got := Diff(testArchive(), testArchive()); len(got) != 0 {
+		t.Errorf("identical archives should not differ:\n%s", diffMessages(got))
+	}
+}
+
+// After a deploy: node-exporter was installed, nginx was restarted under a
+// new PID, and the lock holder went away. The restart must not show up as a
+// change — only what's running and why.
+func TestDiffAfterDeploy(t *testing.T) {
+	before := testArchive()
+	before.CapturePID = 777
+	before.Processes = append(before.Processes, model.Process{PID: 777, PPID: 1, Command: "witr", Cmdline: "witr snapshot save a"})
+
+	after := testArchive()
+	nginx := after.Details[900200]
+	nginx.PID = 900300
+	after.Details = map[int]model.Process{1: after.Details[1], 900300: nginx}
+	exporter := model.Process{PID: 900400, PPID: 1, Command: "node_exporter", Cmdline: "/usr/bin/node_exporter"}
+	after.Details[900400] = exporter
+	after.Processes = []model.Process{after.Details[1], nginx, exporter}
+	after.Sources = map[int]model.Source{
+		900300: {Type: model.SourceSystemd, Name: "nginx.service"},
+		900400: {Type: model.SourceSystemd, Name: "node-exporter.service"},
+	}
+	after.OpenPorts = []model.OpenPort{
+		{PID: 900300, Port: 8080, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+		{PID: 900400, Port: 9100, Address: "::", Protocol: "TCP", State: "LISTEN"},
+		{PID: 900400, Port: 40000, Address: "127.0.0.1", Protocol: "TCP", State: "ESTABLISHED"},
+	}
+	after.Locks = nil
+	after.Containers = []*model.ContainerMatch{{Runtime: "docker", ID: "abc", Name: "redis", Image: "redis:7"}}
+
+	got := diffMessages(Diff(before, after))
+	want := strings.Join([]string{
+		"+ new systemd unit node-exporter.service (node_exporter, pid 900400)",
+		"+ new process /usr/bin/node_exporter (pid 900400) started by systemd unit node-exporter.service",
+		"+ new listener TCP [::]:9100 (node_exporter, pid 900400) started by systemd unit node-exporter.service",
+		"+ new container docker redis (redis:7) started by container runtime docker",
+		"- lock /run/nginx.lock [FLOCK WRITE] (nginx, pid 900200) is gone (was started by systemd unit nginx.service)",
+	}, "\n")
+	if got != want {
+		t.Errorf("diff =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDescribeSource(t *testing.T) {
+	tests := []struct {
+		src  model.Source
+		want string
+	}{
+		{model.Source{Type: model.SourceSystemd, Name: "nginx.service"}, "systemd unit nginx.service"},
+		{model.Source{Type: model.SourceLaunchd, Name: "com.apple.sshd"}, "launchd job com.apple.sshd"},
+		{model.Source{Type: model.SourceContainer, Name: "docker"}, "container runtime docker"},
+		{model.Source{Type: model.SourceContainer, Name: "kubernetes", Description: "Deployment shop/web"}, "Kubernetes Deployment shop/web"},
+		{model.Source{Type: model.SourceCron}, "cron"},
+		{model.Source{Type: model.SourceAutomation, Name: "github-actions"}, "github-actions job"},
+		{model.Source{Type: model.SourceShell, Name: "zsh"}, "an interactive shell (zsh)"},
+		{model.Source{}, "an unknown source"},
+		{model.Source{Type: "nomad", Name: "web"}, "nomad web"},
+	}
+	for _, tt := range tests {
+		if got := DescribeSource(tt.src); got != tt.want {
+			t.Errorf("DescribeSource(%+v) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
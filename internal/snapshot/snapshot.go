@@ -0,0 +1,222 @@
+// Package snapshot captures everything witr reads from a host into a single
+// archive file and replays it later, so "why is this running" can be answered
+// after the fact on a different machine.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// FormatVersion is bumped whenever Archive changes incompatibly.
+const FormatVersion = 1
+
+// Archive is a point-in-time capture of a host. Activate replays it through
+// the proc package.
+type Archive struct {
+	Version    int
+	Hostname   string
+	OS         string
+	CapturedAt time.Time
+	// CapturePID is witr's own PID during the capture, so Diff can leave it out.
+	CapturePID int `json:",omitempty"`
+
+	// Processes is the process list (PID, PPID, command, user, CPU, memory).
+	Processes []model.Process
+	// Details holds the full per-process read, keyed by PID.
+	Details map[int]model.Process
+	// Sources is the explanation detected for each PID at capture time,
+	// including the systemd/launchd/service-manager properties behind it.
+	Sources map[int]model.Source
+	// Cgroups holds raw cgroup membership per PID (Linux only).
+	Cgroups map[int]string `json:",omitempty"`
+
+	OpenPorts  []model.OpenPort
+	Locks      []*model.LockedFile
+	OpenFiles  []*model.LockedFile
+	Containers []*model.ContainerMatch
+	// ContainerPIDs maps "runtime|id" to the container's main host PID.
+	ContainerPIDs map[string]int `json:",omitempty"`
+}
+
+// Capture reads the live system into a new Archive. Per-process read errors
+// (processes exiting mid-capture, permission denied) are skipped; the capture
+// only fails when the process table itself can't be listed.
+func Capture() (*Archive, error) {
+	if procpkg.ActiveReplay() != nil {
+		return nil, procpkg.ErrReplayUnavailable
+	}
+
+	procs, err := procpkg.ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	a := &Archive{
+		Version:       FormatVersion,
+		Hostname:      hostname,
+		OS:            runtime.GOOS,
+		CapturedAt:    time.Now(),
+		CapturePID:    os.Getpid(),
+		Processes:     procs,
+		Details:       make(map[int]model.Process, len(procs)),
+		Sources:       make(map[int]model.Source, len(procs)),
+		Cgroups:       make(map[int]string),
+		ContainerPIDs: make(map[string]int),
+	}
+
+	healthchecks := make(map[string]string)
+	for _, p := range procs {
+		d, err := procpkg.ReadProcess(p.PID)
+		if err != nil {
+			continue
+		}
+		if d.ContainerID != "" {
+			hc, ok := healthchecks[d.ContainerID]
+			if !ok {
+				hc = procpkg.ContainerHealthcheckStatus(d.ContainerID, d.ContainerRuntime)
+				healthchecks[d.ContainerID] = hc
+			}
+			d.ContainerHealthcheck = hc
+		}
+		a.Details[p.PID] = d
+		if cg := procpkg.ReadCgroup(p.PID); cg != "" {
+			a.Cgroups[p.PID] = cg
+		}
+	}
+
+	for pid := range a.Details {
+		if ancestry := a.ancestry(pid); len(ancestry) > 0 {
+			a.Sources[pid] = source.Detect(ancestry)
+		}
+	}
+
+	a.OpenPorts, _ = procpkg.ListOpenPorts()
+	a.Locks = procpkg.ListLockedFiles()
+	a.OpenFiles = procpkg.ListAllOpenFiles()
+
+	a.Containers = procpkg.ListAllContainers()
+	for _, c := range a.Containers {
+		procpkg.EnrichContainer(c)
+		if pid := procpkg.ResolveContainerHostPID(c.Runtime, c.ID); pid > 0 {
+			a.ContainerPIDs[c.Runtime+"|"+c.ID] = pid
+		}
+	}
+
+	return a, nil
+}
+
+// ancestry walks captured parents from pid up to PID 1, returning the chain
+// root-first like proc.ResolveAncestry.
+func (a *Archive) ancestry(pid int) []model.Process {
+	var chain []model.Process
+	seen := make(map[int]bool)
+	for current := pid; current > 0 && !seen[current]; {
+		seen[current] = true
+		p, ok := a.Details[current]
+		if !ok {
+			break
+		}
+		chain = append(chain, p)
+		if p.PPID == 0 || p.PID == 1 {
+			break
+		}
+		current = p.PPID
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// Save writes the archive as gzip-compressed JSON. The file is created
+// owner-only: it contains every process's environment and command line.
+func (a *Archive) Save(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		f.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+// Load reads an archive written by Save.
+func Load(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	var a Archive
+	if err := json.NewDecoder(zr).Decode(&a); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	if a.Version != FormatVersion {
+		return nil, fmt.Errorf("invalid snapshot %s: format version %d, this witr reads version %d", path, a.Version, FormatVersion)
+	}
+	return &a, nil
+}
+
+// Activate makes every proc/target/pipeline query answer from the archive.
+func (a *Archive) Activate() { procpkg.SetReplay(replay{a}) }
+
+// replay adapts an Archive to proc.Replay.
+type replay struct{ a *Archive }
+
+func (r replay) Process(pid int) (model.Process, bool) {
+	p, ok := r.a.Details[pid]
+	return p, ok
+}
+
+func (r replay) Processes() []model.Process { return slices.Clone(r.a.Processes) }
+
+func (r replay) OpenPorts() []model.OpenPort { return slices.Clone(r.a.OpenPorts) }
+
+func (r replay) LockedFiles() []*model.LockedFile { return slices.Clone(r.a.Locks) }
+
+func (r replay) OpenFiles() []*model.LockedFile { return slices.Clone(r.a.OpenFiles) }
+
+func (r replay) Containers() []*model.ContainerMatch { return slices.Clone(r.a.Containers) }
+
+func (r replay) ContainerHostPID(runtime, id string) int {
+	return r.a.ContainerPIDs[runtime+"|"+id]
+}
+
+func (r replay) ContainerHealthcheck(id string) string {
+	for _, p := range r.a.Details {
+		if p.ContainerID == id && p.ContainerHealthcheck != "" {
+			return p.ContainerHealthcheck
+		}
+	}
+	return ""
+}
+
+func (r replay) Cgroup(pid int) string { return r.a.Cgroups[pid] }
+
+func (r replay) Source(pid int) (model.Source, bool) {
+	s, ok := r.a.Sources[pid]
+	return s, ok
+}
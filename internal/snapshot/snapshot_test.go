@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// testArchive is a tiny systemd host: nginx (pid 900200) under PID 1, listening
+// on 8080 and holding a lock. PIDs are far above anything live so a leak from
+// the replay into live reads would fail the lookups instead of passing by
+// accident.
+func testArchive() *Archive {
+	initProc := model.Process{PID: 1, Command: "systemd", User: "root"}
+	nginx := model.Process{PID: 900200, PPID: 1, Command: "nginx", Cmdline: "nginx -g daemon off;", User: "www-data",
+		Sockets: []model.Socket{{Protocol: "TCP", Address: "0.0.0.0", Port: 8080, State: "LISTEN"}}}
+	return &Archive{
+		Version:    FormatVersion,
+		Hostname:   "web-1",
+		OS:         "linux",
+		CapturedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Processes:  []model.Process{initProc, nginx},
+		Details:    map[int]model.Process{1: initProc, 900200: nginx},
+		Sources: map[int]model.Source{
+			900200: {Type: model.SourceSystemd, Name: "nginx.service", Details: map[string]string{"NRestarts": "3"}},
+		},
+		Cgroups:   map[int]string{900200: "0::/system.slice/docker-abc123def456.scope"},
+		OpenPorts: []model.OpenPort{{PID: 900200, Port: 8080, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"}},
+		Locks:     []*model.LockedFile{{PID: 900200, Process: "nginx", Path: "/run/nginx.lock", Type: "FLOCK", Mode: "WRITE"}},
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host.witr")
+	want := testArchive()
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// The archive carries every process's environment, so it must not be
+	// readable by other users.
+	if info, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		t.Errorf("snapshot mode = %v, want owner-only", info.Mode().Perm())
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestLoadRejectsBadInput(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "plain.json")
+	os.WriteFile(plain, []byte(`{"Version":1}`), 0o600)
+	if _, err := Load(plain); err == nil {
+		t.Error("uncompressed file should be rejected")
+	}
+
+	future := filepath.Join(dir, "future.witr")
+	a := testArchive()
+	a.Version = FormatVersion + 1
+	if err := a.Save(future); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(future); err == nil {
+		t.Error("newer format version should be rejected")
+	}
+}
+
+// The point of a snapshot: resolve and analyze a target without touching the
+// live host, with the source explained from what was captured.
+func TestReplayAnswersResolveAndAnalyze(t *testing.T) {
+	testArchive().Activate()
+	t.Cleanup(func() { procpkg.SetReplay(nil) })
+
+	for _, tg := range []model.Target{
+		{Type: model.TargetPort, Value: "8080"},
+		{Type: model.TargetName, Value: "nginx"},
+		{Type: model.TargetFile, Value: "/run/nginx.lock"},
+	} {
+		pids, err := target.Resolve(tg, false)
+		if err != nil || !reflect.DeepEqual(pids, []int{900200}) {
+			t.Errorf("Resolve(%s %s) = %v, %v; want [900200]", tg.Type, tg.Value, pids, err)
+		}
+	}
+	if _, err := target.Resolve(model.Target{Type: model.TargetPort, Value: "9999"}, false); err == nil {
+		t.Error("port absent from the snapshot should not resolve")
+	}
+
+	res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{PID: 900200, Target: model.Target{Type: model.TargetPort, Value: "8080"}})
+	if err != nil {
+		t.Fatalf("AnalyzePID: %v", err)
+	}
+	if len(res.Ancestry) != 2 || res.Ancestry[0].PID != 1 {
+		t.Errorf("ancestry = %+v, want systemd → nginx", res.Ancestry)
+	}
+	if res.Source.Name != "nginx.service" || res.RestartCount != 3 {
+		t.Errorf("source = %+v restarts = %d, want the captured nginx.service with 3 restarts", res.Source, res.RestartCount)
+	}
+
+	if si := procpkg.GetSocketStateForPort(8080); si == nil || si.State != "LISTEN" {
+		t.Errorf("socket state = %+v, want LISTEN", si)
+	}
+	if !procpkg.PIDBelongsToContainer(900200, "abc123def456") || procpkg.PIDBelongsToContainer(900200, "fff000") {
+		t.Error("container membership should come from the captured cgroup")
+	}
+
+	// What only the live system can answer says so.
+	if _, err := Capture(); !errors.Is(err, procpkg.ErrReplayUnavailable) {
+		t.Errorf("Capture while replaying: %v, want ErrReplayUnavailable", err)
+	}
+	if runtime.GOOS == "linux" {
+		if _, err := target.Resolve(model.Target{Type: model.TargetSocket, Value: "/run/nginx.sock"}, false); !errors.Is(err, procpkg.ErrReplayUnavailable) {
+			t.Errorf("Resolve(socket) while replaying: %v, want ErrReplayUnavailable", err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package source
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// automationAgent is a CI runner, configuration management or IaC agent:
+// how to recognize it among a process's ancestors, or from the variable it
+// sets in the environment of every job, and how to name the job.
+type automationAgent struct {
+	name  string
+	label string
+	// binaries are the agent's executable or command-line token basenames;
+	// a trailing '*' matches a prefix.
+	binaries []string
+	// cliBinaries are the tool's own command-line programs, which people
+	// also run by hand: they only count inside a CI job.
+	cliBinaries []string
+	// env is the KEY or KEY=value the agent sets in its jobs' environment.
+	// Processes a job leaves behind keep it after the runner is gone.
+	env string
+	// job names the job from the environment or the agent's command line.
+	job func(ancestry []model.Process, agent *model.Process) string
+	// url links to the job, when the agent exports one.
+	url func(ancestry []model.Process) string
+}
+
+var automationAgents = []automationAgent{
+	{
+		name: "github-actions", label: "GitHub Actions",
+		binaries: []string{"Runner.Worker", "Runner.Listener"},
+		env:      "GITHUB_ACTIONS=true",
+		job:      envJob("", "GITHUB_REPOSITORY", "workflow", "GITHUB_WORKFLOW", "run", "GITHUB_RUN_ID", "attempt", "GITHUB_RUN_ATTEMPT", "job", "GITHUB_JOB"),
+		url: func(ancestry []model.Process) string {
+			server, repo, run := supervisedEnv(ancestry, "GITHUB_SERVER_URL"), supervisedEnv(ancestry, "GITHUB_REPOSITORY"), supervisedEnv(ancestry, "GITHUB_RUN_ID")
+			if server == "" || repo == "" || run == "" {
+				return ""
+			}
+			return server + "/" + repo + "/actions/runs/" + run
+		},
+	},
+	{
+		name: "gitlab-runner", label: "GitLab CI",
+		binaries: []string{"gitlab-runner", "gitlab-ci-multi-runner"},
+		env:      "GITLAB_CI=true",
+		job:      envJob("", "CI_PROJECT_PATH", "pipeline", "CI_PIPELINE_ID", "job", "CI_JOB_NAME", "id", "CI_JOB_ID"),
+		url:      envURL("CI_JOB_URL"),
+	},
+	{
+		name: "jenkins", label: "Jenkins",
+		binaries: []string{"agent.jar", "remoting.jar", "slave.jar", "jenkins.war"},
+		env:      "JENKINS_URL",
+		job:      envJob("", "JOB_NAME", "build", "BUILD_NUMBER", "node", "NODE_NAME"),
+		url:      envURL("BUILD_URL"),
+	},
+	{
+		name: "buildkite", label: "Buildkite",
+		binaries: []string{"buildkite-agent"},
+		env:      "BUILDKITE=true",
+		job:      envJob("", "BUILDKITE_PIPELINE_SLUG", "build", "BUILDKITE_BUILD_NUMBER", "step", "BUILDKITE_LABEL", "job", "BUILDKITE_JOB_ID"),
+		url:      envURL("BUILDKITE_BUILD_URL"),
+	},
+	{
+		name: "drone", label: "Drone",
+		binaries: []string{"drone-runner-exec", "drone-runner-docker", "drone-runner-ssh", "drone-agent"},
+		env:      "DRONE=true",
+		job:      envJob("", "DRONE_REPO", "build", "DRONE_BUILD_NUMBER", "step", "DRONE_STEP_NAME"),
+		url:      envURL("DRONE_BUILD_LINK"),
+	},
+	{
+		name: "ansible", label: "Ansible",
+		binaries:    []string{"AnsiballZ_*", "ansible-pull"},
+		cliBinaries: []string{"ansible-playbook", "ansible"},
+		job:         ansibleJob,
+	},
+	{name: "salt", label: "Salt", binaries: []string{"salt-minion", "salt-call"}},
+	{name: "puppet", label: "Puppet", binaries: []string{"puppet"}},
+	{name: "chef", label: "Chef", binaries: []string{"chef-client", "chef-solo"}},
+	{name: "terraform", label: "Terraform", binaries: []string{"tfc-agent"}, cliBinaries: []string{"terraform"}},
+	{name: "opentofu", label: "OpenTofu", cliBinaries: []string{"tofu"}},
+	{name: "pulumi", label: "Pulumi", cliBinaries: []string{"pulumi"}},
+	{name: "atlantis", label: "Atlantis", binaries: []string{"atlantis"}},
+}
+
+// detectAutomation claims processes spawned by a CI runner or an automation
+// agent: below one of the agents (the closest wins, so a playbook a Jenkins
+// job runs is Ansible's), else carrying the variable a CI system sets in its
+// jobs. It runs ahead of the ssh and shell detectors, since jobs run in a
+// shell and Ansible reaches hosts over SSH.
+func detectAutomation(ancestry []model.Process) *model.Source {
+	ci := inCIJob(ancestry)
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		for _, a := range automationAgents {
+			if a.matches(ancestry[i], ci) {
+				agent := ancestry[i]
+				return a.source(ancestry, &agent, fmt.Sprintf("%s (pid %d)", agent.Command, agent.PID))
+			}
+		}
+	}
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		for _, a := range automationAgents {
+			if a.env != "" && hasEnv(ancestry[i].Env, a.env) {
+				return a.source(ancestry, nil, "env "+a.env)
+			}
+		}
+	}
+	return nil
+}
+
+// matches reports whether p is the agent, by its executable or, when p is
+// an interpreter, the script it runs ("java -jar agent.jar", "python3
+// AnsiballZ_command.py"). Other commands' arguments are left alone, so
+// "ls /etc/puppet" isn't Puppet. The tool's command-line programs match
+// only in a CI job (ci), so "terraform apply" in a terminal isn't a job.
+func (a automationAgent) matches(p model.Process, ci bool) bool {
+	binaries := a.binaries
+	if ci {
+		binaries = append(binaries[:len(binaries):len(binaries)], a.cliBinaries...)
+	}
+	names := []string{filepath.Base(p.Command)}
+	fields := strings.Fields(p.Cmdline)
+	if len(fields) > 0 {
+		names = append(names, filepath.Base(fields[0]))
+		if isInterpreter(names[0]) || isInterpreter(names[1]) {
+			args := 0
+			for _, token := range fields[1:] {
+				if strings.HasPrefix(token, "-") {
+					continue
+				}
+				names = append(names, filepath.Base(token))
+				if args++; args == 2 {
+					break
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		for _, b := range binaries {
+			if prefix, ok := strings.CutSuffix(b, "*"); ok {
+				if strings.HasPrefix(name, prefix) {
+					return true
+				}
+			} else if strings.EqualFold(name, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inCIJob reports whether the process runs in a CI job, by the CI variable
+// most CI systems set or the one a known runner sets.
+func inCIJob(ancestry []model.Process) bool {
+	for _, p := range ancestry {
+		if v := envValue(p.Env, "CI"); v != "" && v != "false" {
+			return true
+		}
+		for _, a := range automationAgents {
+			if a.env != "" && hasEnv(p.Env, a.env) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInterpreter reports whether name runs a script or archive named in its
+// arguments: Python, Ruby, Node.js, Java or a POSIX shell.
+func isInterpreter(name string) bool {
+	switch name {
+	case "ruby", "node", "nodejs", "java", "sh", "bash", "dash":
+		return true
+	}
+	return strings.HasPrefix(name, "python") || strings.HasPrefix(name, "ruby")
+}
+
+func (a automationAgent) source(ancestry []model.Process, agent *model.Process, matched string) *model.Source {
+	src := &model.Source{
+		Type:        model.SourceAutomation,
+		Name:        a.name,
+		Description: a.label + " job",
+		Details:     map[string]string{"matched": matched},
+	}
+	if agent == nil {
+		// Found by its environment alone: the job's runner has exited and the
+		// process was left running.
+		src.Description += " (left behind: its runner is no longer an ancestor)"
+	}
+	if a.job != nil {
+		if job := a.job(ancestry, agent); job != "" {
+			src.Details["job"] = job
+		}
+	}
+	if a.url != nil {
+		if url := a.url(ancestry); url != "" {
+			src.Details["url"] = url
+		}
+	}
+	return src
+}
+
+// envJob names a job from environment variables, given as label, KEY
+// pairs; an empty label shows the value alone, e.g. "octo/app, run 42".
+func envJob(pairs ...string) func([]model.Process, *model.Process) string {
+	return func(ancestry []model.Process, _ *model.Process) string {
+		var parts []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			v := supervisedEnv(ancestry, pairs[i+1])
+			if v == "" {
+				continue
+			}
+			if pairs[i] != "" {
+				v = pairs[i] + " " + v
+			}
+			parts = append(parts, v)
+		}
+		return strings.Join(parts, ", ")
+	}
+}
+
+func envURL(key string) func([]model.Process) string {
+	return func(ancestry []model.Process) string {
+		return supervisedEnv(ancestry, key)
+	}
+}
+
+// ansibleJob names the module Ansible runs on a managed host, from the
+// AnsiballZ wrapper in its temporary directory, or the playbook a
+// controller runs.
+func ansibleJob(_ []model.Process, agent *model.Process) string {
+	if agent == nil {
+		return ""
+	}
+	for _, token := range strings.Fields(agent.Cmdline) {
+		base := filepath.Base(token)
+		if module, ok := strings.CutPrefix(base, "AnsiballZ_"); ok {
+			job := "module " + strings.TrimSuffix(module, ".py")
+			if dir := filepath.Base(filepath.Dir(token)); strings.HasPrefix(dir, "ansible-tmp-") {
+				job += ", " + dir
+			}
+			return job
+		}
+		if strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml") {
+			return "playbook " + token
+		}
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectAutomation(t *testing.T) {
+	initProc := model.Process{PID: 1, Command: "systemd"}
+	ghEnv := []string{
+		"GITHUB_ACTIONS=true", "GITHUB_SERVER_URL=https://github.com", "GITHUB_REPOSITORY=octo/app",
+		"GITHUB_WORKFLOW=CI", "GITHUB_RUN_ID=42", "GITHUB_RUN_ATTEMPT=2", "GITHUB_JOB=build",
+	}
+
+	cases := []struct {
+		name     string
+		ancestry []model.Process
+		want     *model.Source
+	}{
+		{
+			name: "GitHub Actions step",
+			ancestry: []model.Process{initProc,
+				{PID: 10, PPID: 1, Command: "Runner.Listener", Cmdline: "/home/runner/bin/Runner.Listener run"},
+				{PID: 11, PPID: 10, Command: "Runner.Worker", Cmdline: "/home/runner/bin/Runner.Worker spawnclient 104 107"},
+				{PID: 12, PPID: 11, Command: "bash", Cmdline: "/usr/bin/bash -e /home/runner/work/_temp/step.sh", Env: ghEnv},
+				{PID: 13, PPID: 12, Command: "node", Cmdline: "node server.js", Env: ghEnv},
+			},
+			want: &model.Source{Name: "github-actions", Description: "GitHub Actions job", Details: map[string]string{
+				"matched": "Runner.Worker (pid 11)",
+				"job":     "octo/app, workflow CI, run 42, attempt 2, job build",
+				"url":     "https://github.com/octo/app/actions/runs/42",
+			}},
+		},
+		{
+			name: "GitHub Actions leftover reparented to init",
+			ancestry: []model.Process{initProc,
+				{PID: 13, PPID: 1, Command: "node", Cmdline: "node server.js", Env: ghEnv},
+			},
+			want: &model.Source{Name: "github-actions", Description: "GitHub Actions job (left behind: its runner is no longer an ancestor)", Details: map[string]string{
+				"matched": "env GITHUB_ACTIONS=true",
+				"job":     "octo/app, workflow CI, run 42, attempt 2, job build",
+				"url":     "https://github.com/octo/app/actions/runs/42",
+			}},
+		},
+		{
+			name: "GitLab job",
+			ancestry: []model.Process{initProc,
+				{PID: 20, PPID: 1, Command: "gitlab-runner", Cmdline: "/usr/bin/gitlab-runner run --working-directory /home/gitlab-runner"},
+				{PID: 21, PPID: 20, Command: "bash", Cmdline: "bash --login", Env: []string{"GITLAB_CI=true", "CI_PROJECT_PATH=grp/svc", "CI_PIPELINE_ID=900", "CI_JOB_NAME=test", "CI_JOB_ID=9001", "CI_JOB_URL=https://gitlab.example.com/grp/svc/-/jobs/9001"}},
+			},
+			want: &model.Source{Name: "gitlab-runner", Description: "GitLab CI job", Details: map[string]string{
+				"matched": "gitlab-runner (pid 20)",
+				"job":     "grp/svc, pipeline 900, job test, id 9001",
+				"url":     "https://gitlab.example.com/grp/svc/-/jobs/9001",
+			}},
+		},
+		{
+			name: "Jenkins agent",
+			ancestry: []model.Process{initProc,
+				{PID: 30, PPID: 1, Command: "java", Cmdline: "java -Xmx512m -jar /opt/jenkins/agent.jar -url https://ci.example.com/"},
+				{PID: 31, PPID: 30, Command: "sh", Cmdline: "sh -xe /tmp/jenkins123.sh", Env: []string{"JENKINS_URL=https://ci.example.com/", "JOB_NAME=deploy", "BUILD_NUMBER=77", "BUILD_URL=https://ci.example.com/job/deploy/77/"}},
+			},
+			want: &model.Source{Name: "jenkins", Description: "Jenkins job", Details: map[string]string{
+				"matched": "java (pid 30)",
+				"job":     "deploy, build 77",
+				"url":     "https://ci.example.com/job/deploy/77/",
+			}},
+		},
+		{
+			name: "Ansible module over SSH",
+			ancestry: []model.Process{initProc,
+				{PID: 40, PPID: 1, Command: "sshd", Cmdline: "sshd: deploy@notty"},
+				{PID: 41, PPID: 40, Command: "sh", Cmdline: "/bin/sh -c /usr/bin/python3 /home/deploy/.ansible/tmp/ansible-tmp-1700000000.1-99-1/AnsiballZ_command.py && sleep 0"},
+				{PID: 42, PPID: 41, Command: "python3", Cmdline: "/usr/bin/python3 /home/deploy/.ansible/tmp/ansible-tmp-1700000000.1-99-1/AnsiballZ_command.py"},
+				{PID: 43, PPID: 42, Command: "apt-get", Cmdline: "apt-get install -y nginx"},
+			},
+			want: &model.Source{Name: "ansible", Description: "Ansible job", Details: map[string]string{
+				"matched": "python3 (pid 42)",
+				"job":     "module command, ansible-tmp-1700000000.1-99-1",
+			}},
+		},
+		{
+			name: "Puppet agent",
+			ancestry: []model.Process{initProc,
+				{PID: 50, PPID: 1, Command: "ruby", Cmdline: "/opt/puppetlabs/puppet/bin/ruby /opt/puppetlabs/puppet/bin/puppet agent --no-daemonize"},
+				{PID: 51, PPID: 50, Command: "yum", Cmdline: "/usr/bin/yum -y install httpd"},
+			},
+			want: &model.Source{Name: "puppet", Description: "Puppet job", Details: map[string]string{"matched": "ruby (pid 50)"}},
+		},
+		{
+			name: "path arguments don't count",
+			ancestry: []model.Process{initProc,
+				{PID: 60, PPID: 1, Command: "bash", Cmdline: "bash"},
+				{PID: 61, PPID: 60, Command: "less", Cmdline: "less /var/log/app.log /etc/puppet"},
+				{PID: 62, PPID: 61, Command: "sh", Cmdline: "sh"},
+			},
+		},
+		{
+			name: "ls of a config directory isn't Puppet",
+			ancestry: []model.Process{initProc,
+				{PID: 63, PPID: 1, Command: "bash", Cmdline: "bash"},
+				{PID: 64, PPID: 63, Command: "ls", Cmdline: "ls /etc/puppet"},
+				{PID: 65, PPID: 64, Command: "sh", Cmdline: "sh"},
+			},
+		},
+		{
+			name: "editing a file named like an agent isn't Ansible",
+			ancestry: []model.Process{initProc,
+				{PID: 66, PPID: 1, Command: "bash", Cmdline: "bash"},
+				{PID: 67, PPID: 66, Command: "vim", Cmdline: "vim ansible"},
+				{PID: 68, PPID: 67, Command: "sh", Cmdline: "/bin/sh -c make"},
+			},
+		},
+		{
+			name: "terraform run by hand isn't a job",
+			ancestry: []model.Process{initProc,
+				{PID: 80, PPID: 1, Command: "bash", Cmdline: "bash"},
+				{PID: 81, PPID: 80, Command: "terraform", Cmdline: "terraform apply"},
+				{PID: 82, PPID: 81, Command: "terraform-provi", Cmdline: ".terraform/providers/registry.terraform.io/hashicorp/aws/5.0.0/linux_amd64/terraform-provider-aws_v5.0.0_x5"},
+			},
+		},
+		{
+			name: "terraform in a CI job",
+			ancestry: []model.Process{initProc,
+				{PID: 90, PPID: 1, Command: "bash", Cmdline: "bash -e /builds/script", Env: []string{"CI=true"}},
+				{PID: 91, PPID: 90, Command: "terraform", Cmdline: "terraform apply -auto-approve", Env: []string{"CI=true"}},
+				{PID: 92, PPID: 91, Command: "terraform-provi", Cmdline: ".terraform/providers/registry.terraform.io/hashicorp/aws/5.0.0/linux_amd64/terraform-provider-aws_v5.0.0_x5", Env: []string{"CI=true"}},
+			},
+			want: &model.Source{Name: "terraform", Description: "Terraform job", Details: map[string]string{"matched": "terraform (pid 91)"}},
+		},
+		{
+			name: "ansible-playbook in a Jenkins job is Ansible's",
+			ancestry: []model.Process{initProc,
+				{PID: 95, PPID: 1, Command: "java", Cmdline: "java -jar /opt/jenkins/agent.jar"},
+				{PID: 96, PPID: 95, Command: "ansible-playboo", Cmdline: "/usr/bin/python3 /usr/bin/ansible-playbook site.yml", Env: []string{"JENKINS_URL=https://ci.example.com/"}},
+				{PID: 97, PPID: 96, Command: "ssh", Cmdline: "ssh web1 /bin/sh -c 'python3 AnsiballZ_setup.py'", Env: []string{"JENKINS_URL=https://ci.example.com/"}},
+			},
+			want: &model.Source{Name: "ansible", Description: "Ansible job", Details: map[string]string{"matched": "ansible-playboo (pid 96)", "job": "playbook site.yml"}},
+		},
+		{
+			name: "the agent itself",
+			ancestry: []model.Process{initProc,
+				{PID: 70, PPID: 1, Command: "buildkite-agent", Cmdline: "buildkite-agent start"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectAutomation(tc.ancestry)
+			if tc.want == nil {
+				if got != nil {
+					t.Fatalf("detectAutomation = %+v, want nil", *got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("detectAutomation = nil")
+			}
+			if got.Type != model.SourceAutomation || got.Name != tc.want.Name || got.Description != tc.want.Description {
+				t.Errorf("source = %s %q %q, want automation %q %q", got.Type, got.Name, got.Description, tc.want.Name, tc.want.Description)
+			}
+			if len(got.Details) != len(tc.want.Details) {
+				t.Errorf("Details = %v, want %v", got.Details, tc.want.Details)
+			}
+			for k, v := range tc.want.Details {
+				if got.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, got.Details[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectPrefersAutomationOverSSH(t *testing.T) {
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 40, PPID: 1, Command: "sshd", Cmdline: "sshd: deploy@notty"},
+		{PID: 42, PPID: 40, Command: "python3", Cmdline: "/usr/bin/python3 /tmp/ansible-tmp-1/AnsiballZ_ping.py"},
+		{PID: 43, PPID: 42, Command: "sleep", Cmdline: "sleep 60"},
+	}
+	if src := Detect(ancestry); src.Type != model.SourceAutomation || src.Name != "ansible" {
+		t.Errorf("Detect = %s %q, want automation ansible", src.Type, src.Name)
+	}
+}
@@ -1,20 +1,18 @@
 package source
 
 import (
-	"os"
-	"strconv"
 	"strings"
 
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func detectContainer(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
-		if err != nil {
+		content := procpkg.ReadCgroup(p.PID)
+		if content == "" {
 			continue
 		}
-		content := string(data)
 
 		switch {
 		case strings.Contains(content, "docker"):
@@ -28,10 +26,7 @@ func detectContainer(ancestry []model.Process) *model.Source {
 				Name: "podman",
 			}
 		case strings.Contains(content, "kubepods"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "kubernetes",
-			}
+			return kubernetesSource(procpkg.KubernetesPod(content))
 		case strings.Contains(content, "colima"):
 			return &model.Source{
 				Type: model.SourceContainer,
@@ -72,8 +67,34 @@ func detectContainer(ancestry []model.Process) *model.Source {
 	return nil
 }
 
-func itoa(n int) string {
-	return strconv.Itoa(n)
+// kubernetesSource answers with the workload a pod's container runs for
+// ("Deployment shop/web"), not just "kubernetes", and the pod it runs in.
+func kubernetesSource(pod *model.ContainerMatch) *model.Source {
+	src := &model.Source{
+		Type: model.SourceContainer,
+		Name: "kubernetes",
+	}
+	if pod == nil {
+		return src
+	}
+	src.Description = pod.Workload()
+	src.Details = make(map[string]string)
+	if pod.PodName != "" {
+		name := pod.PodName
+		if pod.PodNamespace != "" {
+			name = pod.PodNamespace + "/" + name
+		}
+		src.Details["pod"] = name + " (uid " + pod.PodUID + ")"
+	} else {
+		src.Details["pod"] = "uid " + pod.PodUID
+	}
+	if pod.Name != "" {
+		src.Details["container"] = pod.Name
+	}
+	if pod.PodQOSClass != "" {
+		src.Details["qos"] = pod.PodQOSClass
+	}
+	return src
 }
 
 func detectLXCRuntime(ancestry []model.Process) string {
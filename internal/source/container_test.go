@@ -0,0 +1,57 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestKubernetesSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *model.ContainerMatch
+		description string
+		details     map[string]string
+	}{
+		{
+			name: "deployment",
+			pod: &model.ContainerMatch{
+				Name: "nginx", PodName: "web-7d9c8b5f4-x2x4q", PodNamespace: "shop", PodUID: "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f",
+				PodQOSClass: "Burstable", PodOwnerKind: "Deployment", PodOwnerName: "web",
+			},
+			description: "Deployment shop/web",
+			details: map[string]string{
+				"pod":       "shop/web-7d9c8b5f4-x2x4q (uid 8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f)",
+				"container": "nginx",
+				"qos":       "Burstable",
+			},
+		},
+		{
+			name:        "static pod",
+			pod:         &model.ContainerMatch{Name: "kube-apiserver", PodName: "kube-apiserver-node1", PodNamespace: "kube-system", PodUID: "6b1d4f0e9a8c7b6d5e4f3a2b1c0d9e8f", PodOwnerKind: "Node"},
+			description: "static pod kube-system/kube-apiserver-node1",
+		},
+		{
+			name:    "pod unknown beyond the cgroup",
+			pod:     &model.ContainerMatch{PodUID: "8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f", PodQOSClass: "BestEffort"},
+			details: map[string]string{"pod": "uid 8d2f6c1e-4b7a-4c1d-9e2f-0a1b2c3d4e5f", "qos": "BestEffort"},
+		},
+		{name: "not resolved", pod: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := kubernetesSource(tt.pod)
+			if src.Type != model.SourceContainer || src.Name != "kubernetes" {
+				t.Fatalf("source = %s %q, want container kubernetes", src.Type, src.Name)
+			}
+			if src.Description != tt.description {
+				t.Errorf("Description = %q, want %q", src.Description, tt.description)
+			}
+			for k, v := range tt.details {
+				if src.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, src.Details[k], v)
+				}
+			}
+		})
+	}
+}
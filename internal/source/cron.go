@@ -1,20 +1,314 @@
 package source
 
 import (
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// cronRoot prefixes every crontab and spool path read, so tests can point
+// it at a fixture tree.
+var cronRoot = "/"
+
+var (
+	// cronSpoolDirs hold per-user crontabs: Debian, Red Hat, FreeBSD, macOS.
+	cronSpoolDirs = []string{"/var/spool/cron/crontabs", "/var/spool/cron", "/var/cron/tabs", "/usr/lib/cron/tabs"}
+	// atSpoolDirs hold pending and running at jobs.
+	atSpoolDirs = []string{"/var/spool/cron/atjobs", "/var/spool/at", "/var/at/jobs"}
+)
+
+// cronPeriods are the run-parts directories /etc/cron.<period> that cron or
+// anacron run at that period.
+var cronPeriods = []string{"hourly", "daily", "weekly", "monthly"}
+
+// schedulerDaemons maps job scheduler executables to the source name.
+var schedulerDaemons = map[string]string{
+	"cron":    "cron",
+	"crond":   "cron",
+	"anacron": "anacron",
+	"atd":     "at",
+}
+
+// detectCron claims processes started by cron, anacron or atd, and
+// pinpoints the job that started them: the crontab line, the run-parts
+// script, the anacrontab entry or the at job. It runs ahead of the shell and
+// systemd detectors, since every job runs under `sh -c` and, on systemd
+// hosts, inside the scheduler's own unit.
 func detectCron(ancestry []model.Process) *model.Source {
-	for _, p := range ancestry {
-		base := filepath.Base(p.Command)
-		if base == "cron" || base == "crond" {
-			return &model.Source{
-				Type: model.SourceCron,
-				Name: "cron",
-			}
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		base := filepath.Base(ancestry[i].Command)
+		name, ok := schedulerDaemons[strings.ToLower(base)]
+		if !ok {
+			continue
+		}
+		// A forked copy of the daemon is the daemon, not one of its jobs.
+		if filepath.Base(target.Command) == base {
+			return nil
 		}
+		src := &model.Source{Type: model.SourceCron, Name: name}
+		pinpointCronJob(src, ancestry[i+1:])
+		return src
 	}
 	return nil
 }
+
+// pinpointCronJob fills in the file and line of the job that started jobs
+// (the processes between the scheduler and the target), its command and its
+// schedule, when the job can be found.
+func pinpointCronJob(src *model.Source, jobs []model.Process) {
+	if src.Name == "at" {
+		if file, line, id, when := findAtJob(jobs); file != "" {
+			src.UnitFile = file + ":" + strconv.Itoa(line)
+			schedule := "once, job " + id
+			if !when.IsZero() {
+				schedule = "once at " + when.Format("2006-01-02 15:04") + ", job " + id
+			}
+			src.Details = map[string]string{"schedule": schedule}
+		}
+		return
+	}
+
+	var entries []cronEntry
+	if src.Name == "anacron" {
+		entries = anacrontabEntries()
+	} else {
+		entries = crontabEntries()
+	}
+
+	// A script run-parts runs out of /etc/cron.daily is the job itself; the
+	// entry that runs the directory gives its schedule.
+	if script, period := runPartsScript(jobs); script != "" {
+		src.UnitFile = script
+		schedule := "@" + period
+		for _, e := range entries {
+			if containsToken(e.command, "/etc/cron."+period) {
+				schedule = describeCronEntry(e)
+				break
+			}
+		}
+		src.Details = map[string]string{"schedule": schedule}
+		return
+	}
+
+	if e, ok := matchCronEntry(entries, jobs); ok {
+		src.UnitFile = e.location()
+		src.Description = e.command
+		src.Details = map[string]string{"schedule": describeCronEntry(e)}
+	}
+}
+
+// describeCronEntry renders an entry's schedule with when it next runs.
+func describeCronEntry(e cronEntry) string {
+	if e.jobID != "" {
+		last, _ := os.ReadFile(filepath.Join(cronRoot, "/var/spool/anacron", e.jobID))
+		return describeAnacronPeriod(e.schedule, string(last))
+	}
+	return describeCronSchedule(e.schedule, time.Now())
+}
+
+// crontabEntries reads the system crontab, /etc/cron.d and every user's
+// crontab.
+func crontabEntries() []cronEntry {
+	var entries []cronEntry
+	read := func(path string, system bool, owner string) {
+		data, err := os.ReadFile(filepath.Join(cronRoot, path))
+		if err == nil {
+			entries = append(entries, parseCrontab(string(data), path, system, owner)...)
+		}
+	}
+	read("/etc/crontab", true, "")
+	for _, dir := range append([]string{"/etc/cron.d"}, cronSpoolDirs...) {
+		for _, name := range cronDirFiles(dir) {
+			read(filepath.Join(dir, name), dir == "/etc/cron.d", name)
+		}
+	}
+	return entries
+}
+
+func anacrontabEntries() []cronEntry {
+	data, err := os.ReadFile(filepath.Join(cronRoot, "/etc/anacrontab"))
+	if err != nil {
+		return nil
+	}
+	return parseAnacrontab(string(data), "/etc/anacrontab")
+}
+
+// cronDirFiles lists the regular files of a crontab or spool directory,
+// skipping hidden files and editor backups.
+func cronDirFiles(dir string) []string {
+	entries, err := os.ReadDir(filepath.Join(cronRoot, dir))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// runPartsScript finds a script of an /etc/cron.<period> directory on the
+// command line of one of the job's processes.
+func runPartsScript(jobs []model.Process) (script, period string) {
+	for i := len(jobs) - 1; i >= 0; i-- {
+		for _, token := range strings.Fields(jobs[i].Cmdline) {
+			for _, p := range cronPeriods {
+				dir := "/etc/cron." + p + "/"
+				if strings.HasPrefix(token, dir) && len(token) > len(dir) {
+					return token, p
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// jobCommands returns what each of the job's processes runs, from the
+// scheduler down: the command of a `sh -c` wrapper, or the command line
+// itself. Bare shells, which read their commands from stdin, are skipped.
+func jobCommands(jobs []model.Process) []string {
+	var cmds []string
+	for _, p := range jobs {
+		if cmd, ok := shellCommand(p.Cmdline); ok {
+			cmds = append(cmds, normalizeCommand(cmd))
+			continue
+		}
+		fields := strings.Fields(p.Cmdline)
+		if len(fields) == 0 || (len(fields) == 1 && isShell(strings.ToLower(filepath.Base(fields[0])))) {
+			continue
+		}
+		cmds = append(cmds, normalizeCommand(p.Cmdline))
+	}
+	return cmds
+}
+
+// matchCronEntry finds the entry that started jobs. cron hands each command
+// to `sh -c`, so the wrapper's argument is the entry's command exactly;
+// failing that (a shell running a lone command execs it, dropping
+// redirections), an entry containing a job's command line is taken.
+func matchCronEntry(entries []cronEntry, jobs []model.Process) (cronEntry, bool) {
+	cmds := jobCommands(jobs)
+	user := ""
+	if len(jobs) > 0 {
+		user = jobs[len(jobs)-1].User
+	}
+	candidates := entries[:0:0]
+	for _, e := range entries {
+		if user == "" || e.user == "" || e.user == user {
+			candidates = append(candidates, e)
+		}
+	}
+	for _, cmd := range cmds {
+		for _, e := range candidates {
+			if runsCommand(cmd, normalizeCommand(e.command)) {
+				return e, true
+			}
+		}
+	}
+	for _, cmd := range cmds {
+		for _, e := range candidates {
+			if strings.Contains(normalizeCommand(e.command), cmd) {
+				return e, true
+			}
+		}
+	}
+	return cronEntry{}, false
+}
+
+// findAtJob finds the at job that started jobs in the at spool: atd feeds
+// the job file to a shell, so one of its lines is a job process's command.
+// The file name encodes the job number and when it was scheduled to run; a
+// running job's file is prefixed with '='.
+func findAtJob(jobs []model.Process) (file string, line int, id string, when time.Time) {
+	cmds := jobCommands(jobs)
+	if len(cmds) == 0 {
+		return "", 0, "", time.Time{}
+	}
+	for _, dir := range atSpoolDirs {
+		for _, name := range cronDirFiles(dir) {
+			data, err := os.ReadFile(filepath.Join(cronRoot, dir, name))
+			if err != nil {
+				continue
+			}
+			for i, l := range strings.Split(string(data), "\n") {
+				l = normalizeCommand(l)
+				if l == "" || strings.HasPrefix(l, "#") || strings.Contains(l, "; export ") {
+					continue
+				}
+				for _, cmd := range cmds {
+					if runsCommand(cmd, l) || strings.Contains(l, cmd) {
+						id, when := parseAtJobName(name)
+						return filepath.Join(dir, name), i + 1, id, when
+					}
+				}
+			}
+		}
+	}
+	return "", 0, "", time.Time{}
+}
+
+// parseAtJobName decodes an at spool file name, "a0000b01a8c3e4": the queue
+// letter, the job number and the minute it runs (both hex).
+func parseAtJobName(name string) (string, time.Time) {
+	name = strings.TrimPrefix(name, "=")
+	if len(name) != 14 {
+		return name, time.Time{}
+	}
+	num, err := strconv.ParseInt(name[1:6], 16, 64)
+	if err != nil {
+		return name, time.Time{}
+	}
+	id := strconv.FormatInt(num, 10)
+	minutes, err := strconv.ParseInt(name[6:], 16, 64)
+	if err != nil {
+		return id, time.Time{}
+	}
+	return id, time.Unix(minutes*60, 0)
+}
+
+// shellCommand returns the command string of a `sh -c <command>` line.
+func shellCommand(cmdline string) (string, bool) {
+	fields := strings.Fields(cmdline)
+	if len(fields) < 3 || !isShell(strings.ToLower(filepath.Base(fields[0]))) {
+		return "", false
+	}
+	_, cmd, ok := strings.Cut(cmdline, " -c ")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(cmd), true
+}
+
+// runsCommand reports whether a process running cmd was started by command,
+// directly or as a script the kernel handed to its interpreter
+// ("/bin/bash /opt/run.sh" for "/opt/run.sh").
+func runsCommand(cmd, command string) bool {
+	return cmd == command || strings.HasSuffix(cmd, " "+command)
+}
+
+func normalizeCommand(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// containsToken reports whether path appears in command as a whole word, so
+// /etc/cron.daily doesn't match /etc/cron.daily.bak.
+func containsToken(command, path string) bool {
+	for _, token := range strings.Fields(command) {
+		if strings.TrimSuffix(token, "/") == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseCrontab(t *testing.T) {
+	content := `# m h dom mon dow user command
+SHELL=/bin/sh
+PATH = /usr/bin:/bin
+17 *	* * *	root    cd / && run-parts --report /etc/cron.hourly
+@reboot  root  /usr/local/bin/warmup
+0 3 * * * backup /opt/backup/run.sh --full  % body\%here
+bogus line
+`
+	got := parseCrontab(content, "/etc/crontab", true, "")
+	want := []cronEntry{
+		{file: "/etc/crontab", line: 4, schedule: "17 * * * *", user: "root", command: "cd / && run-parts --report /etc/cron.hourly"},
+		{file: "/etc/crontab", line: 5, schedule: "@reboot", user: "root", command: "/usr/local/bin/warmup"},
+		{file: "/etc/crontab", line: 6, schedule: "0 3 * * *", user: "backup", command: "/opt/backup/run.sh --full"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseCrontab = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	user := parseCrontab("*/5 * * * * echo 100\\% > /tmp/x\n", "/var/spool/cron/crontabs/alice", false, "alice")
+	if len(user) != 1 || user[0].user != "alice" || user[0].schedule != "*/5 * * * *" || user[0].command != "echo 100% > /tmp/x" {
+		t.Errorf("user crontab = %+v", user)
+	}
+}
+
+func TestParseAnacrontab(t *testing.T) {
+	got := parseAnacrontab("SHELL=/bin/sh\n1\t5\tcron.daily\trun-parts --report /etc/cron.daily\n@monthly 15 cron.monthly run-parts /etc/cron.monthly\n", "/etc/anacrontab")
+	if len(got) != 2 {
+		t.Fatalf("parseAnacrontab = %+v", got)
+	}
+	if got[0].line != 2 || got[0].schedule != "1" || got[0].jobID != "cron.daily" || got[0].command != "run-parts --report /etc/cron.daily" {
+		t.Errorf("entry 0 = %+v", got[0])
+	}
+	if got[1].schedule != "@monthly" || got[1].jobID != "cron.monthly" {
+		t.Errorf("entry 1 = %+v", got[1])
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// Saturday 2026-10-17 10:20.
+	now := time.Date(2026, 10, 17, 10, 20, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		want string
+	}{
+		{"*/15 * * * *", "2026-10-17 10:30"},
+		{"0 3 * * *", "2026-10-18 03:00"},
+		{"30 9 * * 1-5", "2026-10-19 09:30"},
+		{"0 0 1 jan *", "2027-01-01 00:00"},
+		{"@hourly", "2026-10-17 11:00"},
+		{"0 12 * * 7", "2026-10-18 12:00"},
+		// With both day fields restricted either one matching is enough:
+		// the 20th, or the next Sunday (the 18th).
+		{"0 0 20 * sun", "2026-10-18 00:00"},
+		{"0 0 30 2 *", ""},
+	}
+	for _, tc := range cases {
+		s, err := parseCronSchedule(tc.spec)
+		if err != nil {
+			t.Errorf("parseCronSchedule(%q): %v", tc.spec, err)
+			continue
+		}
+		got := ""
+		if next := s.next(now); !next.IsZero() {
+			got = next.Format("2006-01-02 15:04")
+		}
+		if got != tc.want {
+			t.Errorf("next(%q) = %q, want %q", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"@reboot", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		if _, err := parseCronSchedule(bad); err == nil {
+			t.Errorf("parseCronSchedule(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestDescribeAnacronPeriod(t *testing.T) {
+	cases := []struct {
+		period, last, want string
+	}{
+		{"1", "20261016\n", "every day, last: 2026-10-16, next: 2026-10-17"},
+		{"7", "20261010", "every 7 days, last: 2026-10-10, next: 2026-10-17"},
+		{"@monthly", "20260917", "@monthly, last: 2026-09-17, next: 2026-10-17"},
+		{"7", "", "every 7 days"},
+	}
+	for _, tc := range cases {
+		if got := describeAnacronPeriod(tc.period, tc.last); got != tc.want {
+			t.Errorf("describeAnacronPeriod(%q, %q) = %q, want %q", tc.period, tc.last, got, tc.want)
+		}
+	}
+}
+
+func TestParseAtJobName(t *testing.T) {
+	id, when := parseAtJobName("=a0000b01d3c5e0")
+	if id != "11" || when.Unix() != 0x01d3c5e0*60 {
+		t.Errorf("parseAtJobName = %q, %v", id, when)
+	}
+	if id, when := parseAtJobName("short"); id != "short" || !when.IsZero() {
+		t.Errorf("parseAtJobName(short) = %q, %v", id, when)
+	}
+}
+
+func writeCronFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectCron(t *testing.T) {
+	root := t.TempDir()
+	cronRoot = root
+	t.Cleanup(func() { cronRoot = "/" })
+
+	writeCronFile(t, root, "/etc/crontab", "SHELL=/bin/sh\n25 6 * * * root test -x /usr/sbin/anacron || run-parts --report /etc/cron.daily\n")
+	writeCronFile(t, root, "/etc/cron.d/backup", "# nightly\nMAILTO=ops\n\n30 2 * * * backup /opt/backup/run.sh --full >/var/log/backup.log 2>&1\n")
+	writeCronFile(t, root, "/var/spool/cron/crontabs/alice", "*/5 * * * * python3 /home/alice/poll.py\n")
+	writeCronFile(t, root, "/etc/anacrontab", "1 5 cron.daily run-parts --report /etc/cron.daily\n")
+	writeCronFile(t, root, "/var/spool/anacron/cron.daily", "20261016\n")
+	writeCronFile(t, root, "/var/spool/cron/atjobs/=a0000b01d3c5e0", "#!/bin/sh\n# atrun uid=0 gid=0\nPATH=/usr/bin; export PATH\ncd /root || {\n\t exit 1\n}\n/root/migrate.sh --apply\n")
+
+	initProc := model.Process{PID: 1, Command: "systemd"}
+	cronProc := model.Process{PID: 600, PPID: 1, Command: "cron", Cmdline: "/usr/sbin/cron -f"}
+	cronChild := model.Process{PID: 700, PPID: 600, Command: "cron", Cmdline: "/usr/sbin/CRON -f"}
+
+	cases := []struct {
+		name     string
+		ancestry []model.Process
+		want     model.Source
+	}{
+		{
+			name: "sh -c wrapper matches the entry exactly",
+			ancestry: []model.Process{initProc, cronProc, cronChild,
+				{PID: 701, PPID: 700, Command: "sh", Cmdline: "/bin/sh -c /opt/backup/run.sh --full >/var/log/backup.log 2>&1", User: "backup"},
+				{PID: 702, PPID: 701, Command: "run.sh", Cmdline: "/bin/bash /opt/backup/run.sh --full", User: "backup"},
+			},
+			want: model.Source{Name: "cron", UnitFile: "/etc/cron.d/backup:4", Description: "/opt/backup/run.sh --full >/var/log/backup.log 2>&1"},
+		},
+		{
+			name: "exec'd command matches a user crontab",
+			ancestry: []model.Process{initProc, cronProc, cronChild,
+				{PID: 701, PPID: 700, Command: "python3", Cmdline: "python3 /home/alice/poll.py", User: "alice"},
+			},
+			want: model.Source{Name: "cron", UnitFile: "/var/spool/cron/crontabs/alice:1", Description: "python3 /home/alice/poll.py"},
+		},
+		{
+			name: "entries of other users are skipped",
+			ancestry: []model.Process{initProc, cronProc, cronChild,
+				{PID: 701, PPID: 700, Command: "python3", Cmdline: "python3 /home/alice/poll.py", User: "bob"},
+			},
+			want: model.Source{Name: "cron"},
+		},
+		{
+			name: "run-parts script",
+			ancestry: []model.Process{initProc, cronProc, cronChild,
+				{PID: 701, PPID: 700, Command: "sh", Cmdline: "/bin/sh -c test -x /usr/sbin/anacron || run-parts --report /etc/cron.daily", User: "root"},
+				{PID: 702, PPID: 701, Command: "run-parts", Cmdline: "run-parts --report /etc/cron.daily", User: "root"},
+				{PID: 703, PPID: 702, Command: "logrotate", Cmdline: "/bin/sh /etc/cron.daily/logrotate", User: "root"},
+			},
+			want: model.Source{Name: "cron", UnitFile: "/etc/cron.daily/logrotate"},
+		},
+		{
+			name: "anacron job",
+			ancestry: []model.Process{initProc,
+				{PID: 800, PPID: 1, Command: "anacron", Cmdline: "/usr/sbin/anacron -d -q -s"},
+				{PID: 801, PPID: 800, Command: "run-parts", Cmdline: "run-parts --report /etc/cron.daily", User: "root"},
+			},
+			want: model.Source{Name: "anacron", UnitFile: "/etc/anacrontab:1", Description: "run-parts --report /etc/cron.daily"},
+		},
+		{
+			name: "at job",
+			ancestry: []model.Process{initProc,
+				{PID: 900, PPID: 1, Command: "atd", Cmdline: "/usr/sbin/atd -f"},
+				{PID: 901, PPID: 900, Command: "sh", Cmdline: "sh", User: "root"},
+				{PID: 902, PPID: 901, Command: "migrate.sh", Cmdline: "/bin/bash /root/migrate.sh --apply", User: "root"},
+			},
+			want: model.Source{Name: "at", UnitFile: "/var/spool/cron/atjobs/=a0000b01d3c5e0:7"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectCron(tc.ancestry)
+			if got == nil {
+				t.Fatal("detectCron = nil")
+			}
+			if got.Type != model.SourceCron || got.Name != tc.want.Name || got.UnitFile != tc.want.UnitFile || got.Description != tc.want.Description {
+				t.Errorf("detectCron = %+v, want %+v", *got, tc.want)
+			}
+			if tc.want.UnitFile != "" && got.Details["schedule"] == "" {
+				t.Errorf("no schedule for %s", got.UnitFile)
+			}
+		})
+	}
+
+	// The run-parts script takes its schedule from the entry running its
+	// directory, and the anacron job its last run from the timestamp file.
+	script := detectCron(cases[3].ancestry)
+	if s := script.Details["schedule"]; !strings.HasPrefix(s, "25 6 * * *, next: ") {
+		t.Errorf("run-parts schedule = %q", s)
+	}
+	if s := detectCron(cases[4].ancestry).Details["schedule"]; s != "every day, last: 2026-10-16, next: 2026-10-17" {
+		t.Errorf("anacron schedule = %q", s)
+	}
+	if s := detectCron(cases[5].ancestry).Details["schedule"]; !strings.HasSuffix(s, ", job 11") {
+		t.Errorf("at schedule = %q", s)
+	}
+
+	// Neither the daemon nor its forks are jobs.
+	if got := detectCron([]model.Process{initProc, cronProc}); got != nil {
+		t.Errorf("detectCron(cron daemon) = %+v, want nil", *got)
+	}
+	if got := detectCron([]model.Process{initProc, cronProc, cronChild}); got != nil {
+		t.Errorf("detectCron(cron fork) = %+v, want nil", *got)
+	}
+}
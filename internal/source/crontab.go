@@ -0,0 +1,313 @@
+package source
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronEntry is one job line of a crontab or anacrontab.
+type cronEntry struct {
+	file string
+	line int
+	// schedule is the five time fields ("0 3 * * *"), a macro ("@daily"),
+	// or for anacron the period in days.
+	schedule string
+	user     string
+	// jobID is anacron's job identifier, e.g. "cron.daily".
+	jobID   string
+	command string
+}
+
+// location renders where the entry is defined, as file:line.
+func (e cronEntry) location() string {
+	return e.file + ":" + strconv.Itoa(e.line)
+}
+
+var cronEnvLine = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*=`)
+
+// parseCrontab parses the job lines of a crontab. System crontabs
+// (/etc/crontab, /etc/cron.d) carry a user field between the schedule and
+// the command; a user's own crontab doesn't, and owner is its user.
+func parseCrontab(content, file string, system bool, owner string) []cronEntry {
+	var entries []cronEntry
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || cronEnvLine.MatchString(line) {
+			continue
+		}
+		n := 5
+		if strings.HasPrefix(line, "@") {
+			n = 1
+		}
+		if system {
+			n++
+		}
+		fields, rest := splitFields(line, n)
+		if len(fields) < n || rest == "" {
+			continue
+		}
+		e := cronEntry{file: file, line: i + 1, user: owner, command: cronCommand(rest)}
+		if system {
+			e.user = fields[n-1]
+			fields = fields[:n-1]
+		}
+		e.schedule = strings.Join(fields, " ")
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// parseAnacrontab parses the job lines of /etc/anacrontab:
+// "period delay job-identifier command".
+func parseAnacrontab(content, file string) []cronEntry {
+	var entries []cronEntry
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || cronEnvLine.MatchString(line) {
+			continue
+		}
+		fields, rest := splitFields(line, 3)
+		if len(fields) < 3 || rest == "" {
+			continue
+		}
+		entries = append(entries, cronEntry{
+			file:     file,
+			line:     i + 1,
+			schedule: fields[0],
+			user:     "root",
+			jobID:    fields[2],
+			command:  rest,
+		})
+	}
+	return entries
+}
+
+// splitFields takes the first n whitespace-separated fields of line and
+// returns them with the rest of the line, whose spacing is kept.
+func splitFields(line string, n int) ([]string, string) {
+	var fields []string
+	rest := line
+	for len(fields) < n {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		fields = append(fields, rest[:end])
+		rest = rest[end:]
+	}
+	return fields, strings.TrimSpace(rest)
+}
+
+// cronCommand returns the command cron runs for a crontab command field: an
+// unescaped '%' ends the command (the rest is fed to it on stdin), and "\%"
+// stands for a literal '%'.
+func cronCommand(field string) string {
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		switch {
+		case field[i] == '\\' && i+1 < len(field) && field[i+1] == '%':
+			b.WriteByte('%')
+			i++
+		case field[i] == '%':
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// cronMacros are the @-schedules cron accepts in place of the five fields.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	cronDayNames   = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+)
+
+// cronSchedule is a parsed five-field schedule, one bit per allowed value.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a '*' day field: cron matches a day when
+	// both day fields match, unless both are restricted, then when either
+	// does.
+	domAny, dowAny bool
+}
+
+// parseCronSchedule parses a five-field schedule or an @-macro. @reboot has
+// no next run and is rejected.
+func parseCronSchedule(spec string) (cronSchedule, error) {
+	if expanded, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("schedule %q: want 5 fields", spec)
+	}
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too
+	}
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges ("1-5"),
+// steps ("*/15", "0-30/10") and, for months and weekdays, names.
+func parseCronField(field string, lo, hi int, names map[string]int) (uint64, error) {
+	value := func(s string) (int, error) {
+		if n, ok := names[strings.ToLower(s)]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("cron field %q: bad value %q", field, s)
+		}
+		return n, nil
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("cron field %q: bad step", field)
+			}
+			step = n
+		}
+		start, end := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = value(a); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = value(b); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = hi
+			}
+			if start > end {
+				return 0, fmt.Errorf("cron field %q: bad range", field)
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// next returns the first time after t the schedule fires, or the zero time
+// when it doesn't within five years (e.g. "0 0 30 2 *").
+func (s cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case s.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// describeCronSchedule renders a crontab schedule with its next run, e.g.
+// "30 3 * * 1-5, next: in 6h". @reboot and unparsable schedules are shown
+// as written.
+func describeCronSchedule(spec string, now time.Time) string {
+	s, err := parseCronSchedule(spec)
+	if err != nil {
+		return spec
+	}
+	next := s.next(now)
+	if next.IsZero() {
+		return spec
+	}
+	return spec + ", next: " + formatRelativeTime(next)
+}
+
+// describeAnacronPeriod renders an anacron period ("1", "7", "@monthly")
+// with the day the job last ran, from anacron's timestamp file, and the day
+// it is next due.
+func describeAnacronPeriod(period, lastRun string) string {
+	desc := period
+	days, err := strconv.Atoi(period)
+	switch {
+	case err == nil && days == 1:
+		desc = "every day"
+	case err == nil:
+		desc = fmt.Sprintf("every %d days", days)
+	case period == "@weekly":
+		days = 7
+	}
+	last, err := time.ParseInLocation("20060102", strings.TrimSpace(lastRun), time.Local)
+	if err != nil {
+		return desc
+	}
+	desc += ", last: " + last.Format("2006-01-02")
+	switch {
+	case period == "@monthly":
+		desc += ", next: " + last.AddDate(0, 1, 0).Format("2006-01-02")
+	case period == "@yearly" || period == "@annually":
+		desc += ", next: " + last.AddDate(1, 0, 0).Format("2006-01-02")
+	case days > 0:
+		desc += ", next: " + last.AddDate(0, 0, days).Format("2006-01-02")
+	}
+	return desc
+}
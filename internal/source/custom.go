@@ -0,0 +1,324 @@
+package source
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// builtinDetectors is the fixed detection order. It prioritizes
+// platform-specific init systems over generic supervisor detection to avoid
+// false positives; custom detectors are placed relative to these names.
+var builtinDetectors = []namedDetector{
+	{"container", detectContainer},
+	{"systemd-run", detectTransient},
+	{"cron", detectCron},
+	{"automation", detectAutomation},
+	{"ssh", detectSSH},
+	{"shell", detectShell},
+	{"detached", detectDetached},
+	{"systemd", detectSystemd},
+	{"launchd", detectLaunchd},
+	{"bsdrc", detectBsdRc},
+	{"supervisor", detectSupervisor},
+	{"windows_service", detectWindowsService},
+	{"init", detectInit},
+}
+
+type namedDetector struct {
+	name   string
+	detect func([]model.Process) *model.Source
+}
+
+// detectors is the order Detect runs: builtinDetectors with any configured
+// custom detectors spliced in.
+var detectors = builtinDetectors
+
+// sourcesFile is the sources.yaml layout.
+type sourcesFile struct {
+	Detectors []CustomDetector `yaml:"detectors"`
+}
+
+// CustomDetector declares an in-house supervisor or launcher that the
+// built-in detectors don't know. Every match criterion that is set must hold
+// (they are ANDed); at least one is required.
+//
+//	detectors:
+//	  - name: procman
+//	    description: In-house Go process manager
+//	    priority: before:systemd
+//	    binary: [procman]
+//	    cmdline: 'procman .*--job=\S+'
+//	    cgroup: '/procman\.slice/'
+//	    env: [PROCMAN_JOB]
+type CustomDetector struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Type is the reported model.SourceType; "supervisor" when empty.
+	Type string `yaml:"type"`
+	// Priority places the detector in the built-in order: "first" (the
+	// default), "last", "before:<detector>" or "after:<detector>", where
+	// <detector> is a built-in (container, systemd-run, cron, automation, ssh,
+	// shell, detached, systemd, launchd, bsdrc, supervisor, windows_service,
+	// init) or another custom detector's name.
+	Priority string `yaml:"priority"`
+
+	// Binary matches an ancestor's executable basename (case-insensitive).
+	Binary []string `yaml:"binary"`
+	// Cmdline is a regular expression matched against each ancestor's
+	// command line.
+	Cmdline string `yaml:"cmdline"`
+	// Cgroup is a regular expression matched against the target's cgroup
+	// membership (Linux).
+	Cgroup string `yaml:"cgroup"`
+	// Env lists variables the target's environment must carry, as KEY or
+	// KEY=VALUE.
+	Env []string `yaml:"env"`
+
+	file    string
+	cmdline *regexp.Regexp
+	cgroup  *regexp.Regexp
+}
+
+// DefaultSourcesPaths returns the sources.yaml files witr reads, system-wide
+// first so a user's file can override a detector of the same name.
+func DefaultSourcesPaths() []string {
+	var paths []string
+	if runtime.GOOS != "windows" {
+		paths = append(paths, "/etc/witr/sources.yaml")
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "witr", "sources.yaml"))
+	}
+	return paths
+}
+
+// LoadSourcesConfig reads the given sources.yaml files, skipping any that
+// don't exist, and installs their detectors for Detect. A detector in a later
+// file replaces one of the same name from an earlier file. Calling it with no
+// readable files restores the built-in order.
+func LoadSourcesConfig(paths ...string) error {
+	var custom []*CustomDetector
+	byName := make(map[string]int)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var f sourcesFile
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for i := range f.Detectors {
+			d := &f.Detectors[i]
+			d.file = path
+			if err := d.compile(); err != nil {
+				return fmt.Errorf("%s: detector %q: %w", path, d.Name, err)
+			}
+			if idx, ok := byName[d.Name]; ok {
+				custom[idx] = d
+				continue
+			}
+			byName[d.Name] = len(custom)
+			custom = append(custom, d)
+		}
+	}
+
+	order, err := arrangeDetectors(custom)
+	if err != nil {
+		return err
+	}
+	detectors = order
+	return nil
+}
+
+func (d *CustomDetector) compile() error {
+	if d.Name == "" {
+		return errors.New("missing name")
+	}
+	if _, ok := builtinIndex(d.Name); ok {
+		return fmt.Errorf("name clashes with the built-in %q detector", d.Name)
+	}
+	if len(d.Binary) == 0 && d.Cmdline == "" && d.Cgroup == "" && len(d.Env) == 0 {
+		return errors.New("needs at least one of binary, cmdline, cgroup or env")
+	}
+	var err error
+	if d.Cmdline != "" {
+		if d.cmdline, err = regexp.Compile(d.Cmdline); err != nil {
+			return fmt.Errorf("invalid cmdline pattern: %w", err)
+		}
+	}
+	if d.Cgroup != "" {
+		if d.cgroup, err = regexp.Compile(d.Cgroup); err != nil {
+			return fmt.Errorf("invalid cgroup pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// arrangeDetectors splices custom detectors into the built-in order. A
+// detector anchored to another custom detector is placed once its anchor has
+// been, so declaration order doesn't matter.
+func arrangeDetectors(custom []*CustomDetector) ([]namedDetector, error) {
+	order := append([]namedDetector(nil), builtinDetectors...)
+	firstCount := 0
+	insert := func(idx int, nd namedDetector) {
+		order = append(order[:idx], append([]namedDetector{nd}, order[idx:]...)...)
+	}
+
+	pending := custom
+	for len(pending) > 0 {
+		var deferred []*CustomDetector
+		for _, d := range pending {
+			nd := namedDetector{name: d.Name, detect: d.detect}
+			where, anchor, _ := strings.Cut(d.Priority, ":")
+			switch where = strings.TrimSpace(where); where {
+			case "", "first":
+				insert(firstCount, nd)
+				firstCount++
+			case "last":
+				order = append(order, nd)
+			case "before", "after":
+				anchor = strings.TrimSpace(anchor)
+				idx := indexOf(order, anchor)
+				if idx < 0 {
+					if !hasDetector(custom, anchor) {
+						return nil, fmt.Errorf("%s: detector %q: unknown priority anchor %q", d.file, d.Name, anchor)
+					}
+					deferred = append(deferred, d)
+					continue
+				}
+				if where == "after" {
+					idx++
+				}
+				insert(idx, nd)
+			default:
+				return nil, fmt.Errorf("%s: detector %q: invalid priority %q (want first, last, before:<detector> or after:<detector>)", d.file, d.Name, d.Priority)
+			}
+		}
+		if len(deferred) == len(pending) {
+			return nil, fmt.Errorf("%s: detector %q: priority anchors form a cycle", deferred[0].file, deferred[0].Name)
+		}
+		pending = deferred
+	}
+	return order, nil
+}
+
+func builtinIndex(name string) (int, bool) {
+	idx := indexOf(builtinDetectors, name)
+	return idx, idx >= 0
+}
+
+func indexOf(ds []namedDetector, name string) int {
+	for i, d := range ds {
+		if d.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasDetector(ds []*CustomDetector, name string) bool {
+	for _, d := range ds {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// detect reports the configured source when every criterion matches. Binary
+// and cmdline are checked against the target's ancestors (a supervisor is
+// never its own source); cgroup and env against the target itself.
+func (d *CustomDetector) detect(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	parents := ancestry[:len(ancestry)-1]
+
+	var matched *model.Process
+	if len(d.Binary) > 0 {
+		matched = findAncestor(parents, func(p model.Process) bool {
+			for _, b := range d.Binary {
+				if strings.EqualFold(filepath.Base(p.Command), b) || strings.EqualFold(filepath.Base(p.Exe), b) {
+					return true
+				}
+			}
+			return false
+		})
+		if matched == nil {
+			return nil
+		}
+	}
+	if d.cmdline != nil {
+		m := findAncestor(parents, func(p model.Process) bool { return d.cmdline.MatchString(p.Cmdline) })
+		if m == nil {
+			return nil
+		}
+		if matched == nil {
+			matched = m
+		}
+	}
+	if d.cgroup != nil && !d.cgroup.MatchString(procpkg.ReadCgroup(target.PID)) {
+		return nil
+	}
+	for _, want := range d.Env {
+		if !hasEnv(target.Env, want) {
+			return nil
+		}
+	}
+
+	srcType := model.SourceSupervisor
+	if d.Type != "" {
+		srcType = model.SourceType(d.Type)
+	}
+	src := &model.Source{
+		Type:        srcType,
+		Name:        d.Name,
+		Description: d.Description,
+		Details:     map[string]string{"detector": d.file},
+	}
+	if matched != nil {
+		src.Details["matched"] = fmt.Sprintf("%s (pid %d)", matched.Command, matched.PID)
+	}
+	return src
+}
+
+// findAncestor returns the closest ancestor satisfying match.
+func findAncestor(parents []model.Process, match func(model.Process) bool) *model.Process {
+	for i := len(parents) - 1; i >= 0; i-- {
+		if match(parents[i]) {
+			return &parents[i]
+		}
+	}
+	return nil
+}
+
+func hasEnv(env []string, want string) bool {
+	key, value, withValue := strings.Cut(want, "=")
+	for _, e := range env {
+		k, v, _ := strings.Cut(e, "=")
+		if k == key && (!withValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeSources(t *testing.T, name, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadSources(t *testing.T, paths ...string) error {
+	t.Helper()
+	t.Cleanup(func() { LoadSourcesConfig() })
+	return LoadSourcesConfig(paths...)
+}
+
+func detectorNames() []string {
+	names := make([]string, len(detectors))
+	for i, d := range detectors {
+		names[i] = d.name
+	}
+	return names
+}
+
+func TestCustomDetectorPriority(t *testing.T) {
+	path := writeSources(t, "sources.yaml", `
+detectors:
+  - name: late
+    priority: after:pm2
+    binary: [late]
+  - name: pm2
+    priority: before:systemd
+    binary: [pm2]
+  - name: fallback
+    priority: last
+    binary: [fallback]
+  - name: procman
+    binary: [procman]
+  - name: runner
+    priority: after:supervisor
+    binary: [runner]
+`)
+	if err := loadSources(t, path); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"procman", "container", "systemd-run", "cron", "automation", "ssh", "shell", "detached", "pm2", "late", "systemd", "launchd", "bsdrc", "supervisor", "runner", "windows_service", "init", "fallback"}
+	if got := detectorNames(); !slices.Equal(got, want) {
+		t.Errorf("order = %v\nwant    %v", got, want)
+	}
+
+	if err := LoadSourcesConfig(); err != nil || len(detectors) != len(builtinDetectors) {
+		t.Errorf("LoadSourcesConfig() should restore the built-in order, got %v (%v)", detectorNames(), err)
+	}
+}
+
+func TestCustomDetectorMatch(t *testing.T) {
+	path := writeSources(t, "sources.yaml", `
+detectors:
+  - name: procman
+    description: In-house process manager
+    binary: [procman]
+    cmdline: '--job=\S+'
+  - name: batch
+    type: cron
+    priority: after:shell
+    env: [BATCH_JOB=nightly]
+`)
+	if err := loadSources(t, path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ancestry []model.Process
+		want     string
+		wantType model.SourceType
+	}{
+		{
+			name: "binary and cmdline on a parent",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 10, PPID: 1, Command: "bash"},
+				{PID: 20, PPID: 10, Command: "procman", Exe: "/opt/procman/bin/procman", Cmdline: "procman --job=indexer"},
+				{PID: 30, PPID: 20, Command: "indexer", Cmdline: "indexer"},
+			},
+			want:     "procman",
+			wantType: model.SourceSupervisor,
+		},
+		{
+			name: "cmdline must also hold",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 10, PPID: 1, Command: "bash"},
+				{PID: 20, PPID: 10, Command: "procman", Cmdline: "procman --status"},
+				{PID: 30, PPID: 20, Command: "indexer"},
+			},
+			want:     "bash",
+			wantType: model.SourceShell,
+		},
+		{
+			name: "the target is never its own supervisor",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 10, PPID: 1, Command: "bash"},
+				{PID: 20, PPID: 10, Command: "procman", Cmdline: "procman --job=x"},
+			},
+			want:     "bash",
+			wantType: model.SourceShell,
+		},
+		{
+			name: "env on the target, ranked after shell",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 40, PPID: 1, Command: "report", Env: []string{"PATH=/bin", "BATCH_JOB=nightly"}},
+			},
+			want:     "batch",
+			wantType: model.SourceCron,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := Detect(tt.ancestry)
+			if src.Name != tt.want || src.Type != tt.wantType {
+				t.Errorf("Detect = %s %q, want %s %q", src.Type, src.Name, tt.wantType, tt.want)
+			}
+		})
+	}
+
+	src := Detect(tests[0].ancestry)
+	if src.Description != "In-house process manager" || src.Details["detector"] != path || src.Details["matched"] != "procman (pid 20)" {
+		t.Errorf("source = %+v", src)
+	}
+}
+
+func TestCustomDetectorOverride(t *testing.T) {
+	system := writeSources(t, "system.yaml", `
+detectors:
+  - name: procman
+    binary: [procman]
+`)
+	user := writeSources(t, "user.yaml", `
+detectors:
+  - name: procman
+    description: overridden
+    priority: last
+    binary: [procman]
+`)
+	if err := loadSources(t, system, filepath.Join(t.TempDir(), "missing.yaml"), user); err != nil {
+		t.Fatal(err)
+	}
+	names := detectorNames()
+	if len(names) != len(builtinDetectors)+1 || names[len(names)-1] != "procman" {
+		t.Errorf("order = %v, want the user's procman only, last", names)
+	}
+}
+
+func TestLoadSourcesConfigErrors(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"missing name", "detectors:\n  - binary: [x]\n", "missing name"},
+		{"no criteria", "detectors:\n  - name: x\n", "needs at least one"},
+		{"builtin name", "detectors:\n  - name: systemd\n    binary: [x]\n", "clashes with the built-in"},
+		{"bad regexp", "detectors:\n  - name: x\n    cmdline: '('\n", "invalid cmdline pattern"},
+		{"bad priority", "detectors:\n  - name: x\n    binary: [x]\n    priority: middle\n", "invalid priority"},
+		{"unknown anchor", "detectors:\n  - name: x\n    binary: [x]\n    priority: before:nope\n", "unknown priority anchor"},
+		{"cycle", "detectors:\n  - name: a\n    binary: [a]\n    priority: after:b\n  - name: b\n    binary: [b]\n    priority: after:a\n", "cycle"},
+		{"unknown field", "detectors:\n  - name: x\n    binaries: [x]\n", "binaries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadSources(t, writeSources(t, "sources.yaml", tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+			if len(detectors) != len(builtinDetectors) {
+				t.Errorf("a failed load must keep the previous detectors, got %v", detectorNames())
+			}
+		})
+	}
+}
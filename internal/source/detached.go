@@ -0,0 +1,148 @@
+package source
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detachLaunchers are the commands that detach a job from its terminal. A
+// shell records the last command it ran in $_, which the job inherits.
+var detachLaunchers = map[string]bool{
+	"nohup":     true,
+	"setsid":    true,
+	"daemonize": true,
+}
+
+// detectDetached claims processes a user started from a shell that then
+// outlived it: nohup'd, setsid'd, disowned or double-forked jobs, which the
+// kernel reparents to PID 1 or a subreaper such as systemd --user. Left to
+// detectInit they would pass for services; what gives them away is what
+// they carry from the login: its session, or the SSH connection it came in
+// over. A controlling terminal alone doesn't count, since gettys have one
+// too, and neither does SHLVL, which every shell-script ExecStart exports.
+// Processes a service manager started, even ones that forked away to PID 1,
+// are left to the systemd detector.
+func detectDetached(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	parent := ancestry[len(ancestry)-2]
+	if !isReaper(parent) || hasEnv(target.Env, "INVOCATION_ID") || inServiceCgroup(procpkg.ReadCgroup(target.PID)) {
+		return nil
+	}
+
+	session := target.Session
+	if session == "" {
+		session = envValue(target.Env, "XDG_SESSION_ID")
+	}
+	sshClient := envValue(target.Env, "SSH_CONNECTION")
+	if session == "" && sshClient == "" {
+		return nil
+	}
+
+	via, how := detachMethod(target)
+	src := &model.Source{
+		Type:        model.SourceShell,
+		Name:        via,
+		Description: fmt.Sprintf("Orphaned user launch: its shell exited and it was reparented to %s (pid %d)", filepath.Base(parent.Command), parent.PID),
+		Details:     map[string]string{"detach": how},
+	}
+
+	// The login session it was started from, as far as it can be told.
+	var origin []string
+	if session != "" {
+		origin = append(origin, "login session "+session)
+	} else if target.SID > 0 && target.SID != target.PID {
+		origin = append(origin, "session "+strconv.Itoa(target.SID))
+	}
+	tty := target.TTY
+	if tty == "" {
+		tty = strings.TrimPrefix(envValue(target.Env, "SSH_TTY"), "/dev/")
+	}
+	if tty != "" {
+		origin = append(origin, "on "+tty)
+	}
+	user := envValue(target.Env, "LOGNAME")
+	if user == "" {
+		user = envValue(target.Env, "USER")
+	}
+	if user == "" {
+		user = target.User
+	}
+	if user != "" {
+		origin = append(origin, "by "+user)
+	}
+	if fields := strings.Fields(sshClient); len(fields) > 0 {
+		origin = append(origin, "over SSH from "+fields[0])
+	}
+	if len(origin) > 0 {
+		src.Details["session"] = strings.Join(origin, " ")
+	}
+	return src
+}
+
+// isReaper reports whether p adopts orphaned processes: PID 1, or a user
+// service manager acting as a subreaper.
+func isReaper(p model.Process) bool {
+	if p.PID == 1 {
+		return true
+	}
+	return filepath.Base(p.Command) == "systemd" && strings.Contains(p.Cmdline, "--user")
+}
+
+// inServiceCgroup reports whether a cgroup places the process in a service
+// manager's unit: anything under system.slice, or a user manager's .service.
+// Login sessions (session-N.scope) and the user manager's own
+// user@UID.service don't count.
+func inServiceCgroup(cgroup string) bool {
+	for _, line := range strings.Split(cgroup, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		for _, seg := range strings.Split(strings.TrimSpace(parts[2]), "/") {
+			if seg == "system.slice" {
+				return true
+			}
+			if strings.HasSuffix(seg, ".service") && !(strings.HasPrefix(seg, "user@") || strings.HasPrefix(seg, "user-runtime-dir@")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// detachMethod names how a job was most likely detached, and describes it.
+// disown leaves no trace of its own, so it falls in with plain background
+// jobs.
+func detachMethod(p model.Process) (name, how string) {
+	launcher := filepath.Base(envValue(p.Env, "_"))
+	switch {
+	case launcher == "nohup" || (!detachLaunchers[launcher] && p.IgnoresHUP):
+		if p.IgnoresHUP {
+			return "nohup", "nohup (ignores SIGHUP)"
+		}
+		return "nohup", "nohup"
+	case detachLaunchers[launcher]:
+		return launcher, launcher
+	case p.SID > 0 && p.SID == p.PID && p.TTY == "":
+		return "setsid", "setsid (leads its own session, no terminal)"
+	}
+	return "detached", "backgrounded (&, disown) or double-forked, then its shell exited"
+}
+
+// envValue returns the value of key in env, or "".
+func envValue(env []string, key string) string {
+	for _, e := range env {
+		if k, v, ok := strings.Cut(e, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
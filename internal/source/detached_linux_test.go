@@ -0,0 +1,57 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// TestDetectServiceWrapperIsNotDetached covers a unit whose ExecStart is a
+// shell script: the daemon it starts inherits SHLVL and, once it forks, is
+// reparented to PID 1, yet it's still the unit's.
+func TestDetectServiceWrapperIsNotDetached(t *testing.T) {
+	root := t.TempDir()
+	for pid, cgroup := range map[int]string{
+		700: "0::/system.slice/tomcat.service\n",
+		701: "0::/user.slice/user-1000.slice/session-7.scope\n",
+	} {
+		dir := filepath.Join(root, strconv.Itoa(pid))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	procpkg.SetProcRoot(root)
+	t.Cleanup(func() { procpkg.SetProcRoot("") })
+
+	systemd := model.Process{PID: 1, Command: "systemd", Cmdline: "/sbin/init"}
+	wrapped := []model.Process{systemd, {
+		PID: 700, PPID: 1, Command: "java", User: "tomcat", SID: 690,
+		Env: []string{"SHLVL=1", "XDG_SESSION_ID=4", "_=/usr/bin/java"},
+	}}
+	if got := detectDetached(wrapped); got != nil {
+		t.Errorf("detectDetached = %+v, want nil for a service's process", *got)
+	}
+	src := Detect(wrapped)
+	if src.Type == model.SourceShell {
+		t.Errorf("Detect = shell %q, want the unit", src.Name)
+	}
+	if IsSystemdRunning() && (src.Type != model.SourceSystemd || src.Name != "tomcat.service") {
+		t.Errorf("Detect = %s %q, want systemd tomcat.service", src.Type, src.Name)
+	}
+
+	// The same job in a login session's scope is the user's.
+	orphan := []model.Process{systemd, {
+		PID: 701, PPID: 1, Command: "java", User: "alice", SID: 690,
+		Env: []string{"SHLVL=1", "XDG_SESSION_ID=7", "_=/usr/bin/nohup"},
+	}}
+	if got := detectDetached(orphan); got == nil || got.Name != "nohup" {
+		t.Errorf("detectDetached = %+v, want nohup", got)
+	}
+}
@@ -0,0 +1,158 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectDetached(t *testing.T) {
+	systemd := model.Process{PID: 1, Command: "systemd", Cmdline: "/sbin/init"}
+	userManager := model.Process{PID: 900, PPID: 1, Command: "systemd", Cmdline: "/usr/lib/systemd/systemd --user"}
+
+	cases := []struct {
+		name     string
+		ancestry []model.Process
+		want     *model.Source
+	}{
+		{
+			name: "nohup from an SSH login",
+			ancestry: []model.Process{systemd, {
+				PID: 4242, PPID: 1, Command: "python3", User: "alice", Session: "7", SID: 4100, PGID: 4242, IgnoresHUP: true,
+				Env: []string{"_=/usr/bin/nohup", "LOGNAME=alice", "SSH_CONNECTION=10.0.0.5 51234 10.0.0.1 22", "SSH_TTY=/dev/pts/3", "SHLVL=1"},
+			}},
+			want: &model.Source{Name: "nohup", Details: map[string]string{
+				"detach":  "nohup (ignores SIGHUP)",
+				"session": "login session 7 on pts/3 by alice over SSH from 10.0.0.5",
+			}},
+		},
+		{
+			name: "SIGHUP ignored without the nohup trace",
+			ancestry: []model.Process{systemd, {
+				PID: 500, PPID: 1, Command: "worker", User: "bob", SID: 480, IgnoresHUP: true,
+				Env: []string{"SHLVL=2", "USER=bob", "SSH_CONNECTION=10.0.0.9 40000 10.0.0.1 22"},
+			}},
+			want: &model.Source{Name: "nohup", Details: map[string]string{
+				"detach":  "nohup (ignores SIGHUP)",
+				"session": "session 480 by bob over SSH from 10.0.0.9",
+			}},
+		},
+		{
+			name: "setsid under a user manager",
+			ancestry: []model.Process{systemd, userManager, {
+				PID: 610, PPID: 900, Command: "sleep", User: "carol", SID: 610, PGID: 610,
+				Env: []string{"XDG_SESSION_ID=3"},
+			}},
+			want: &model.Source{Name: "setsid", Details: map[string]string{
+				"detach":  "setsid (leads its own session, no terminal)",
+				"session": "login session 3 by carol",
+			}},
+		},
+		{
+			name: "double fork",
+			ancestry: []model.Process{systemd, {
+				PID: 700, PPID: 1, Command: "server", User: "dave", Session: "12", SID: 650, TTY: "pts/1",
+			}},
+			want: &model.Source{Name: "detached", Details: map[string]string{
+				"detach":  "backgrounded (&, disown) or double-forked, then its shell exited",
+				"session": "login session 12 on pts/1 by dave",
+			}},
+		},
+		{
+			name: "system service",
+			ancestry: []model.Process{systemd, {
+				PID: 800, PPID: 1, Command: "nginx", User: "root", SID: 800, PGID: 800,
+				Env: []string{"PATH=/usr/bin", "INVOCATION_ID=abc"},
+			}},
+		},
+		{
+			name: "service started by a shell wrapper",
+			ancestry: []model.Process{systemd, {
+				PID: 805, PPID: 1, Command: "java", User: "app", SID: 805, PGID: 805,
+				Env: []string{"SHLVL=1", "_=/usr/bin/java"},
+			}},
+		},
+		{
+			name: "service carrying a login's variables",
+			ancestry: []model.Process{systemd, {
+				PID: 806, PPID: 1, Command: "node", User: "app", SID: 806,
+				Env: []string{"SHLVL=1", "XDG_SESSION_ID=2", "INVOCATION_ID=5d0c2f"},
+			}},
+		},
+		{
+			name: "getty with a terminal",
+			ancestry: []model.Process{systemd, {
+				PID: 810, PPID: 1, Command: "agetty", User: "root", SID: 810, TTY: "tty1",
+			}},
+		},
+		{
+			name: "user service",
+			ancestry: []model.Process{systemd, userManager, {
+				PID: 820, PPID: 900, Command: "pipewire", User: "carol", SID: 820,
+			}},
+		},
+		{
+			name: "still parented by its shell",
+			ancestry: []model.Process{systemd,
+				{PID: 100, PPID: 1, Command: "bash"},
+				{PID: 101, PPID: 100, Command: "sleep", Session: "4", Env: []string{"SHLVL=1"}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectDetached(tc.ancestry)
+			if tc.want == nil {
+				if got != nil {
+					t.Fatalf("detectDetached = %+v, want nil", *got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("detectDetached = nil")
+			}
+			if got.Type != model.SourceShell || got.Name != tc.want.Name {
+				t.Errorf("source = %s %q, want shell %q", got.Type, got.Name, tc.want.Name)
+			}
+			for k, v := range tc.want.Details {
+				if got.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, got.Details[k], v)
+				}
+			}
+			if got.Description == "" {
+				t.Error("no description")
+			}
+		})
+	}
+}
+
+func TestDetectPrefersDetachedOverInit(t *testing.T) {
+	ancestry := []model.Process{
+		{PID: 1, Command: "init"},
+		{PID: 300, PPID: 1, Command: "job", Env: []string{"SHLVL=1", "XDG_SESSION_ID=3", "_=/usr/bin/setsid"}},
+	}
+	if src := Detect(ancestry); src.Type != model.SourceShell || src.Name != "setsid" {
+		t.Errorf("Detect = %s %q, want shell setsid", src.Type, src.Name)
+	}
+}
+
+func TestInServiceCgroup(t *testing.T) {
+	tests := []struct {
+		cgroup string
+		want   bool
+	}{
+		{"0::/system.slice/tomcat.service\n", true},
+		{"0::/system.slice/cron.service\n", true},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service\n", true},
+		{"0::/user.slice/user-1000.slice/session-7.scope\n", false},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-org.gnome.Terminal.slice/vte-spawn-1.scope\n", false},
+		{"12:pids:/user.slice/user-1000.slice/session-3.scope\n1:name=systemd:/user.slice/user-1000.slice/session-3.scope\n", false},
+		{"0::/init.scope\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := inServiceCgroup(tt.cgroup); got != tt.want {
+			t.Errorf("inServiceCgroup(%q) = %v, want %v", tt.cgroup, got, tt.want)
+		}
+	}
+}
@@ -27,7 +27,70 @@ func isDangerousCapability(cap string) bool {
 	return dangerousCapabilities[cap]
 }
 
+// Built-in warning rule IDs. They are stable: policy files and CI gates key
+// on them.
+const (
+	RuleRestartLoop          = "restart-loop"
+	RuleZombie               = "zombie"
+	RuleStopped              = "stopped"
+	RuleHighCPU              = "high-cpu"
+	RuleHighMemory           = "high-memory"
+	RulePublicBind           = "public-bind"
+	RuleRoot                 = "root"
+	RuleDangerousCaps        = "dangerous-capabilities"
+	RuleNoSupervisor         = "no-supervisor"
+	RuleLongUptime           = "long-uptime"
+	RuleSuspiciousWorkingDir = "suspicious-working-dir"
+	RuleNoHealthcheck        = "no-healthcheck"
+	RuleServiceNameMismatch  = "service-name-mismatch"
+	RuleDeletedBinary        = "deleted-binary"
+	RuleLDPreload            = "ld-preload"
+	RuleDYLDEnv              = "dyld-env"
+)
+
+// BuiltinRuleSeverity is the default severity of each built-in rule.
+var BuiltinRuleSeverity = map[string]model.Severity{
+	RuleRestartLoop:          model.SeverityMedium,
+	RuleZombie:               model.SeverityMedium,
+	RuleStopped:              model.SeverityLow,
+	RuleHighCPU:              model.SeverityLow,
+	RuleHighMemory:           model.SeverityLow,
+	RulePublicBind:           model.SeverityMedium,
+	RuleRoot:                 model.SeverityLow,
+	RuleDangerousCaps:        model.SeverityHigh,
+	RuleNoSupervisor:         model.SeverityLow,
+	RuleLongUptime:           model.SeverityInfo,
+	RuleSuspiciousWorkingDir: model.SeverityMedium,
+	RuleNoHealthcheck:        model.SeverityLow,
+	RuleServiceNameMismatch:  model.SeverityLow,
+	RuleDeletedBinary:        model.SeverityHigh,
+	RuleLDPreload:            model.SeverityHigh,
+	RuleDYLDEnv:              model.SeverityHigh,
+}
+
+// BuiltinRuleDescription says what each built-in rule flags, independent of
+// any one finding's details.
+var BuiltinRuleDescription = map[string]string{
+	RuleRestartLoop:          "Service has restarted more than 5 times",
+	RuleZombie:               "Process is a zombie (defunct)",
+	RuleStopped:              "Process is stopped (T state)",
+	RuleHighCPU:              "Process is using high CPU (>2h total)",
+	RuleHighMemory:           "Process is using high memory (>1GB RSS)",
+	RulePublicBind:           "Process is listening on a public interface",
+	RuleRoot:                 "Process is running as root",
+	RuleDangerousCaps:        "Process has dangerous capabilities",
+	RuleNoSupervisor:         "No known supervisor or service manager detected",
+	RuleLongUptime:           "Process has been running for over 90 days",
+	RuleSuspiciousWorkingDir: "Process is running from a suspicious working directory",
+	RuleNoHealthcheck:        "Container has no healthcheck configured",
+	RuleServiceNameMismatch:  "Service name and process name do not match",
+	RuleDeletedBinary:        "Process is running from a deleted binary",
+	RuleLDPreload:            "Process sets LD_PRELOAD (potential library injection)",
+	RuleDYLDEnv:              "Process sets DYLD_* variables (potential library injection)",
+}
+
 type envSuspiciousRule struct {
+	id          string
 	pattern     string
 	match       func(key, pattern string) bool
 	warning     string
@@ -37,12 +100,14 @@ type envSuspiciousRule struct {
 var (
 	envVarRules = []envSuspiciousRule{
 		{
+			id:      RuleLDPreload,
 			pattern: "LD_PRELOAD",
 			match:   func(key, pattern string) bool { return key == pattern },
 			warning: "Process sets LD_PRELOAD (potential library injection)",
 		},
 
 		{
+			id:          RuleDYLDEnv,
 			pattern:     "DYLD_",
 			match:       strings.HasPrefix,
 			warning:     "Process sets DYLD_* variables (potential library injection)",
@@ -51,38 +116,13 @@ var (
 	}
 )
 
+// Detect explains an ancestry by running the detectors in order (see
+// builtinDetectors and LoadSourcesConfig); the first match wins.
 func Detect(ancestry []model.Process) model.Source {
-	// Detection order prioritizes platform-specific init systems
-	// over generic supervisor detection to avoid false positives
-	if src := detectContainer(ancestry); src != nil {
-		return *src
-	}
-	if src := detectSSH(ancestry); src != nil {
-		return *src
-	}
-	if src := detectShell(ancestry); src != nil {
-		return *src
-	}
-	if src := detectSystemd(ancestry); src != nil {
-		return *src
-	}
-	if src := detectLaunchd(ancestry); src != nil {
-		return *src
-	}
-	if src := detectBsdRc(ancestry); src != nil {
-		return *src
-	}
-	if src := detectSupervisor(ancestry); src != nil {
-		return *src
-	}
-	if src := detectCron(ancestry); src != nil {
-		return *src
-	}
-	if src := detectWindowsService(ancestry); src != nil {
-		return *src
-	}
-	if src := detectInit(ancestry); src != nil {
-		return *src
+	for _, d := range detectors {
+		if src := d.detect(ancestry); src != nil {
+			return *src
+		}
 	}
 
 	return model.Source{
@@ -90,8 +130,8 @@ func Detect(ancestry []model.Process) model.Source {
 	}
 }
 
-// env suspicious warnings returns warnings for known env based library injection patterns
-func envSuspiciousWarnings(env []string) []string {
+// envSuspiciousFindings returns findings for known env based library injection patterns
+func envSuspiciousFindings(env []string) []model.Finding {
 	matched := make([]bool, len(envVarRules))
 	matchedKeys := make([]map[string]struct{}, len(envVarRules))
 
@@ -121,15 +161,15 @@ func envSuspiciousWarnings(env []string) []string {
 		}
 	}
 
-	var warnings []string
+	var findings []model.Finding
 
-	// emit warnings in the same order as envVarRules
+	// emit findings in the same order as envVarRules
 	for i, rule := range envVarRules {
 		if !matched[i] {
 			continue
 		}
 		if !rule.includeKeys {
-			warnings = append(warnings, rule.warning)
+			findings = append(findings, finding(rule.id, rule.warning))
 			continue
 		}
 
@@ -139,45 +179,52 @@ func envSuspiciousWarnings(env []string) []string {
 			keys = append(keys, key)
 		}
 		sort.Strings(keys)
-		warnings = append(warnings, rule.warning+": "+strings.Join(keys, ", "))
+		findings = append(findings, finding(rule.id, rule.warning+": "+strings.Join(keys, ", ")))
 	}
 
-	return warnings
+	return findings
 }
 
+// Warnings returns the messages of Findings.
 func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType) []string {
+	return messages(Findings(p, restartCount, srcType...))
+}
+
+// Findings runs the built-in warning rules against the last process of the
+// ancestry, each at its BuiltinRuleSeverity.
+func Findings(p []model.Process, restartCount int, srcType ...model.SourceType) []model.Finding {
 	if len(p) == 0 {
 		return nil
 	}
 
-	var w []string
+	var w []model.Finding
 
 	last := p[len(p)-1]
 
 	// Warn on a service that has restarted many times. restartCount is the real
 	// count from the service manager (e.g. systemd NRestarts), or 0 when unknown.
 	if restartCount > 5 {
-		w = append(w, fmt.Sprintf("Service has restarted %d times", restartCount))
+		w = append(w, finding(RuleRestartLoop, fmt.Sprintf("Service has restarted %d times", restartCount)))
 	}
 
 	// Health warnings
 	switch last.Health {
 	case "zombie":
-		w = append(w, "Process is a zombie (defunct)")
+		w = append(w, finding(RuleZombie, "Process is a zombie (defunct)"))
 	case "stopped":
-		w = append(w, "Process is stopped (T state)")
+		w = append(w, finding(RuleStopped, "Process is stopped (T state)"))
 	case "high-cpu":
-		w = append(w, "Process is using high CPU (>2h total)")
+		w = append(w, finding(RuleHighCPU, "Process is using high CPU (>2h total)"))
 	case "high-mem":
-		w = append(w, "Process is using high memory (>1GB RSS)")
+		w = append(w, finding(RuleHighMemory, "Process is using high memory (>1GB RSS)"))
 	}
 
 	if IsPublicBind(last.Sockets) {
-		w = append(w, "Process is listening on a public interface")
+		w = append(w, finding(RulePublicBind, "Process is listening on a public interface"))
 	}
 
 	if last.User == "root" {
-		w = append(w, "Process is running as root")
+		w = append(w, finding(RuleRoot, "Process is running as root"))
 	} else if len(last.Capabilities) > 0 {
 		var dangerous []string
 		for _, cap := range last.Capabilities {
@@ -186,7 +233,7 @@ func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType)
 			}
 		}
 		if len(dangerous) > 0 {
-			w = append(w, "Process has dangerous capabilities: "+strings.Join(dangerous, ", "))
+			w = append(w, finding(RuleDangerousCaps, "Process has dangerous capabilities: "+strings.Join(dangerous, ", ")))
 		}
 	}
 
@@ -201,24 +248,24 @@ func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType)
 	// so an unknown source is normal there — not a reliable "unsupervised"
 	// signal — and this warning would fire on most user processes.
 	if st == model.SourceUnknown && runtime.GOOS != "windows" {
-		w = append(w, "No known supervisor or service manager detected")
+		w = append(w, finding(RuleNoSupervisor, "No known supervisor or service manager detected"))
 	}
 
 	// Warn if process is very old (>90 days). A zero start time means we
 	// couldn't read it (e.g. protected Windows processes), not that the process
 	// is ancient — skip the warning rather than emit a false positive.
 	if !last.StartedAt.IsZero() && time.Since(last.StartedAt).Hours() > 90*24 {
-		w = append(w, "Process has been running for over 90 days")
+		w = append(w, finding(RuleLongUptime, "Process has been running for over 90 days"))
 	}
 
 	if suspiciousDirs[last.WorkingDir] {
-		w = append(w, "Process is running from a suspicious working directory: "+last.WorkingDir)
+		w = append(w, finding(RuleSuspiciousWorkingDir, "Process is running from a suspicious working directory: "+last.WorkingDir))
 	}
 
 	// Warn only when the runtime confirms no healthcheck is configured. Unknown
 	// ("") — snap/flatpak, unprobed runtimes, non-Linux — does not warn.
 	if last.ContainerHealthcheck == "absent" {
-		w = append(w, "Container has no healthcheck configured")
+		w = append(w, finding(RuleNoHealthcheck, "Container has no healthcheck configured"))
 	}
 
 	// Warn if service name and process name are genuinely unrelated
@@ -236,21 +283,36 @@ func Warnings(p []model.Process, restartCount int, srcType ...model.SourceType)
 		svcCore = strings.ToLower(svcCore)
 		cmdBase := strings.ToLower(last.Command)
 		if !strings.Contains(svcCore, cmdBase) && !strings.Contains(cmdBase, svcCore) {
-			w = append(w, "Service name and process name do not match")
+			w = append(w, finding(RuleServiceNameMismatch, "Service name and process name do not match"))
 		}
 	}
 
 	// Warn if binary is deleted
 	if last.ExeDeleted {
-		w = append(w, "Process is running from a deleted binary (potential library injection or pending update)")
+		w = append(w, finding(RuleDeletedBinary, "Process is running from a deleted binary (potential library injection or pending update)"))
 	}
 
-	// Include warnings based on suspicious env variables
-	w = append(w, envSuspiciousWarnings(last.Env)...)
+	// Include findings based on suspicious env variables
+	w = append(w, envSuspiciousFindings(last.Env)...)
 
 	return w
 }
 
+func finding(ruleID, message string) model.Finding {
+	return model.Finding{RuleID: ruleID, Severity: BuiltinRuleSeverity[ruleID], Message: message}
+}
+
+func messages(findings []model.Finding) []string {
+	if len(findings) == 0 {
+		return nil
+	}
+	out := make([]string, len(findings))
+	for i, f := range findings {
+		out[i] = f.Message
+	}
+	return out
+}
+
 // EnrichSocketInfo provides human-readable explanations and workarounds for socket states
 func EnrichSocketInfo(si *model.SocketInfo) {
 	if si == nil {
@@ -271,4 +333,40 @@ func EnrichSocketInfo(si *model.SocketInfo) {
 	case "LISTEN":
 		si.Explanation = "The process is actively waiting for incoming connections."
 	}
+
+	si.Activation = SocketActivationForPort(si.Port)
+	if a := si.Activation; a != nil && si.State == "LISTEN" {
+		si.Explanation = fmt.Sprintf("systemd holds the listening socket for %s and starts %s on demand.", a.SocketUnit, a.Service)
+		if a.Accept {
+			si.Explanation = fmt.Sprintf("systemd holds the listening socket for %s and starts one %s instance per connection.", a.SocketUnit, a.Service)
+		}
+	}
+}
+
+// formatRelativeTime returns a human-friendly relative time string.
+func formatRelativeTime(t time.Time) string {
+	d := time.Since(t)
+	if d < 0 {
+		d = -d
+		switch {
+		case d < time.Minute:
+			return "in <1 min"
+		case d < time.Hour:
+			return fmt.Sprintf("in %d min", int(d.Minutes()))
+		case d < 24*time.Hour:
+			return fmt.Sprintf("in %dh", int(d.Hours()))
+		default:
+			return fmt.Sprintf("in %dd", int(d.Hours()/24))
+		}
+	}
+	switch {
+	case d < time.Minute:
+		return "<1 min ago"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
 }
@@ -129,15 +129,15 @@ func FuzzEnvSuspiciousWarningsDeterministic(f *testing.F) {
 			}
 		}
 
-		w1 := envSuspiciousWarnings(parts)
-		w2 := envSuspiciousWarnings(parts)
+		w1 := envSuspiciousFindings(parts)
+		w2 := envSuspiciousFindings(parts)
 		if !slices.Equal(w1, w2) {
 			t.Fatalf("expected deterministic output, got %v vs %v", w1, w2)
 		}
 	})
 }
 
-func TestEnvSuspiciousWarnings(t *testing.T) {
+func TestEnvSuspiciousFindings(t *testing.T) {
 	tests := []struct {
 		name string
 		env  []string
@@ -178,7 +178,7 @@ func TestEnvSuspiciousWarnings(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := envSuspiciousWarnings(tt.env)
+			got := messages(envSuspiciousFindings(tt.env))
 			if !slices.Equal(got, tt.want) {
 				t.Fatalf("got %v, want %v", got, tt.want)
 			}
@@ -8,34 +8,35 @@ import (
 )
 
 var knownSupervisors = map[string]string{
-	"pm2":          "pm2",
-	"supervisord":  "supervisord",
-	"supervisor":   "supervisord",
-	"gunicorn":     "gunicorn",
-	"uwsgi":        "uwsgi",
-	"s6-supervise": "s6",
-	"s6":           "s6",
-	"s6-svscan":    "s6",
-	"runsv":        "runit",
-	"runit":        "runit",
-	"runit-init":   "runit",
-	"openrc":       "openrc",
-	"openrc-init":  "openrc",
-	"monit":        "monit",
-	"circusd":      "circus",
-	"circus":       "circus",
-	"systemd":      "systemd service",
-	"systemctl":    "systemd service",
-	"daemontools":  "daemontools",
-	"initctl":      "upstart",
-	"tini":         "tini",
-	"docker-init":  "docker-init",
-	"podman-init":  "podman-init",
-	"smf":          "smf",
-	"launchd":      "launchd",
-	"god":          "god",
-	"forever":      "forever",
-	"nssm":         "nssm",
+	"pm2":              "pm2",
+	"supervisord":      "supervisord",
+	"supervisor":       "supervisord",
+	"gunicorn":         "gunicorn",
+	"uwsgi":            "uwsgi",
+	"s6-supervise":     "s6",
+	"s6":               "s6",
+	"s6-svscan":        "s6",
+	"runsv":            "runit",
+	"runit":            "runit",
+	"runit-init":       "runit",
+	"openrc":           "openrc",
+	"openrc-init":      "openrc",
+	"supervise-daemon": "openrc",
+	"monit":            "monit",
+	"circusd":          "circus",
+	"circus":           "circus",
+	"systemd":          "systemd service",
+	"systemctl":        "systemd service",
+	"daemontools":      "daemontools",
+	"initctl":          "upstart",
+	"tini":             "tini",
+	"docker-init":      "docker-init",
+	"podman-init":      "podman-init",
+	"smf":              "smf",
+	"launchd":          "launchd",
+	"god":              "god",
+	"forever":          "forever",
+	"nssm":             "nssm",
 }
 
 func detectSupervisor(ancestry []model.Process) *model.Source {
@@ -59,21 +60,21 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 			}
 		}
 
-		if label, ok := knownSupervisors[strings.ToLower(base)]; ok {
-			if label == "init" && hasShell {
-				continue
-			}
-			return &model.Source{
-				Type: model.SourceSupervisor,
-				Name: label,
-			}
+		label, ok := knownSupervisors[strings.ToLower(base)]
+		if ok && label == "init" && hasShell {
+			continue
+		}
+		if !ok {
+			// Match individual tokens from the cmdline against supervisor keys
+			label = matchCmdlineTokens(p.Cmdline, hasShell)
 		}
-		// Match individual tokens from the cmdline against supervisor keys
-		if label := matchCmdlineTokens(p.Cmdline, hasShell); label != "" {
-			return &model.Source{
+		if label != "" {
+			src := &model.Source{
 				Type: model.SourceSupervisor,
 				Name: label,
 			}
+			describeSupervised(src, ancestry)
+			return src
 		}
 	}
 	return nil
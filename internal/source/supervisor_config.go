@@ -0,0 +1,484 @@
+package source
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// supervisorRoot prefixes every supervisor configuration path read, so tests
+// can point it at a fixture tree.
+var supervisorRoot = "/"
+
+var (
+	// supervisordConfigs are where supervisord looks for its configuration
+	// when it isn't given -c.
+	supervisordConfigs = []string{"/usr/local/etc/supervisord.conf", "/etc/supervisord.conf", "/etc/supervisor/supervisord.conf"}
+	// runitServiceDirs and s6ServiceDirs are the scan directories (and the
+	// directories their services usually link to) on common distributions
+	// and in s6-overlay containers.
+	runitServiceDirs = []string{"/etc/service", "/var/service", "/service", "/run/runit/service", "/etc/runit/runsvdir/current", "/etc/sv"}
+	s6ServiceDirs    = []string{"/run/service", "/run/s6-rc/servicedirs", "/var/run/s6/services", "/service", "/etc/s6/sv"}
+	// pm2Ecosystems are the file names pm2 accepts for an ecosystem file.
+	pm2Ecosystems = []string{"ecosystem.config.js", "ecosystem.config.cjs", "ecosystem.config.mjs", "ecosystem.json", "ecosystem.yml", "ecosystem.yaml", "process.json"}
+)
+
+// describeSupervised fills in where a supervisor defines the program: the
+// configuration file or service directory (UnitFile), what it runs
+// (Description) and how it restarts it (Details["restart"]).
+func describeSupervised(src *model.Source, ancestry []model.Process) {
+	switch src.Name {
+	case "pm2":
+		describePM2(src, ancestry)
+	case "supervisord":
+		describeSupervisord(src, ancestry)
+	case "runit":
+		describeServiceDir(src, ancestry, "runsv", runitServiceDirs)
+	case "s6":
+		describeServiceDir(src, ancestry, "s6-supervise", s6ServiceDirs)
+	case "openrc":
+		describeOpenRC(src, ancestry)
+	}
+}
+
+func setDetail(src *model.Source, key, value string) {
+	if value == "" {
+		return
+	}
+	if src.Details == nil {
+		src.Details = make(map[string]string)
+	}
+	src.Details[key] = value
+}
+
+// supervisedEnv returns the closest value of an environment variable the
+// supervisor gave the program, looking from the target up.
+func supervisedEnv(ancestry []model.Process, key string) string {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		for _, entry := range ancestry[i].Env {
+			if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// isProgram reports whether p runs the named executable, by its command name
+// or the first word of its command line.
+func isProgram(p model.Process, name string) bool {
+	if filepath.Base(p.Command) == name {
+		return true
+	}
+	fields := strings.Fields(p.Cmdline)
+	return len(fields) > 0 && filepath.Base(fields[0]) == name
+}
+
+// rootedPath joins a path to supervisorRoot for reading.
+func rootedPath(path string) string {
+	return filepath.Join(supervisorRoot, path)
+}
+
+func supervisorFileExists(path string) bool {
+	info, err := os.Stat(rootedPath(path))
+	return err == nil && !info.IsDir()
+}
+
+// findLine returns the 1-based number of the first line of a file containing
+// all of the given strings, or 0.
+func findLine(path string, want ...string) int {
+	f, err := os.Open(rootedPath(path))
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		found := true
+		for _, w := range want {
+			if !strings.Contains(sc.Text(), w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return n
+		}
+	}
+	return 0
+}
+
+// describePM2 reads the app's pm2 description, which pm2 passes to it in the
+// environment, and finds the ecosystem file in the app's directory that
+// declares it, else the process list pm2 resurrects on boot.
+func describePM2(src *model.Source, ancestry []model.Process) {
+	name := supervisedEnv(ancestry, "name")
+	id := supervisedEnv(ancestry, "pm_id")
+	if name == "" || id == "" {
+		return
+	}
+	src.Description = "pm2 app " + name + " (id " + id + ")"
+	if script := supervisedEnv(ancestry, "pm_exec_path"); script != "" {
+		src.Description += ": " + script
+	}
+
+	if supervisedEnv(ancestry, "autorestart") == "false" {
+		setDetail(src, "restart", "never")
+	} else {
+		policy := "always"
+		if max := supervisedEnv(ancestry, "max_restarts"); max != "" {
+			policy += ", up to " + max + " unstable restarts"
+		}
+		setDetail(src, "restart", policy)
+	}
+	if n, err := strconv.Atoi(supervisedEnv(ancestry, "restart_time")); err == nil {
+		setDetail(src, "NRestarts", strconv.Itoa(n))
+	}
+
+	if cwd := supervisedEnv(ancestry, "pm_cwd"); cwd != "" {
+		for _, file := range pm2Ecosystems {
+			path := filepath.Join(cwd, file)
+			if line := findLine(path, "name", name); line > 0 {
+				src.UnitFile = path + ":" + strconv.Itoa(line)
+				return
+			}
+		}
+	}
+	if home := supervisedEnv(ancestry, "PM2_HOME"); home != "" {
+		dump := filepath.Join(home, "dump.pm2")
+		if findLine(dump, `"name"`, `"`+name+`"`) > 0 {
+			src.UnitFile = dump
+		}
+	}
+}
+
+func readSupervisorFile(path string) string {
+	data, err := os.ReadFile(rootedPath(path))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// iniSection is a section of a supervisord configuration file.
+type iniSection struct {
+	name string
+	file string
+	line int
+	keys map[string]string
+}
+
+// parseSupervisordConfig parses a supervisord configuration file and the
+// files its [include] section pulls in.
+func parseSupervisordConfig(path string) []iniSection {
+	sections := parseINI(path)
+	dir := filepath.Dir(path)
+	for _, s := range sections {
+		if s.name != "include" {
+			continue
+		}
+		for _, pattern := range strings.Fields(s.keys["files"]) {
+			pattern = strings.ReplaceAll(pattern, "%(here)s", dir)
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(dir, pattern)
+			}
+			matches, _ := filepath.Glob(rootedPath(pattern))
+			for _, m := range matches {
+				rel, err := filepath.Rel(supervisorRoot, m)
+				if err != nil {
+					continue
+				}
+				sections = append(sections, parseINI(filepath.Join("/", rel))...)
+			}
+		}
+	}
+	return sections
+}
+
+// parseINI parses the sections of an INI file the way supervisord does:
+// "key = value" or "key: value", ';' and '#' comments, and indented
+// continuation lines.
+func parseINI(path string) []iniSection {
+	content := readSupervisorFile(path)
+	var sections []iniSection
+	var lastKey string
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if idx := strings.Index(line, " ;"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, iniSection{
+				name: strings.TrimSpace(line[1 : len(line)-1]),
+				file: path,
+				line: i + 1,
+				keys: make(map[string]string),
+			})
+			lastKey = ""
+			continue
+		}
+		if len(sections) == 0 {
+			continue
+		}
+		cur := &sections[len(sections)-1]
+		if (raw[0] == ' ' || raw[0] == '\t') && lastKey != "" {
+			cur.keys[lastKey] += " " + line
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			continue
+		}
+		lastKey = strings.ToLower(strings.TrimSpace(line[:sep]))
+		cur.keys[lastKey] = strings.TrimSpace(line[sep+1:])
+	}
+	return sections
+}
+
+// supervisordConfigPath returns the configuration file supervisord was
+// started with (-c), or the first of its default locations that exists.
+func supervisordConfigPath(daemon model.Process) string {
+	fields := strings.Fields(daemon.Cmdline)
+	for i, f := range fields {
+		var path string
+		switch {
+		case (f == "-c" || f == "--configuration") && i+1 < len(fields):
+			path = fields[i+1]
+		case strings.HasPrefix(f, "--configuration="):
+			path = strings.TrimPrefix(f, "--configuration=")
+		case strings.HasPrefix(f, "-c") && len(f) > 2 && !strings.HasPrefix(f, "--"):
+			path = f[2:]
+		default:
+			continue
+		}
+		if !filepath.IsAbs(path) && daemon.WorkingDir != "" {
+			path = filepath.Join(daemon.WorkingDir, path)
+		}
+		return path
+	}
+	for _, path := range supervisordConfigs {
+		if supervisorFileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// describeSupervisord finds the [program:x] section that defines the
+// program: by the name supervisord exports to it, else by its command line.
+func describeSupervisord(src *model.Source, ancestry []model.Process) {
+	idx := -1
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		if isProgram(ancestry[i], "supervisord") || strings.Contains(ancestry[i].Cmdline, "/supervisord") {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	config := supervisordConfigPath(ancestry[idx])
+	if config == "" {
+		return
+	}
+	sections := parseSupervisordConfig(config)
+
+	found := findProgramSection(sections, supervisordProgramNames(ancestry))
+	if found == nil && idx+1 < len(ancestry) {
+		cmdline := normalizeCommand(ancestry[idx+1].Cmdline)
+		for i, s := range sections {
+			if strings.HasPrefix(s.name, "program:") && runsCommand(cmdline, normalizeCommand(s.keys["command"])) {
+				found = &sections[i]
+				break
+			}
+		}
+	}
+	if found == nil {
+		return
+	}
+	src.UnitFile = found.file + ":" + strconv.Itoa(found.line)
+	src.Description = found.keys["command"]
+	setDetail(src, "restart", supervisordRestart(found.keys))
+}
+
+// supervisordProgramNames lists the names the program's section may have,
+// from what supervisord exports: the process name, without the _NN a
+// numprocs process_name adds, then the group name, which is the program's
+// own unless it's in a [group:x].
+func supervisordProgramNames(ancestry []model.Process) []string {
+	var names []string
+	if process := supervisedEnv(ancestry, "SUPERVISOR_PROCESS_NAME"); process != "" {
+		names = append(names, process)
+		if i := strings.LastIndex(process, "_"); i > 0 {
+			if _, err := strconv.Atoi(process[i+1:]); err == nil {
+				names = append(names, process[:i])
+			}
+		}
+	}
+	if group := supervisedEnv(ancestry, "SUPERVISOR_GROUP_NAME"); group != "" {
+		names = append(names, group)
+	}
+	return names
+}
+
+// findProgramSection returns the [program:x] section named by the first of
+// names that has one.
+func findProgramSection(sections []iniSection, names []string) *iniSection {
+	for _, name := range names {
+		for i, s := range sections {
+			if s.name == "program:"+name {
+				return &sections[i]
+			}
+		}
+	}
+	return nil
+}
+
+// supervisordRestart renders a program's autorestart policy; supervisord
+// restarts on unexpected exit codes by default.
+func supervisordRestart(keys map[string]string) string {
+	switch strings.ToLower(keys["autorestart"]) {
+	case "true":
+		return "always"
+	case "false":
+		return "never"
+	}
+	codes := keys["exitcodes"]
+	if codes == "" {
+		codes = "0"
+	}
+	return "on unexpected exit (codes other than " + codes + ")"
+}
+
+// describeServiceDir finds the service directory a runsv or s6-supervise
+// process supervises: its working directory, else its argument looked up in
+// the usual scan directories, with links followed to the real definition.
+func describeServiceDir(src *model.Source, ancestry []model.Process, superviser string, dirs []string) {
+	sup := findAncestor(ancestry[:len(ancestry)-1], func(p model.Process) bool {
+		return isProgram(p, superviser)
+	})
+	if sup == nil {
+		return
+	}
+	fields := strings.Fields(sup.Cmdline)
+	name := ""
+	if len(fields) > 1 {
+		name = fields[len(fields)-1]
+	}
+
+	var dir string
+	switch {
+	case sup.WorkingDir != "" && sup.WorkingDir != "unknown" && sup.WorkingDir != "/":
+		dir = sup.WorkingDir
+	case filepath.IsAbs(name):
+		dir = name
+	case name != "":
+		for _, d := range dirs {
+			if supervisorFileExists(filepath.Join(d, name, "run")) {
+				dir = filepath.Join(d, name)
+				break
+			}
+		}
+	}
+	if dir == "" {
+		return
+	}
+	dir = resolveServiceDir(dir)
+	run := filepath.Join(dir, "run")
+	if !supervisorFileExists(run) {
+		return
+	}
+	src.UnitFile = run
+	src.Description = filepath.Base(dir)
+	if cmd := runScriptCommand(readSupervisorFile(run)); cmd != "" {
+		src.Description += ": " + cmd
+	}
+	restart := "always"
+	if supervisorFileExists(filepath.Join(dir, "down")) {
+		restart = "always, once started (down by default)"
+	}
+	setDetail(src, "restart", restart)
+}
+
+// resolveServiceDir follows the links from a scan directory entry to the
+// service directory itself, e.g. /etc/service/nginx to /etc/sv/nginx.
+func resolveServiceDir(dir string) string {
+	resolved, err := filepath.EvalSymlinks(rootedPath(dir))
+	if err != nil {
+		return dir
+	}
+	rel, err := filepath.Rel(supervisorRoot, resolved)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return dir
+	}
+	return filepath.Join("/", rel)
+}
+
+// runScriptCommand returns the command a run script execs: its last exec
+// line, with execline or chpst wrappers left as written.
+func runScriptCommand(script string) string {
+	var cmd string
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if after, ok := strings.CutPrefix(line, "exec "); ok {
+			cmd = strings.TrimSpace(after)
+		}
+	}
+	return cmd
+}
+
+// describeOpenRC finds the init script of an OpenRC service, named by the
+// RC_SVCNAME openrc-run exports or by supervise-daemon's first argument.
+func describeOpenRC(src *model.Source, ancestry []model.Process) {
+	name := supervisedEnv(ancestry, "RC_SVCNAME")
+	daemon := findAncestor(ancestry[:len(ancestry)-1], func(p model.Process) bool {
+		return isProgram(p, "supervise-daemon")
+	})
+	if name == "" && daemon != nil {
+		if fields := strings.Fields(daemon.Cmdline); len(fields) > 1 {
+			name = fields[1]
+		}
+	}
+	if name == "" {
+		return
+	}
+	script := filepath.Join("/etc/init.d", name)
+	content := readSupervisorFile(script)
+	if content == "" {
+		return
+	}
+	src.UnitFile = script
+	src.Description = shellVar(content, "description")
+
+	if daemon == nil && shellVar(content, "supervisor") != "supervise-daemon" {
+		setDetail(src, "restart", "never")
+		return
+	}
+	restart := "always (supervise-daemon)"
+	if max := shellVar(content, "respawn_max"); max != "" && max != "0" {
+		restart = "up to " + max + " times"
+		if period := shellVar(content, "respawn_period"); period != "" {
+			restart += " every " + period + "s"
+		}
+		restart += " (supervise-daemon)"
+	}
+	setDetail(src, "restart", restart)
+}
+
+// shellVar returns the value of a top-level variable assignment in a shell
+// script, e.g. description="Web server".
+func shellVar(script, key string) string {
+	for _, line := range strings.Split(script, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), key+"="); ok {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return ""
+}
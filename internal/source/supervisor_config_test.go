@@ -0,0 +1,165 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeSupervisorFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	supervisorRoot = root
+	t.Cleanup(func() { supervisorRoot = "/" })
+	return root
+}
+
+func TestDetectSupervisorConfig(t *testing.T) {
+	root := writeSupervisorFixture(t, map[string]string{
+		"/etc/supervisor/supervisord.conf": "[supervisord]\nlogfile=/var/log/supervisord.log ; main log\n\n[include]\nfiles = conf.d/*.conf\n",
+		"/etc/supervisor/conf.d/web.conf":  "[group:web]\nprograms=api\n\n[program:api]\ncommand=/srv/web/api --port 90%(process_num)02d\nnumprocs=2\nprocess_name=%(program_name)s_%(process_num)02d\n",
+		"/etc/supervisor/conf.d/worker.conf": "; queue workers\n[program:worker]\ncommand=/usr/bin/python3 -m app.worker\n  --queue default\nautorestart=true\n\n" +
+			"[program:cleaner]\ncommand = /usr/local/bin/cleaner --once\nexitcodes=0,2\n",
+		"/srv/api/ecosystem.config.js": "module.exports = {\n  apps: [{\n    name: \"api\",\n    script: \"./server.js\",\n  }],\n};\n",
+		"/home/deploy/.pm2/dump.pm2":   "[\n  {\n    \"name\": \"jobs\",\n    \"pm_exec_path\": \"/srv/jobs/index.js\"\n  }\n]\n",
+		"/etc/sv/nginx/run":            "#!/bin/sh\nexec 2>&1\nexec chpst -u www-data nginx -g 'daemon off;'\n",
+		"/run/service/redis/run":       "#!/bin/execlineb -P\nredis-server /etc/redis.conf\n",
+		"/run/service/redis/down":      "",
+		"/etc/init.d/sshd":             "#!/sbin/openrc-run\ndescription=\"OpenSSH server daemon\"\nsupervisor=supervise-daemon\nrespawn_max=5\nrespawn_period=60\n",
+		"/etc/init.d/crond":            "#!/sbin/openrc-run\ndescription='Cron daemon'\n",
+	})
+	if err := os.MkdirAll(filepath.Join(root, "/etc/service"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../sv/nginx", filepath.Join(root, "/etc/service/nginx")); err != nil {
+		t.Fatal(err)
+	}
+
+	initProc := model.Process{PID: 1, Command: "dumb-init"}
+	supervisord := model.Process{PID: 100, PPID: 1, Command: "supervisord", Cmdline: "/usr/bin/python3 /usr/bin/supervisord -n -c /etc/supervisor/supervisord.conf"}
+	pm2 := model.Process{PID: 200, PPID: 1, Command: "PM2 v5.3.0: God", Cmdline: "PM2 v5.3.0: God Daemon (/home/deploy/.pm2)"}
+
+	cases := []struct {
+		name     string
+		ancestry []model.Process
+		want     model.Source
+		restart  string
+	}{
+		{
+			name: "supervisord program by exported name",
+			ancestry: []model.Process{initProc, supervisord,
+				{PID: 101, PPID: 100, Command: "python3", Cmdline: "/usr/bin/python3 -m app.worker --queue default", Env: []string{"SUPERVISOR_GROUP_NAME=worker", "SUPERVISOR_PROCESS_NAME=worker"}},
+			},
+			want:    model.Source{Name: "supervisord", UnitFile: "/etc/supervisor/conf.d/worker.conf:2", Description: "/usr/bin/python3 -m app.worker --queue default"},
+			restart: "always",
+		},
+		{
+			name: "supervisord program in a group, one of numprocs",
+			ancestry: []model.Process{initProc, supervisord,
+				{PID: 103, PPID: 100, Command: "api", Cmdline: "/srv/web/api --port 9001", Env: []string{"SUPERVISOR_GROUP_NAME=web", "SUPERVISOR_PROCESS_NAME=api_01"}},
+			},
+			want:    model.Source{Name: "supervisord", UnitFile: "/etc/supervisor/conf.d/web.conf:4", Description: "/srv/web/api --port 90%(process_num)02d"},
+			restart: "on unexpected exit (codes other than 0)",
+		},
+		{
+			name: "supervisord program by command line when the exported name has no section",
+			ancestry: []model.Process{initProc, supervisord,
+				{PID: 104, PPID: 100, Command: "cleaner", Cmdline: "/usr/local/bin/cleaner --once", Env: []string{"SUPERVISOR_GROUP_NAME=housekeeping", "SUPERVISOR_PROCESS_NAME=sweeper"}},
+			},
+			want:    model.Source{Name: "supervisord", UnitFile: "/etc/supervisor/conf.d/worker.conf:7", Description: "/usr/local/bin/cleaner --once"},
+			restart: "on unexpected exit (codes other than 0,2)",
+		},
+		{
+			name: "supervisord program by command line",
+			ancestry: []model.Process{initProc, supervisord,
+				{PID: 102, PPID: 100, Command: "cleaner", Cmdline: "/usr/local/bin/cleaner --once"},
+			},
+			want:    model.Source{Name: "supervisord", UnitFile: "/etc/supervisor/conf.d/worker.conf:7", Description: "/usr/local/bin/cleaner --once"},
+			restart: "on unexpected exit (codes other than 0,2)",
+		},
+		{
+			name: "pm2 app from its ecosystem file",
+			ancestry: []model.Process{initProc, pm2,
+				{PID: 201, PPID: 200, Command: "node", Cmdline: "node /srv/api/server.js", Env: []string{"name=api", "pm_id=0", "pm_cwd=/srv/api", "pm_exec_path=/srv/api/server.js", "autorestart=true", "restart_time=3", "PM2_HOME=/home/deploy/.pm2"}},
+			},
+			want:    model.Source{Name: "pm2", UnitFile: "/srv/api/ecosystem.config.js:3", Description: "pm2 app api (id 0): /srv/api/server.js"},
+			restart: "always",
+		},
+		{
+			name: "pm2 app from the dump file",
+			ancestry: []model.Process{initProc, pm2,
+				{PID: 202, PPID: 200, Command: "node", Cmdline: "node /srv/jobs/index.js", Env: []string{"name=jobs", "pm_id=1", "pm_cwd=/srv/jobs", "autorestart=false", "PM2_HOME=/home/deploy/.pm2"}},
+			},
+			want:    model.Source{Name: "pm2", UnitFile: "/home/deploy/.pm2/dump.pm2", Description: "pm2 app jobs (id 1)"},
+			restart: "never",
+		},
+		{
+			name: "runit service linked from the scan directory",
+			ancestry: []model.Process{initProc,
+				{PID: 300, PPID: 1, Command: "runsvdir", Cmdline: "runsvdir -P /etc/service"},
+				{PID: 301, PPID: 300, Command: "runsv", Cmdline: "runsv nginx"},
+				{PID: 302, PPID: 301, Command: "nginx", Cmdline: "nginx: master process nginx -g daemon off;"},
+			},
+			want:    model.Source{Name: "runit", UnitFile: "/etc/sv/nginx/run", Description: "nginx: chpst -u www-data nginx -g 'daemon off;'"},
+			restart: "always",
+		},
+		{
+			name: "s6 service down by default",
+			ancestry: []model.Process{initProc,
+				{PID: 400, PPID: 1, Command: "s6-svscan", Cmdline: "s6-svscan /run/service"},
+				{PID: 401, PPID: 400, Command: "s6-supervise", Cmdline: "s6-supervise redis"},
+				{PID: 402, PPID: 401, Command: "redis-server", Cmdline: "redis-server /etc/redis.conf"},
+			},
+			want:    model.Source{Name: "s6", UnitFile: "/run/service/redis/run", Description: "redis"},
+			restart: "always, once started (down by default)",
+		},
+		{
+			name: "openrc service under supervise-daemon",
+			ancestry: []model.Process{initProc,
+				{PID: 500, PPID: 1, Command: "supervise-daemon", Cmdline: "supervise-daemon sshd --start /usr/sbin/sshd -- -D"},
+				{PID: 501, PPID: 500, Command: "sshd", Cmdline: "/usr/sbin/sshd -D"},
+			},
+			want:    model.Source{Name: "openrc", UnitFile: "/etc/init.d/sshd", Description: "OpenSSH server daemon"},
+			restart: "up to 5 times every 60s (supervise-daemon)",
+		},
+		{
+			name: "openrc service by RC_SVCNAME",
+			ancestry: []model.Process{initProc,
+				{PID: 600, PPID: 1, Command: "openrc", Cmdline: "/sbin/openrc default"},
+				{PID: 601, PPID: 600, Command: "crond", Cmdline: "/usr/sbin/crond", Env: []string{"RC_SVCNAME=crond"}},
+			},
+			want:    model.Source{Name: "openrc", UnitFile: "/etc/init.d/crond", Description: "Cron daemon"},
+			restart: "never",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectSupervisor(tc.ancestry)
+			if got == nil {
+				t.Fatal("detectSupervisor = nil")
+			}
+			if got.Name != tc.want.Name || got.UnitFile != tc.want.UnitFile || got.Description != tc.want.Description {
+				t.Errorf("detectSupervisor = %+v\nwant %+v", *got, tc.want)
+			}
+			if r := got.Details["restart"]; r != tc.restart {
+				t.Errorf("restart = %q, want %q", r, tc.restart)
+			}
+		})
+	}
+
+	api := detectSupervisor(cases[4].ancestry)
+	if n := api.Details["NRestarts"]; n != "3" {
+		t.Errorf("pm2 NRestarts = %q, want 3", n)
+	}
+}
@@ -10,3 +10,13 @@ func detectSystemd(_ []model.Process) *model.Source {
 
 // IsSystemdRunning always returns false on macOS.
 func IsSystemdRunning() bool { return false }
+
+func detectTransient(_ []model.Process) *model.Source {
+	return nil
+}
+
+// DescribeUnit is a no-op on macOS, which has no systemd units.
+func DescribeUnit(_ *model.Unit) {}
+
+// SocketActivationForPort always returns nil on macOS.
+func SocketActivationForPort(_ int) *model.SocketActivation { return nil }
@@ -6,12 +6,17 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -44,27 +49,55 @@ func detectSystemd(ancestry []model.Process) *model.Source {
 		return nil
 	}
 
-	// The unit name comes for free from the process cgroup; description, unit
-	// file, restart count and timer schedule are best-effort enrichment over
-	// systemd's D-Bus API.
-	unitName := getUnitNameFromCgroup(ancestry[len(ancestry)-1].PID)
+	return systemdSource(unitFromCgroup(procpkg.ReadCgroup(ancestry[len(ancestry)-1].PID)))
+}
+
+// detectTransient claims processes in a transient unit created by
+// systemd-run ahead of the shell and SSH detectors: `systemd-run --scope`
+// runs its command as a child of the invoking shell, but it's the unit, with
+// the properties it was created with, that explains the process.
+func detectTransient(ancestry []model.Process) *model.Source {
+	if !IsSystemdRunning() || len(ancestry) == 0 {
+		return nil
+	}
+	cu := unitFromCgroup(procpkg.ReadCgroup(ancestry[len(ancestry)-1].PID))
+	if !transientUnitName.MatchString(cu.Name) {
+		return nil
+	}
+	return systemdSource(cu)
+}
 
+// systemdSource builds the source for a process in unit cu. The unit name
+// comes for free from the process cgroup; description, unit file, restart
+// count, timer schedule and transient-unit properties are best-effort
+// enrichment over systemd's D-Bus API.
+func systemdSource(cu cgroupUnit) *model.Source {
 	src := &model.Source{
 		Type:    model.SourceSystemd,
-		Name:    unitName,
+		Name:    cu.Name,
 		Details: map[string]string{},
 	}
-	enrichFromSystemd(src, unitName)
+	if cu.UserUID >= 0 {
+		src.Details["manager"] = "user"
+		src.Details["uid"] = strconv.Itoa(cu.UserUID)
+	}
+	if transientUnitName.MatchString(cu.Name) {
+		src.Details["transient"] = "systemd-run"
+	}
+	enrichFromSystemd(src, cu)
 	return src
 }
 
-// enrichFromSystemd fills Description, UnitFile, NRestarts and (for timer-
-// triggered services) the schedule via systemd's D-Bus API. Every step is
+// enrichFromSystemd fills Description, UnitFile, NRestarts, (for timer-
+// triggered services) the schedule and (for transient units) how the unit
+// was created via systemd's D-Bus API, asking the per-user manager for units
+// under user@<uid>.service. Every step is
 // best-effort: a missing bus, a permission error, or an unloaded unit just
 // leaves the corresponding field empty rather than failing detection. This
 // replaces forking `systemctl show` (2-3 processes per report) with a single
 // short-lived D-Bus connection.
-func enrichFromSystemd(src *model.Source, unitName string) {
+func enrichFromSystemd(src *model.Source, cu cgroupUnit) {
+	unitName := cu.Name
 	if unitName == "" {
 		return
 	}
@@ -72,7 +105,7 @@ func enrichFromSystemd(src *model.Source, unitName string) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
 	defer cancel()
 
-	conn, err := sd.NewSystemConnectionContext(ctx)
+	conn, err := connectManager(ctx, cu.UserUID)
 	if err != nil {
 		return // no usable bus — keep the cgroup-derived unit name only
 	}
@@ -85,11 +118,31 @@ func enrichFromSystemd(src *model.Source, unitName string) {
 		} else if sp := stringProp(unit, "SourcePath"); sp != "" {
 			src.UnitFile = sp
 		}
+		if chain := activationChain(unitName, unitProps(ctx, conn, unitName, unit)); len(chain) > 1 {
+			src.Details["activation"] = strings.Join(chain, " → ")
+		}
+		// A per-connection instance of an Accept=yes socket has no
+		// dependency on the socket unit to walk; name it directly.
+		if socket := acceptSocket(ctx, conn, unitName); socket != "" {
+			src.Details["socket"] = socket
+			if src.Details["activation"] == "" {
+				src.Details["activation"] = socket + " → " + unitName
+			}
+		}
+		// systemd-run --unit=<name> creates transient units under any name.
+		if transient, _ := unit["Transient"].(bool); transient {
+			src.Details["transient"] = "systemd-run"
+		}
 	}
 
 	if strings.HasSuffix(unitName, ".service") {
 		if svc, err := conn.GetUnitTypePropertiesContext(ctx, unitName, "Service"); err == nil {
 			src.Details["NRestarts"] = strconv.FormatUint(uint64(uint32Prop(svc, "NRestarts")), 10)
+			if src.Details["transient"] != "" {
+				if exec := execStart(svc["ExecStart"]); exec != "" {
+					src.Details["exec"] = exec
+				}
+			}
 		}
 		timerUnit := strings.TrimSuffix(unitName, ".service") + ".timer"
 		if sched := timerSchedule(ctx, conn, timerUnit); sched != "" {
@@ -98,6 +151,258 @@ func enrichFromSystemd(src *model.Source, unitName string) {
 	}
 }
 
+// DescribeUnit fills in u's description, unit file, state, dependents,
+// triggers and main/control PIDs from systemd's D-Bus API. Like
+// enrichFromSystemd it is best-effort, and it leaves u alone while replaying
+// a snapshot, whose units the live bus knows nothing about.
+func DescribeUnit(u *model.Unit) {
+	if u.Name == "" || procpkg.ActiveReplay() != nil || !IsSystemdRunning() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
+	defer cancel()
+
+	conn, err := sd.NewSystemConnectionContext(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	props, err := conn.GetUnitPropertiesContext(ctx, u.Name)
+	if err != nil || stringProp(props, "LoadState") == "not-found" {
+		return
+	}
+	u.Description = stringProp(props, "Description")
+	u.UnitFile = stringProp(props, "FragmentPath")
+	if u.UnitFile == "" {
+		u.UnitFile = stringProp(props, "SourcePath")
+	}
+	u.ActiveState = stringProp(props, "ActiveState")
+	u.SubState = stringProp(props, "SubState")
+	u.WantedBy = stringsProp(props, "WantedBy")
+	u.RequiredBy = stringsProp(props, "RequiredBy")
+	u.TriggeredBy = stringsProp(props, "TriggeredBy")
+	if chain := activationChain(u.Name, unitProps(ctx, conn, u.Name, props)); len(chain) > 1 {
+		u.Activation = chain
+	}
+
+	if strings.HasSuffix(u.Name, ".service") {
+		if svc, err := conn.GetUnitTypePropertiesContext(ctx, u.Name, "Service"); err == nil {
+			u.MainPID = int(uint32Prop(svc, "MainPID"))
+			u.ControlPID = int(uint32Prop(svc, "ControlPID"))
+		}
+	}
+	for _, trigger := range u.TriggeredBy {
+		if strings.HasSuffix(trigger, ".timer") {
+			if sched := timerSchedule(ctx, conn, trigger); sched != "" {
+				u.Schedule = sched
+				break
+			}
+		}
+	}
+}
+
+// SocketActivationForPort finds the systemd .socket unit listening on port
+// and the state of the service it starts, or returns nil when no socket unit
+// listens there (or the bus is unavailable, or a snapshot is being
+// replayed).
+func SocketActivationForPort(port int) *model.SocketActivation {
+	if port <= 0 || procpkg.ActiveReplay() != nil || !IsSystemdRunning() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbusTimeout)
+	defer cancel()
+
+	conn, err := sd.NewSystemConnectionContext(ctx)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	sockets, err := conn.ListUnitsByPatternsContext(ctx, []string{"active", "activating", "reloading"}, []string{"*.socket"})
+	if err != nil {
+		return nil
+	}
+	for _, us := range sockets {
+		props, err := conn.GetUnitTypePropertiesContext(ctx, us.Name, "Socket")
+		if err != nil {
+			continue
+		}
+		listen := socketListen(props["Listen"])
+		if !listensOnPort(listen, port) {
+			continue
+		}
+
+		act := &model.SocketActivation{SocketUnit: us.Name, Listen: listen, Connections: int(uint32Prop(props, "NConnections"))}
+		act.Accept, _ = props["Accept"].(bool)
+		base := strings.TrimSuffix(us.Name, ".socket")
+		if act.Accept {
+			act.Service = base + "@.service"
+			if instances, err := conn.ListUnitsByPatternsContext(ctx, []string{"active", "activating", "reloading"}, []string{base + "@*.service"}); err == nil {
+				for _, inst := range instances {
+					act.Instances = append(act.Instances, inst.Name)
+				}
+				sort.Strings(act.Instances)
+			}
+			return act
+		}
+
+		act.Service = base + ".service"
+		if unit, err := conn.GetUnitPropertiesContext(ctx, us.Name); err == nil {
+			if triggers := stringsProp(unit, "Triggers"); len(triggers) > 0 {
+				act.Service = triggers[0]
+			}
+		}
+		if unit, err := conn.GetUnitPropertiesContext(ctx, act.Service); err == nil {
+			act.ServiceState = stringProp(unit, "ActiveState")
+		}
+		if svc, err := conn.GetUnitTypePropertiesContext(ctx, act.Service, "Service"); err == nil {
+			act.ServicePID = int(uint32Prop(svc, "MainPID"))
+		}
+		return act
+	}
+	return nil
+}
+
+// socketListen renders a socket unit's Listen property, which D-Bus delivers
+// as an array of (type, address) such as ("Stream", "[::]:80"), keeping the
+// network listeners.
+func socketListen(v interface{}) []string {
+	var out []string
+	for _, e := range timerEntries(v) {
+		if len(e) < 2 {
+			continue
+		}
+		kind, _ := e[0].(string)
+		addr, _ := e[1].(string)
+		if (kind == "Stream" || kind == "Datagram" || kind == "SequentialPacket") && addr != "" && !strings.HasPrefix(addr, "/") && !strings.HasPrefix(addr, "@") {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+// listensOnPort reports whether any of a socket unit's listen addresses
+// ("80", "0.0.0.0:80", "[::]:80", "127.0.0.1%lo:53") is on port.
+func listensOnPort(listen []string, port int) bool {
+	want := strconv.Itoa(port)
+	for _, addr := range listen {
+		if addr == want {
+			return true
+		}
+		if _, p, err := net.SplitHostPort(addr); err == nil && p == want {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptSocket returns the Accept=yes socket unit that spawned an instance
+// unit (sshd@3-10.0.0.1:22-10.0.0.9:51234.service → sshd.socket), or "".
+func acceptSocket(ctx context.Context, conn *sd.Conn, unitName string) string {
+	base, instance, ok := strings.Cut(strings.TrimSuffix(unitName, ".service"), "@")
+	if !ok || instance == "" || !strings.HasSuffix(unitName, ".service") {
+		return ""
+	}
+	socket := base + ".socket"
+	props, err := conn.GetUnitTypePropertiesContext(ctx, socket, "Socket")
+	if err != nil {
+		return ""
+	}
+	if accept, _ := props["Accept"].(bool); !accept {
+		return ""
+	}
+	return socket
+}
+
+// maxActivationDepth bounds the reverse-dependency walk; real chains are a
+// handful of units long.
+const maxActivationDepth = 8
+
+// activationTargets are the targets preferred when a unit is pulled in by
+// several, in order: the ones a boot normally reaches.
+var activationTargets = []string{"multi-user.target", "graphical.target", "default.target"}
+
+// unitProps returns a property lookup over the bus for activationChain,
+// answering name's own properties from the already-fetched known.
+func unitProps(ctx context.Context, conn *sd.Conn, name string, known map[string]interface{}) func(string) map[string]interface{} {
+	return func(unit string) map[string]interface{} {
+		if unit == name {
+			return known
+		}
+		props, err := conn.GetUnitPropertiesContext(ctx, unit)
+		if err != nil {
+			return nil
+		}
+		return props
+	}
+}
+
+// activationChain walks unitName's reverse dependencies up to the target or
+// trigger that pulled it in, and returns the chain root first, e.g.
+// [multi-user.target docker.service containerd.service] or
+// [sockets.target nginx.socket nginx.service]. The walk stops at the first
+// target, since targets are what boot (or an admin) asks for.
+func activationChain(unitName string, props func(string) map[string]interface{}) []string {
+	chain := []string{unitName}
+	seen := map[string]bool{unitName: true}
+	for unit := unitName; len(chain) < maxActivationDepth && !strings.HasSuffix(unit, ".target"); {
+		p := props(unit)
+		if p == nil {
+			break
+		}
+		next := activationParent(p, seen)
+		if next == "" {
+			break
+		}
+		chain = append(chain, next)
+		seen[next] = true
+		unit = next
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// activationParent picks the unit that most directly explains why a unit with
+// the given properties was started: a trigger (socket, timer or path unit)
+// first, then a hard dependency, then a soft one. Within each, a specific
+// unit wins over a target (docker.service over multi-user.target for
+// containerd), and among targets the usual boot targets win.
+func activationParent(props map[string]interface{}, seen map[string]bool) string {
+	for _, key := range []string{"TriggeredBy", "RequiredBy", "BoundBy", "WantedBy", "PartOf"} {
+		var units, targets []string
+		for _, u := range stringsProp(props, key) {
+			switch {
+			case seen[u]:
+			case strings.HasSuffix(u, ".target"):
+				targets = append(targets, u)
+			default:
+				units = append(units, u)
+			}
+		}
+		if len(units) > 0 {
+			sort.Strings(units)
+			return units[0]
+		}
+		for _, preferred := range activationTargets {
+			for _, t := range targets {
+				if t == preferred {
+					return t
+				}
+			}
+		}
+		if len(targets) > 0 {
+			sort.Strings(targets)
+			return targets[0]
+		}
+	}
+	return ""
+}
+
 // timerSchedule renders a "<spec>, last: …, next: …" line for a .timer unit,
 // or "" when the timer isn't loaded.
 func timerSchedule(ctx context.Context, conn *sd.Conn, timerUnit string) string {
@@ -208,6 +513,11 @@ func stringProp(m map[string]interface{}, key string) string {
 	return s
 }
 
+func stringsProp(m map[string]interface{}, key string) []string {
+	list, _ := m[key].([]string)
+	return list
+}
+
 func uint32Prop(m map[string]interface{}, key string) uint32 {
 	n, _ := m[key].(uint32)
 	return n
@@ -218,60 +528,101 @@ func uint64Prop(m map[string]interface{}, key string) uint64 {
 	return n
 }
 
-// formatRelativeTime returns a human-friendly relative time string.
-func formatRelativeTime(t time.Time) string {
-	d := time.Since(t)
-	if d < 0 {
-		d = -d
-		switch {
-		case d < time.Minute:
-			return "in <1 min"
-		case d < time.Hour:
-			return fmt.Sprintf("in %d min", int(d.Minutes()))
-		case d < 24*time.Hour:
-			return fmt.Sprintf("in %dh", int(d.Hours()))
-		default:
-			return fmt.Sprintf("in %dd", int(d.Hours()/24))
-		}
+// transientUnitName matches the names systemd-run gives the units it
+// creates: run-u123.service, run-r<hex>.scope, run-p<pid>-i<n>.service.
+var transientUnitName = regexp.MustCompile(`^run-(u[0-9]+|r[0-9a-f]+|p[0-9]+-i[0-9]+)\.(service|scope)$`)
+
+// userManagerUnit matches the unit of a per-user service manager.
+var userManagerUnit = regexp.MustCompile(`^user@([0-9]+)\.service$`)
+
+// cgroupUnit is the systemd unit a process belongs to.
+type cgroupUnit struct {
+	Name string
+	// UserUID is the owner of the per-user manager (user@<uid>.service) the
+	// unit runs under, or -1 for a unit of the system manager.
+	UserUID int
+}
+
+// connectManager connects to the system manager, or with userUID >= 0 to
+// that user's manager over its per-user bus. A user bus only admits its
+// owner (and, depending on the bus implementation, root), so reaching other
+// users' managers fails unless permitted; callers treat that like a missing
+// bus.
+func connectManager(ctx context.Context, userUID int) (*sd.Conn, error) {
+	if userUID < 0 {
+		return sd.NewSystemConnectionContext(ctx)
 	}
-	switch {
-	case d < time.Minute:
-		return "<1 min ago"
-	case d < time.Hour:
-		return fmt.Sprintf("%d min ago", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh ago", int(d.Hours()))
-	default:
-		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	addr := "unix:path=/run/user/" + strconv.Itoa(userUID) + "/bus"
+	return sd.NewConnection(func() (*dbus.Conn, error) {
+		conn, err := dbus.Dial(addr, dbus.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.Auth([]dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err := conn.Hello(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	})
+}
+
+// execStart renders a service's ExecStart property, which D-Bus delivers as
+// an array of (path, argv, ignore-failure, timestamps…, pid, code, status),
+// as the first command line.
+func execStart(v interface{}) string {
+	for _, e := range timerEntries(v) {
+		if len(e) < 2 {
+			continue
+		}
+		if argv, ok := e[1].([]string); ok && len(argv) > 0 {
+			return strings.Join(argv, " ")
+		}
+		if path, ok := e[0].(string); ok && path != "" {
+			return path
+		}
 	}
+	return ""
 }
 
 func getUnitNameFromCgroup(pid int) string {
-	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
-	if err != nil {
-		return ""
-	}
+	return unitFromCgroup(procpkg.ReadCgroup(pid)).Name
+}
 
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
+// unitFromCgroup parses /proc/<pid>/cgroup content into the deepest
+// .service or .scope unit on the process's path, noting when that unit sits
+// under a per-user manager
+// (user.slice/user-1000.slice/user@1000.service/app.slice/foo.service).
+func unitFromCgroup(content string) cgroupUnit {
+	cu := cgroupUnit{UserUID: -1}
+	for _, line := range strings.Split(content, "\n") {
 		parts := strings.SplitN(line, ":", 3)
 		if len(parts) < 3 {
 			continue
 		}
 		controllers := parts[1]
-		path := parts[2]
-
-		if controllers == "" || strings.Contains(controllers, "systemd") {
-			path = strings.TrimSpace(path)
-			pathParts := strings.Split(path, "/")
-
-			for i := len(pathParts) - 1; i >= 0; i-- {
-				part := pathParts[i]
-				if strings.HasSuffix(part, ".service") || strings.HasSuffix(part, ".scope") {
-					return part
-				}
+		if controllers != "" && !strings.Contains(controllers, "systemd") {
+			continue
+		}
+		for _, part := range strings.Split(strings.TrimSpace(parts[2]), "/") {
+			if !strings.HasSuffix(part, ".service") && !strings.HasSuffix(part, ".scope") {
+				continue
+			}
+			if m := userManagerUnit.FindStringSubmatch(part); m != nil && cu.Name == "" {
+				cu.UserUID, _ = strconv.Atoi(m[1])
 			}
+			cu.Name = part
+		}
+		if cu.Name != "" {
+			break
 		}
 	}
-	return ""
+	// The manager process itself belongs to the system manager's unit.
+	if userManagerUnit.MatchString(cu.Name) {
+		cu.UserUID = -1
+	}
+	return cu
 }
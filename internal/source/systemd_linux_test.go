@@ -5,6 +5,7 @@ package source
 import (
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -104,3 +105,115 @@ func TestGetUnitNameFromCgroupSelf(t *testing.T) {
 		t.Errorf("getUnitNameFromCgroup(0) = %q, want empty", got)
 	}
 }
+
+func TestActivationChain(t *testing.T) {
+	units := map[string]map[string]interface{}{
+		"containerd.service": {"WantedBy": []string{"multi-user.target", "docker.service"}},
+		"docker.service":     {"WantedBy": []string{"multi-user.target"}, "TriggeredBy": []string{"docker.socket"}, "RequiredBy": []string{}},
+		"docker.socket":      {"WantedBy": []string{"sockets.target"}},
+		"nginx.service":      {"WantedBy": []string{"graphical.target", "multi-user.target"}},
+		"db.service":         {"RequiredBy": []string{"app.service"}, "WantedBy": []string{"multi-user.target"}},
+		"app.service":        {"BoundBy": []string{"db.service"}},
+		"worker@1.service":   {"PartOf": []string{"worker.target"}},
+		"session-3.scope":    {},
+	}
+	props := func(name string) map[string]interface{} { return units[name] }
+
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{"containerd.service", "sockets.target → docker.socket → docker.service → containerd.service"},
+		{"nginx.service", "multi-user.target → nginx.service"},
+		// A cycle (db requires app, app is bound by db) ends the walk.
+		{"db.service", "app.service → db.service"},
+		{"worker@1.service", "worker.target → worker@1.service"},
+		{"session-3.scope", "session-3.scope"},
+		{"unknown.service", "unknown.service"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(activationChain(tt.unit, props), " → "); got != tt.want {
+			t.Errorf("activationChain(%s) = %q, want %q", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestUnitFromCgroup(t *testing.T) {
+	tests := []struct {
+		content string
+		want    cgroupUnit
+	}{
+		{"0::/system.slice/nginx.service\n", cgroupUnit{Name: "nginx.service", UserUID: -1}},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service\n", cgroupUnit{Name: "syncthing.service", UserUID: 1000}},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/run-u12.scope\n", cgroupUnit{Name: "run-u12.scope", UserUID: 1000}},
+		{"0::/user.slice/user-1000.slice/user@1000.service/init.scope\n", cgroupUnit{Name: "init.scope", UserUID: 1000}},
+		{"0::/user.slice/user-1000.slice/user@1000.service\n", cgroupUnit{Name: "user@1000.service", UserUID: -1}},
+		{"0::/user.slice/user-1000.slice/session-3.scope\n", cgroupUnit{Name: "session-3.scope", UserUID: -1}},
+		{"12:cpu:/x.service\n1:name=systemd:/system.slice/cron.service\n", cgroupUnit{Name: "cron.service", UserUID: -1}},
+		{"0::/\n", cgroupUnit{UserUID: -1}},
+	}
+	for _, tt := range tests {
+		if got := unitFromCgroup(tt.content); got != tt.want {
+			t.Errorf("unitFromCgroup(%q) = %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestTransientUnitName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"run-u123.service":        true,
+		"run-r3f2a9c.scope":       true,
+		"run-p4242-i4243.service": true,
+		"run-user-1000.mount":     false,
+		"runner.service":          false,
+		"nginx.service":           false,
+	} {
+		if got := transientUnitName.MatchString(name); got != want {
+			t.Errorf("transient(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestExecStart(t *testing.T) {
+	v := [][]interface{}{{"/usr/bin/sleep", []string{"/usr/bin/sleep", "100"}, false, uint64(0)}}
+	if got := execStart(v); got != "/usr/bin/sleep 100" {
+		t.Errorf("execStart = %q, want the command line", got)
+	}
+	if got := execStart(nil); got != "" {
+		t.Errorf("execStart(nil) = %q, want empty", got)
+	}
+}
+
+func TestSocketListen(t *testing.T) {
+	v := [][]interface{}{
+		{"Stream", "[::]:80"},
+		{"Datagram", "0.0.0.0:53"},
+		{"Stream", "/run/nginx.sock"},
+		{"Stream", "@abstract"},
+		{"FIFO", "/run/fifo"},
+	}
+	got := socketListen(v)
+	if len(got) != 2 || got[0] != "[::]:80" || got[1] != "0.0.0.0:53" {
+		t.Errorf("socketListen = %v, want the two inet addresses", got)
+	}
+}
+
+func TestListensOnPort(t *testing.T) {
+	tests := []struct {
+		listen []string
+		port   int
+		want   bool
+	}{
+		{[]string{"[::]:80"}, 80, true},
+		{[]string{"0.0.0.0:8080"}, 80, false},
+		{[]string{"22"}, 22, true},
+		{[]string{"127.0.0.1%lo:53"}, 53, true},
+		{[]string{"127.0.0.1:5353", "[::1]:53"}, 53, true},
+		{nil, 80, false},
+	}
+	for _, tt := range tests {
+		if got := listensOnPort(tt.listen, tt.port); got != tt.want {
+			t.Errorf("listensOnPort(%v, %d) = %v, want %v", tt.listen, tt.port, got, tt.want)
+		}
+	}
+}
@@ -298,3 +298,28 @@ func TestWarningsEmptyInputReturnsNil(t *testing.T) {
 		t.Errorf("Warnings(nil) = %v, want nil", got)
 	}
 }
+
+func TestFindingsCarryRuleIDs(t *testing.T) {
+	t.Parallel()
+
+	p := baseProc()
+	p.User = "root"
+	p.ExeDeleted = true
+	p.Env = []string{"LD_PRELOAD=/tmp/x.so"}
+	parent := baseProc()
+	parent.PID = 1
+	parent.Command = "systemd"
+	findings := Findings([]model.Process{parent, p}, 0, model.SourceSystemd)
+
+	var ids []string
+	for _, f := range findings {
+		if _, ok := BuiltinRuleSeverity[f.RuleID]; !ok || f.Severity != BuiltinRuleSeverity[f.RuleID] {
+			t.Errorf("finding %+v has no registered rule or the wrong severity", f)
+		}
+		ids = append(ids, f.RuleID)
+	}
+	want := []string{RuleRoot, RuleDeletedBinary, RuleLDPreload}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("rule ids = %v, want %v", ids, want)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 )
 
 // ResolveFile finds processes holding a lock on the given file path
@@ -21,9 +23,9 @@ func ResolveFile(path string) ([]int, error) {
 
 	var pids []int
 
-	procDirs, err := os.ReadDir("/proc")
+	procDirs, err := os.ReadDir(procpkg.ProcPath())
 	if err != nil {
-		return nil, fmt.Errorf("failed to read /proc: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", procpkg.ProcPath(), err)
 	}
 
 	for _, d := range procDirs {
@@ -35,7 +37,7 @@ func ResolveFile(path string) ([]int, error) {
 			continue
 		}
 
-		fdDir := filepath.Join("/proc", d.Name(), "fd")
+		fdDir := procpkg.ProcPath(d.Name(), "fd")
 		fds, err := os.ReadDir(fdDir)
 		if err != nil {
 			continue
@@ -0,0 +1,121 @@
+package target
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ResolveLogin finds every process of a --user, --session or --tty target:
+// the processes owned by a user (by name or UID), those in a login session,
+// or those whose controlling terminal is a given tty. witr itself and Linux
+// kernel threads (kthreadd, pid 2, and its children) are left out: neither
+// is something the user is running.
+func ResolveLogin(t model.Target) ([]int, error) {
+	val := strings.TrimSpace(t.Value)
+	if val == "" {
+		return nil, fmt.Errorf("invalid %s: must not be empty", t.Type)
+	}
+
+	replaying := procpkg.ActiveReplay() != nil
+	if !replaying {
+		switch {
+		case t.Type == model.TargetSession && (runtime.GOOS == "darwin" || runtime.GOOS == "freebsd"):
+			return nil, fmt.Errorf("login sessions: %w", ErrUnsupported)
+		case t.Type == model.TargetTTY && runtime.GOOS == "windows":
+			return nil, fmt.Errorf("terminals: %w", ErrUnsupported)
+		}
+	}
+
+	var match func(model.Process) bool
+	var what string
+	switch t.Type {
+	case model.TargetUser:
+		names := userNames(val, replaying)
+		match = func(p model.Process) bool { return userMatches(p.User, names) }
+		what = "for user " + val
+	case model.TargetSession:
+		match = func(p model.Process) bool { return p.Session == val }
+		what = "in session " + val
+	case model.TargetTTY:
+		tty := normalizeTTY(val)
+		match = func(p model.Process) bool { return p.TTY != "" && normalizeTTY(p.TTY) == tty }
+		what = "on " + tty
+	default:
+		return nil, fmt.Errorf("unknown target")
+	}
+
+	procs, err := procpkg.ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+	self := os.Getpid()
+	var pids []int
+	for _, p := range procs {
+		if !replaying && p.PID == self {
+			continue
+		}
+		if (runtime.GOOS == "linux" || replaying) && (p.PID == 2 || p.PPID == 2) {
+			continue
+		}
+		if match(p) {
+			pids = append(pids, p.PID)
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no running processes %s", what)
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
+
+// userNames returns the spellings a process list may use for the user val:
+// the value itself plus, on a live system, the name behind a numeric UID or
+// the UID behind a name (process lists fall back to the UID for users
+// missing from the password database).
+func userNames(val string, replaying bool) []string {
+	names := []string{val}
+	if replaying {
+		return names
+	}
+	if _, err := strconv.Atoi(val); err == nil {
+		if u, err := user.LookupId(val); err == nil {
+			names = append(names, u.Username)
+		}
+	} else if u, err := user.Lookup(val); err == nil {
+		names = append(names, u.Uid)
+	}
+	return names
+}
+
+// userMatches reports whether a process owner is one of names. Windows
+// owners are "DOMAIN\user" and matched case-insensitively, with or without
+// the domain.
+func userMatches(owner string, names []string) bool {
+	for _, name := range names {
+		if owner == name {
+			return true
+		}
+		if _, account, ok := strings.Cut(owner, `\`); ok && !strings.Contains(name, `\`) {
+			if strings.EqualFold(account, name) {
+				return true
+			}
+		} else if ok && strings.EqualFold(owner, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTTY reduces the ways a terminal is written ("/dev/pts/3",
+// "pts/3") to the form process lists use.
+func normalizeTTY(tty string) string {
+	return strings.TrimPrefix(strings.TrimSpace(tty), "/dev/")
+}
@@ -0,0 +1,92 @@
+package target
+
+import (
+	"reflect"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestUserMatches(t *testing.T) {
+	tests := []struct {
+		owner string
+		names []string
+		want  bool
+	}{
+		{"alice", []string{"alice"}, true},
+		{"1001", []string{"alice", "1001"}, true},
+		{"bob", []string{"alice"}, false},
+		{`CORP\Alice`, []string{"alice"}, true},
+		{`CORP\alice`, []string{`corp\ALICE`}, true},
+		{`CORP\bob`, []string{"alice"}, false},
+	}
+	for _, tt := range tests {
+		if got := userMatches(tt.owner, tt.names); got != tt.want {
+			t.Errorf("userMatches(%q, %v) = %v, want %v", tt.owner, tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTTY(t *testing.T) {
+	for in, want := range map[string]string{
+		"pts/3":      "pts/3",
+		"/dev/pts/3": "pts/3",
+		" ttys001 ":  "ttys001",
+	} {
+		if got := normalizeTTY(in); got != want {
+			t.Errorf("normalizeTTY(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestResolveLoginFromSnapshot(t *testing.T) {
+	procs := []model.Process{
+		{PID: 1, Command: "systemd", User: "root"},
+		{PID: 2, Command: "kthreadd", User: "root"},
+		{PID: 15, PPID: 2, Command: "rcu_preempt", User: "root"},
+		{PID: 800, PPID: 1, Command: "sshd", User: "root"},
+		{PID: 4100, PPID: 800, Command: "sshd", User: "alice", Session: "3"},
+		{PID: 4102, PPID: 4100, Command: "bash", User: "alice", Session: "3", TTY: "pts/1"},
+		{PID: 4200, PPID: 4102, Command: "vim", User: "alice", Session: "3", TTY: "pts/1"},
+		{PID: 5000, PPID: 1, Command: "node", User: "alice"},
+		{PID: 6100, PPID: 800, Command: "bash", User: "bob", Session: "5", TTY: "pts/2"},
+	}
+	details := make(map[int]model.Process)
+	for _, p := range procs {
+		details[p.PID] = p
+	}
+	archive := &snapshot.Archive{Version: snapshot.FormatVersion, Processes: procs, Details: details}
+	archive.Activate()
+	t.Cleanup(func() { procpkg.SetReplay(nil) })
+
+	tests := []struct {
+		target  model.Target
+		want    []int
+		wantErr string
+	}{
+		{model.Target{Type: model.TargetUser, Value: "alice"}, []int{4100, 4102, 4200, 5000}, ""},
+		{model.Target{Type: model.TargetUser, Value: "root"}, []int{1, 800}, ""},
+		{model.Target{Type: model.TargetSession, Value: "3"}, []int{4100, 4102, 4200}, ""},
+		{model.Target{Type: model.TargetTTY, Value: "/dev/pts/2"}, []int{6100}, ""},
+		{model.Target{Type: model.TargetUser, Value: "carol"}, nil, "no running processes for user carol"},
+		{model.Target{Type: model.TargetTTY, Value: " "}, nil, "invalid tty: must not be empty"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.target, false)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Resolve(%v) err = %v, want %q", tt.target, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%v): %v", tt.target, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Resolve(%v) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
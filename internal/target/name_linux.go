@@ -16,7 +16,7 @@ import (
 func ResolveName(name string, exact bool) ([]int, error) {
 	var procPIDs []int
 
-	entries, _ := os.ReadDir("/proc")
+	entries, _ := os.ReadDir(procpkg.ProcPath())
 	lowerName := strings.ToLower(name)
 	selfPid := os.Getpid()
 
@@ -53,7 +53,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 			continue
 		}
 
-		comm, err := os.ReadFile("/proc/" + e.Name() + "/comm")
+		comm, err := os.ReadFile(procpkg.ProcPath(e.Name(), "comm"))
 		if err == nil {
 			commLower := strings.ToLower(strings.TrimSpace(string(comm)))
 			var match bool
@@ -68,7 +68,7 @@ func ResolveName(name string, exact bool) ([]int, error) {
 			}
 		}
 
-		cmdline, err := os.ReadFile("/proc/" + e.Name() + "/cmdline")
+		cmdline, err := os.ReadFile(procpkg.ProcPath(e.Name(), "cmdline"))
 		if err == nil {
 			cmd := strings.ReplaceAll(string(cmdline), "\x00", " ")
 			cmdLower := strings.ToLower(cmd)
@@ -9,9 +9,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
 )
 
-func findSocketInodes(port int, listenersOnly bool) (map[string]bool, error) {
+// findSocketInodes finds the sockets on port in the tables of netDir, a
+// /proc/net or /proc/<pid>/net directory.
+func findSocketInodes(netDir string, port int, listenersOnly bool) (map[string]bool, error) {
 	inodes := make(map[string]bool)
 
 	type procNetFile struct {
@@ -19,10 +24,10 @@ func findSocketInodes(port int, listenersOnly bool) (map[string]bool, error) {
 		isTCP bool
 	}
 	files := []procNetFile{
-		{"/proc/net/tcp", true},
-		{"/proc/net/tcp6", true},
-		{"/proc/net/udp", false},
-		{"/proc/net/udp6", false},
+		{filepath.Join(netDir, "tcp"), true},
+		{filepath.Join(netDir, "tcp6"), true},
+		{filepath.Join(netDir, "udp"), false},
+		{filepath.Join(netDir, "udp6"), false},
 	}
 	targetHex := fmt.Sprintf("%04X", port)
 
@@ -86,26 +91,51 @@ func findSocketInodes(port int, listenersOnly bool) (map[string]bool, error) {
 	return inodes, nil
 }
 
+// foreignListenerInodes finds listeners on port in the network namespaces
+// other than witr's own: containers that don't publish the port, or `ip
+// netns` namespaces. Socket inodes are unique across namespaces, so they can
+// be matched against any process's fds.
+func foreignListenerInodes(port int) map[string]bool {
+	inodes := make(map[string]bool)
+	for _, ns := range procpkg.ForeignNetNamespaces() {
+		found, err := findSocketInodes(procpkg.ProcPath(strconv.Itoa(ns.PID), "net"), port, true)
+		if err != nil {
+			continue
+		}
+		for inode := range found {
+			inodes[inode] = true
+		}
+	}
+	return inodes
+}
+
 func ResolvePort(port int) ([]int, error) {
-	inodes, err := findSocketInodes(port, true)
+	ownNet := procpkg.ProcPath("net")
+	inodes, err := findSocketInodes(ownNet, port, true)
 	if err != nil {
-		fallbackInodes, fallbackErr := findSocketInodes(port, false)
-		if fallbackErr != nil {
-			return nil, err
+		// A listener in another namespace explains the port better than a
+		// connection from this one to some remote host's port.
+		if foreign := foreignListenerInodes(port); len(foreign) > 0 {
+			inodes = foreign
+		} else {
+			fallbackInodes, fallbackErr := findSocketInodes(ownNet, port, false)
+			if fallbackErr != nil {
+				return nil, err
+			}
+			inodes = fallbackInodes
 		}
-		inodes = fallbackInodes
 	}
 
 	// collect all owning pids so callers can handle multi-owner sockets.
 	pidSet := make(map[int]bool)
-	procEntries, _ := os.ReadDir("/proc")
+	procEntries, _ := os.ReadDir(procpkg.ProcPath())
 	for _, entry := range procEntries {
 		pid, err := strconv.Atoi(entry.Name())
 		if err != nil {
 			continue
 		}
 
-		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fdDir := procpkg.ProcPath(entry.Name(), "fd")
 		fds, err := os.ReadDir(fdDir)
 		if err != nil {
 			continue
@@ -137,6 +167,16 @@ func ResolvePort(port int) ([]int, error) {
 	sort.Ints(result)
 
 	if len(result) == 0 {
+		// Without root the fds of PID 1 and of other users' services can't
+		// be read, but a listener held by a systemd .socket unit is still
+		// attributable over D-Bus: to the activated service, whose main
+		// process inherited the socket, or else to systemd itself.
+		if act := source.SocketActivationForPort(port); act != nil {
+			if act.ServicePID > 0 {
+				return []int{act.ServicePID}, nil
+			}
+			return []int{1}, nil
+		}
 		return nil, ErrSocketOwnerUnknown
 	}
 
@@ -0,0 +1,85 @@
+//go:build linux
+
+package target
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fakeForeignListenerProcfs builds a procfs where witr shares init's network
+// namespace and a container (pids 300 and 301) has its own, with a listener
+// on 8080 that only the container's tables show.
+func fakeForeignListenerProcfs(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	link := func(rel, target string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	for _, dir := range []string{"net", "300/net"} {
+		for _, table := range []string{"tcp", "tcp6", "udp", "udp6"} {
+			write(filepath.Join(dir, table), header)
+		}
+	}
+	write("300/net/tcp", header+"   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 777 1 0000000000000000 100 0 0 10 0\n")
+	link("self/ns/net", "net:[4026531840]")
+	link("1/ns/net", "net:[4026531840]")
+	link("300/ns/net", "net:[4026532345]")
+	link("301/ns/net", "net:[4026532345]")
+	link("301/fd/3", "socket:[777]")
+	return root
+}
+
+// TestResolvePortInForeignNamespace resolves a port that only a container's
+// network namespace shows to the container process holding the socket.
+func TestResolvePortInForeignNamespace(t *testing.T) {
+	procpkg.SetProcRoot(fakeForeignListenerProcfs(t))
+	t.Cleanup(func() { procpkg.SetProcRoot("") })
+
+	got, err := ResolvePort(8080)
+	if err != nil {
+		t.Fatalf("ResolvePort(8080): %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{301}) {
+		t.Errorf("ResolvePort(8080) = %v, want [301]", got)
+	}
+}
+
+// TestResolvePortSpecInForeignNamespace finds the same listener through the
+// spec forms answered from the full socket listing.
+func TestResolvePortSpecInForeignNamespace(t *testing.T) {
+	procpkg.SetProcRoot(fakeForeignListenerProcfs(t))
+	t.Cleanup(func() { procpkg.SetProcRoot("") })
+
+	for _, val := range []string{"8080-8081", "tcp:8080", "0.0.0.0:8080"} {
+		got, err := Resolve(model.Target{Type: model.TargetPort, Value: val}, false)
+		if err != nil {
+			t.Errorf("Resolve(port %s): %v", val, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, []int{301}) {
+			t.Errorf("Resolve(port %s) = %v, want [301]", val, got)
+		}
+	}
+}
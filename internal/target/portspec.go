@@ -0,0 +1,318 @@
+package target
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// portSpec is a parsed --port value: a port or range of ports, optionally
+// narrowed to a protocol and a local address.
+type portSpec struct {
+	low, high int
+	proto     string // "tcp", "udp", or "" for both
+	addr      net.IP // nil for any address
+}
+
+// single reports whether the spec is a bare port, which the platform
+// resolvers answer directly.
+func (s portSpec) single() bool {
+	return s.low == s.high && s.proto == "" && s.addr == nil
+}
+
+// parsePortSpec parses [proto:][addr:]port, where port is a number, a range
+// ("8000-8100") or an /etc/services name ("https"), proto is tcp or udp, and
+// addr an IPv4 address or a bracketed IPv6 one: "udp:53", "127.0.0.1:8080",
+// "tcp:[::1]:443".
+func parsePortSpec(val string) (portSpec, error) {
+	var s portSpec
+	rest := strings.TrimSpace(val)
+	if proto, after, ok := strings.Cut(rest, ":"); ok {
+		if p := strings.ToLower(proto); p == "tcp" || p == "udp" {
+			s.proto = p
+			rest = after
+		}
+	}
+
+	if inner, ok := strings.CutPrefix(rest, "["); ok {
+		host, after, found := strings.Cut(inner, "]")
+		port, hasPort := strings.CutPrefix(after, ":")
+		if !found || !hasPort {
+			return portSpec{}, fmt.Errorf("invalid port: expected [address]:port")
+		}
+		if s.addr = net.ParseIP(host); s.addr == nil {
+			return portSpec{}, fmt.Errorf("invalid port: %q is not an IP address", host)
+		}
+		rest = port
+	} else if host, port, ok := strings.Cut(rest, ":"); ok {
+		if strings.Contains(port, ":") {
+			return portSpec{}, fmt.Errorf("invalid port: IPv6 addresses must be bracketed, e.g. [::1]:80")
+		}
+		if s.addr = net.ParseIP(host); s.addr == nil {
+			return portSpec{}, fmt.Errorf("invalid port: %q is not an IP address", host)
+		}
+		rest = port
+	}
+
+	if rest == "" {
+		return portSpec{}, fmt.Errorf("invalid port")
+	}
+	if low, high, ok := parsePortRange(rest); ok {
+		if low > high {
+			return portSpec{}, fmt.Errorf("invalid port range %q", rest)
+		}
+		s.low, s.high = low, high
+	} else if port, err := strconv.Atoi(rest); err == nil {
+		s.low, s.high = port, port
+	} else {
+		port, err := lookupService(rest, s.proto)
+		if err != nil {
+			return portSpec{}, err
+		}
+		s.low, s.high = port, port
+	}
+	if s.low < 1 || s.high > 65535 {
+		return portSpec{}, fmt.Errorf("invalid port: must be between 1 and 65535")
+	}
+	return s, nil
+}
+
+// parsePortRange parses "low-high". Anything else with a dash is left to
+// the services database, which has names like "http-alt".
+func parsePortRange(val string) (low, high int, ok bool) {
+	lo, hi, found := strings.Cut(val, "-")
+	if !found {
+		return 0, 0, false
+	}
+	low, errLow := strconv.Atoi(lo)
+	high, errHigh := strconv.Atoi(hi)
+	if errLow != nil || errHigh != nil {
+		return 0, 0, false
+	}
+	return low, high, true
+}
+
+// lookupService finds a service name in the services database, for proto
+// or, when none is given, for tcp and then udp.
+func lookupService(name, proto string) (int, error) {
+	protos := []string{"tcp", "udp"}
+	if proto != "" {
+		protos = []string{proto}
+	}
+	for _, p := range protos {
+		if port, err := net.LookupPort(p, name); err == nil {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid port: unknown service %q", name)
+}
+
+// PortNumber returns the port a --port value names, or 0 for a range or an
+// invalid value.
+func PortNumber(val string) int {
+	s, err := parsePortSpec(val)
+	if err != nil || s.low != s.high {
+		return 0
+	}
+	return s.low
+}
+
+// withPort returns the spec as a --port value for a single port of it,
+// keeping the protocol and address: "udp:[::1]:53".
+func (s portSpec) withPort(port int) string {
+	val := strconv.Itoa(port)
+	if s.addr != nil {
+		val = net.JoinHostPort(s.addr.String(), val)
+	}
+	if s.proto != "" {
+		val = s.proto + ":" + val
+	}
+	return val
+}
+
+// ExpandPortTarget splits a port range ("8000-8100") into one target per
+// port something listens on within it, keeping the protocol and address
+// qualifiers, so each listener is explained on its own rather than
+// reported as an ambiguous match. Any other target, or a range without a
+// listener, is returned as is.
+func ExpandPortTarget(t model.Target) []model.Target {
+	s, err := parsePortSpec(t.Value)
+	if t.Type != model.TargetPort || err != nil || s.low == s.high {
+		return []model.Target{t}
+	}
+	ports, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return []model.Target{t}
+	}
+	listening := make(map[int]bool)
+	for _, op := range ports {
+		if ok, _ := s.matches(op); ok && (op.State == "LISTEN" || strings.HasPrefix(strings.ToUpper(op.Protocol), "UDP")) {
+			listening[op.Port] = true
+		}
+	}
+	if len(listening) == 0 {
+		return []model.Target{t}
+	}
+	nums := make([]int, 0, len(listening))
+	for port := range listening {
+		nums = append(nums, port)
+	}
+	sort.Ints(nums)
+	targets := make([]model.Target, 0, len(nums))
+	for _, port := range nums {
+		targets = append(targets, model.Target{Type: model.TargetPort, Value: s.withPort(port)})
+	}
+	return targets
+}
+
+// DescribePort fills in the socket side of a --port result: the state of
+// the TCP socket on the port, with the systemd socket unit behind it, and
+// for a port PID 1 holds, the unit it activates as the resolved target. A
+// bare port reports the port's most telling socket on the host; a range or
+// a protocol- or address-qualified port reports the socket the resolved
+// process holds within the spec. A udp: spec has no TCP state to report.
+func DescribePort(res *model.Result, val string) {
+	s, err := parsePortSpec(val)
+	if err != nil || s.proto == "udp" {
+		return
+	}
+	var info *model.SocketInfo
+	if s.single() {
+		info = procpkg.GetSocketStateForPort(s.low)
+	} else {
+		info = s.socketHeldBy(res.Process.PID)
+	}
+	if info == nil {
+		return
+	}
+	source.EnrichSocketInfo(info)
+	res.SocketInfo = info
+	// A port owned by PID 1 is socket-activated: name the unit behind it.
+	if res.Process.PID == 1 && info.Activation != nil {
+		res.ResolvedTarget = activationTarget(info.Activation)
+	}
+}
+
+// activationTarget names the unit behind a socket-activated port: the
+// service the socket starts ("nginx"), or for an Accept=yes socket, whose
+// template service ("sshd@.service") no unit or process is named after, the
+// socket unit itself.
+func activationTarget(a *model.SocketActivation) string {
+	if a.Accept {
+		return a.SocketUnit
+	}
+	return strings.TrimSuffix(a.Service, ".service")
+}
+
+// socketHeldBy returns pid's TCP socket that the spec matches, preferring a
+// listener and one bound to the spec's address exactly, or nil.
+func (s portSpec) socketHeldBy(pid int) *model.SocketInfo {
+	ports, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return nil
+	}
+	var best *model.OpenPort
+	bestRank := 0
+	for i, op := range ports {
+		if op.PID != pid || !strings.HasPrefix(strings.ToUpper(op.Protocol), "TCP") {
+			continue
+		}
+		ok, exact := s.matches(op)
+		if !ok {
+			continue
+		}
+		rank := 4
+		if op.State == "LISTEN" {
+			rank -= 2
+		}
+		if exact {
+			rank--
+		}
+		if best == nil || rank < bestRank {
+			best, bestRank = &ports[i], rank
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &model.SocketInfo{Port: best.Port, State: best.State, LocalAddr: best.Address, RemoteAddr: best.RemoteAddress}
+}
+
+// matches reports whether op is a socket the spec describes, and whether it
+// is bound to the spec's address exactly rather than through a wildcard.
+func (s portSpec) matches(op model.OpenPort) (ok, exact bool) {
+	if op.Port < s.low || op.Port > s.high {
+		return false, false
+	}
+	if s.proto != "" && !strings.HasPrefix(strings.ToLower(op.Protocol), s.proto) {
+		return false, false
+	}
+	if s.addr == nil {
+		return true, true
+	}
+	ip := net.ParseIP(strings.Trim(op.Address, "[]"))
+	if ip != nil && ip.Equal(s.addr) {
+		return true, true
+	}
+	// A socket bound to every address also answers on this one, though an
+	// IPv4 wildcard never answers on an IPv6 address.
+	if ip != nil && ip.To4() != nil && s.addr.To4() == nil {
+		return false, false
+	}
+	return ip == nil || ip.IsUnspecified(), false
+}
+
+// resolvePortSpec answers a --port lookup from the host's (or a replayed
+// snapshot's) socket table: listeners first, falling back to any socket
+// bound to or connected on the port, and among those, sockets bound to the
+// requested address before wildcard ones.
+func resolvePortSpec(s portSpec, val string) ([]int, error) {
+	ports, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return nil, fmt.Errorf("list sockets: %w", err)
+	}
+
+	// Tiers in order of preference: exact listeners, wildcard listeners,
+	// exact bound sockets, wildcard bound sockets.
+	var tiers [4]map[int]bool
+	for _, op := range ports {
+		ok, exact := s.matches(op)
+		if !ok {
+			continue
+		}
+		tier := 2
+		if op.State == "LISTEN" || strings.HasPrefix(strings.ToUpper(op.Protocol), "UDP") {
+			tier = 0
+		}
+		if !exact {
+			tier++
+		}
+		if tiers[tier] == nil {
+			tiers[tier] = make(map[int]bool)
+		}
+		tiers[tier][op.PID] = true
+	}
+	for _, pids := range tiers {
+		if len(pids) == 0 {
+			continue
+		}
+		result := make([]int, 0, len(pids))
+		for pid := range pids {
+			// As with a live lookup, systemd holding a socket next to the
+			// service it activated yields to the service.
+			if len(pids) > 1 && pid == 1 {
+				continue
+			}
+			result = append(result, pid)
+		}
+		sort.Ints(result)
+		return result, nil
+	}
+	return nil, fmt.Errorf("no process listening on port %s", strings.TrimSpace(val))
+}
@@ -0,0 +1,236 @@
+package target
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	tests := []struct {
+		val       string
+		low, high int
+		proto     string
+		addr      string
+		wantErr   bool
+	}{
+		{val: "8080", low: 8080, high: 8080},
+		{val: " 8000-8100 ", low: 8000, high: 8100},
+		{val: "https", low: 443, high: 443},
+		{val: "udp:53", low: 53, high: 53, proto: "udp"},
+		{val: "TCP:443", low: 443, high: 443, proto: "tcp"},
+		{val: "127.0.0.1:8080", low: 8080, high: 8080, addr: "127.0.0.1"},
+		{val: "[::1]:80", low: 80, high: 80, addr: "::1"},
+		{val: "tcp:[::1]:80-90", low: 80, high: 90, proto: "tcp", addr: "::1"},
+		{val: "udp:domain", low: 53, high: 53, proto: "udp"},
+		{val: "", wantErr: true},
+		{val: "0", wantErr: true},
+		{val: "70000", wantErr: true},
+		{val: "9000-8000", wantErr: true},
+		{val: "1-70000", wantErr: true},
+		{val: "no-such-service-witr", wantErr: true},
+		{val: "localhost:80", wantErr: true},
+		{val: "::1:80", wantErr: true},
+		{val: "[::1]", wantErr: true},
+		{val: "udp:", wantErr: true},
+	}
+	for _, tt := range tests {
+		s, err := parsePortSpec(tt.val)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parsePortSpec(%q) = %+v, want error", tt.val, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePortSpec(%q): %v", tt.val, err)
+			continue
+		}
+		addr := ""
+		if s.addr != nil {
+			addr = s.addr.String()
+		}
+		if s.low != tt.low || s.high != tt.high || s.proto != tt.proto || addr != tt.addr {
+			t.Errorf("parsePortSpec(%q) = %d-%d %q %q, want %d-%d %q %q", tt.val, s.low, s.high, s.proto, addr, tt.low, tt.high, tt.proto, tt.addr)
+		}
+	}
+}
+
+// TestParsePortSpecHyphenatedService checks that a service name with a
+// dash isn't taken for a range.
+func TestParsePortSpecHyphenatedService(t *testing.T) {
+	want, err := net.LookupPort("tcp", "http-alt")
+	if err != nil {
+		t.Skipf("http-alt isn't in this host's services database: %v", err)
+	}
+	s, err := parsePortSpec("http-alt")
+	if err != nil {
+		t.Fatalf("parsePortSpec(%q): %v", "http-alt", err)
+	}
+	if s.low != want || s.high != want {
+		t.Errorf("parsePortSpec(%q) = %d-%d, want %d", "http-alt", s.low, s.high, want)
+	}
+	if s, err := parsePortSpec("tcp:127.0.0.1:http-alt"); err != nil || s.low != want || s.proto != "tcp" {
+		t.Errorf("parsePortSpec(%q) = %+v, %v, want tcp %d", "tcp:127.0.0.1:http-alt", s, err, want)
+	}
+}
+
+func TestPortNumber(t *testing.T) {
+	for val, want := range map[string]int{
+		"8080":           8080,
+		"udp:53":         53,
+		"127.0.0.1:8080": 8080,
+		"8000-8100":      0,
+		"nonsense!":      0,
+	} {
+		if got := PortNumber(val); got != want {
+			t.Errorf("PortNumber(%q) = %d, want %d", val, got, want)
+		}
+	}
+}
+
+// activatePortFixture replays a snapshot whose socket table has listeners
+// on the same port at different addresses, over TCP and UDP.
+func activatePortFixture(t *testing.T) {
+	t.Helper()
+	archive := &snapshot.Archive{
+		Version: snapshot.FormatVersion,
+		OpenPorts: []model.OpenPort{
+			{PID: 300, Port: 53, Address: "127.0.0.53", Protocol: "UDP", State: "OPEN"},
+			{PID: 400, Port: 53, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+			{PID: 500, Port: 8080, Address: "127.0.0.1", Protocol: "TCP", State: "LISTEN"},
+			{PID: 600, Port: 8080, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+			{PID: 700, Port: 8081, Address: "::", Protocol: "TCP6", State: "LISTEN"},
+			{PID: 800, Port: 9000, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+			{PID: 900, Port: 9000, Address: "10.0.0.2", Protocol: "TCP", State: "ESTABLISHED", RemoteAddress: "10.0.0.9", RemotePort: 51000},
+		},
+	}
+	archive.Activate()
+	t.Cleanup(func() { procpkg.SetReplay(nil) })
+}
+
+func TestResolvePortSpecFromSnapshot(t *testing.T) {
+	activatePortFixture(t)
+
+	tests := []struct {
+		val     string
+		want    []int
+		wantErr bool
+	}{
+		{val: "53", want: []int{300, 400}},
+		{val: "udp:53", want: []int{300}},
+		{val: "tcp:53", want: []int{400}},
+		{val: "127.0.0.53:53", want: []int{300}},
+		{val: "127.0.0.1:8080", want: []int{500}},
+		{val: "10.1.1.1:8080", want: []int{600}},
+		{val: "10.1.1.1:8081", want: []int{700}},
+		{val: "[::1]:8080", wantErr: true},
+		{val: "8000-8999", want: []int{500, 600, 700}},
+		{val: "9000", want: []int{800}},
+		{val: "udp:9000", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(model.Target{Type: model.TargetPort, Value: tt.val}, false)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Resolve(port %s) = %v, want error", tt.val, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(port %s): %v", tt.val, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Resolve(port %s) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPortTarget(t *testing.T) {
+	activatePortFixture(t)
+
+	tests := []struct {
+		val  string
+		want []string
+	}{
+		{"8000-8999", []string{"8080", "8081"}},
+		{"tcp:8000-8999", []string{"tcp:8080", "tcp:8081"}},
+		{"127.0.0.1:8000-8099", []string{"127.0.0.1:8080", "127.0.0.1:8081"}},
+		{"udp:1-100", []string{"udp:53"}},
+		{"9000-9001", []string{"9000"}},
+		{"1-10", []string{"1-10"}},
+		{"8080", []string{"8080"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, target := range ExpandPortTarget(model.Target{Type: model.TargetPort, Value: tt.val}) {
+			got = append(got, target.Value)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandPortTarget(%s) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+
+	name := model.Target{Type: model.TargetName, Value: "8000-8999"}
+	if got := ExpandPortTarget(name); !reflect.DeepEqual(got, []model.Target{name}) {
+		t.Errorf("ExpandPortTarget(name) = %v, want it unchanged", got)
+	}
+}
+
+func TestDescribePort(t *testing.T) {
+	activatePortFixture(t)
+
+	tests := []struct {
+		val       string
+		pid       int
+		wantPort  int
+		wantAddr  string
+		wantState string
+	}{
+		{val: "8080", pid: 500, wantPort: 8080, wantAddr: "127.0.0.1", wantState: "LISTEN"},
+		{val: "10.1.1.1:8080", pid: 600, wantPort: 8080, wantAddr: "0.0.0.0", wantState: "LISTEN"},
+		{val: "8000-8999", pid: 700, wantPort: 8081, wantAddr: "::", wantState: "LISTEN"},
+		{val: "tcp:9000", pid: 900, wantPort: 9000, wantAddr: "10.0.0.2", wantState: "ESTABLISHED"},
+		{val: "udp:53", pid: 300},
+		{val: "tcp:8000-8999", pid: 300},
+	}
+	for _, tt := range tests {
+		res := model.Result{Process: model.Process{PID: tt.pid}}
+		DescribePort(&res, tt.val)
+		si := res.SocketInfo
+		if tt.wantState == "" {
+			if si != nil {
+				t.Errorf("DescribePort(%s) = %+v, want no socket info", tt.val, *si)
+			}
+			continue
+		}
+		if si == nil {
+			t.Errorf("DescribePort(%s) = nil, want %s on %d", tt.val, tt.wantState, tt.wantPort)
+			continue
+		}
+		if si.Port != tt.wantPort || si.LocalAddr != tt.wantAddr || si.State != tt.wantState {
+			t.Errorf("DescribePort(%s) = port %d addr %s %s, want port %d addr %s %s", tt.val, si.Port, si.LocalAddr, si.State, tt.wantPort, tt.wantAddr, tt.wantState)
+		}
+	}
+}
+
+func TestActivationTarget(t *testing.T) {
+	tests := []struct {
+		act  model.SocketActivation
+		want string
+	}{
+		{model.SocketActivation{SocketUnit: "nginx.socket", Service: "nginx.service"}, "nginx"},
+		{model.SocketActivation{SocketUnit: "cups.socket", Service: "cups.service", ServicePID: 812}, "cups"},
+		{model.SocketActivation{SocketUnit: "sshd.socket", Service: "sshd@.service", Accept: true, Instances: []string{"sshd@3-10.0.0.1:22-10.0.0.9:51234.service"}}, "sshd.socket"},
+	}
+	for _, tt := range tests {
+		if got := activationTarget(&tt.act); got != tt.want {
+			t.Errorf("activationTarget(%s) = %q, want %q", tt.act.SocketUnit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package target
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// remoteEndpoint is a parsed --remote value: an address, the addresses a
+// hostname resolves to, or a CIDR network, optionally narrowed to a port.
+type remoteEndpoint struct {
+	ips     []net.IP
+	network *net.IPNet
+	port    int // 0 for any port
+}
+
+// parseRemote parses host[:port], where host is an IPv4 or IPv6 address
+// (bracketed when a port follows), a hostname, or a CIDR network:
+// "10.0.0.5", "db.internal:5432", "[2001:db8::1]:443", "10.0.0.0/8:443".
+func parseRemote(spec string) (remoteEndpoint, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return remoteEndpoint{}, fmt.Errorf("invalid remote: must not be empty")
+	}
+
+	host, portStr := spec, ""
+	if rest, ok := strings.CutPrefix(spec, "["); ok {
+		inner, after, found := strings.Cut(rest, "]")
+		if !found {
+			return remoteEndpoint{}, fmt.Errorf("invalid remote %q: missing ']'", spec)
+		}
+		host = inner
+		if after != "" {
+			p, ok := strings.CutPrefix(after, ":")
+			if !ok {
+				return remoteEndpoint{}, fmt.Errorf("invalid remote %q: expected ':port' after ']'", spec)
+			}
+			portStr = p
+		}
+	} else if strings.Count(spec, ":") == 1 {
+		// More than one colon is a bare IPv6 address.
+		host, portStr, _ = strings.Cut(spec, ":")
+	}
+
+	var e remoteEndpoint
+	if portStr != "" {
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return remoteEndpoint{}, fmt.Errorf("invalid remote %q: port must be between 1 and 65535", spec)
+		}
+		e.port = port
+	}
+
+	switch {
+	case host == "":
+		return remoteEndpoint{}, fmt.Errorf("invalid remote %q: missing host", spec)
+	case strings.Contains(host, "/"):
+		_, network, err := net.ParseCIDR(host)
+		if err != nil {
+			return remoteEndpoint{}, fmt.Errorf("invalid remote %q: bad CIDR", spec)
+		}
+		e.network = network
+	case net.ParseIP(host) != nil:
+		e.ips = []net.IP{net.ParseIP(host)}
+	default:
+		ips, err := net.LookupIP(host)
+		if err != nil || len(ips) == 0 {
+			return remoteEndpoint{}, fmt.Errorf("invalid remote %q: cannot resolve host %s", spec, host)
+		}
+		e.ips = ips
+	}
+	return e, nil
+}
+
+// matches reports whether a socket's far end is the endpoint. Sockets with no
+// far end (listeners, unconnected UDP) never match.
+func (e remoteEndpoint) matches(addr string, port int) bool {
+	if port == 0 || (e.port != 0 && port != e.port) {
+		return false
+	}
+	addr, _, _ = strings.Cut(addr, "%") // drop an IPv6 zone
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	if e.network != nil {
+		// Contains folds IPv4-mapped IPv6 addresses into an IPv4 network.
+		return e.network.Contains(ip)
+	}
+	for _, want := range e.ips {
+		if want.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRemote finds every local process with a connection to the --remote
+// endpoint spec and returns those connections keyed by PID.
+func ResolveRemote(spec string) (map[int][]model.Socket, error) {
+	e, err := parseRemote(spec)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := procpkg.ListOpenPorts()
+	if err != nil {
+		return nil, fmt.Errorf("list sockets: %w", err)
+	}
+
+	replaying := procpkg.ActiveReplay() != nil
+	self := os.Getpid()
+	conns := make(map[int][]model.Socket)
+	for _, op := range ports {
+		if !replaying && op.PID == self {
+			continue
+		}
+		if !e.matches(op.RemoteAddress, op.RemotePort) {
+			continue
+		}
+		conns[op.PID] = append(conns[op.PID], model.Socket{
+			Port:          op.Port,
+			Address:       op.Address,
+			State:         op.State,
+			Protocol:      op.Protocol,
+			RemoteAddress: op.RemoteAddress,
+			RemotePort:    op.RemotePort,
+		})
+	}
+	if len(conns) == 0 {
+		return nil, fmt.Errorf("no process connected to %s", strings.TrimSpace(spec))
+	}
+	for _, socks := range conns {
+		sort.Slice(socks, func(i, j int) bool {
+			if socks[i].RemotePort != socks[j].RemotePort {
+				return socks[i].RemotePort < socks[j].RemotePort
+			}
+			return socks[i].Port < socks[j].Port
+		})
+	}
+	return conns, nil
+}
+
+// remotePIDs returns the PIDs of a ResolveRemote result in order.
+func remotePIDs(conns map[int][]model.Socket) []int {
+	pids := make([]int, 0, len(conns))
+	for pid := range conns {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
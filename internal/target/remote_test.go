@@ -0,0 +1,123 @@
+package target
+
+import (
+	"reflect"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		spec    string
+		port    int
+		network string
+		wantErr bool
+	}{
+		{spec: "10.0.0.5"},
+		{spec: "10.0.0.5:5432", port: 5432},
+		{spec: "2001:db8::1"},
+		{spec: "[2001:db8::1]:443", port: 443},
+		{spec: "10.0.0.0/8", network: "10.0.0.0/8"},
+		{spec: "10.1.2.3/16:443", port: 443, network: "10.1.0.0/16"},
+		{spec: "[2001:db8::/32]:443", port: 443, network: "2001:db8::/32"},
+		{spec: " ", wantErr: true},
+		{spec: "10.0.0.5:0", wantErr: true},
+		{spec: "10.0.0.5:http", wantErr: true},
+		{spec: "10.0.0.0/99", wantErr: true},
+		{spec: "[2001:db8::1", wantErr: true},
+		{spec: "[2001:db8::1]443", wantErr: true},
+		{spec: ":443", wantErr: true},
+	}
+	for _, tt := range tests {
+		e, err := parseRemote(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseRemote(%q) = %+v, want error", tt.spec, e)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRemote(%q): %v", tt.spec, err)
+			continue
+		}
+		if e.port != tt.port {
+			t.Errorf("parseRemote(%q).port = %d, want %d", tt.spec, e.port, tt.port)
+		}
+		if tt.network != "" && (e.network == nil || e.network.String() != tt.network) {
+			t.Errorf("parseRemote(%q).network = %v, want %s", tt.spec, e.network, tt.network)
+		}
+	}
+}
+
+func TestRemoteMatches(t *testing.T) {
+	tests := []struct {
+		spec string
+		addr string
+		port int
+		want bool
+	}{
+		{"10.0.0.5", "10.0.0.5", 5432, true},
+		{"10.0.0.5", "::ffff:10.0.0.5", 5432, true},
+		{"10.0.0.5:5432", "10.0.0.5", 5433, false},
+		{"10.0.0.5", "10.0.0.6", 5432, false},
+		{"10.0.0.5", "10.0.0.5", 0, false},
+		{"10.0.0.0/8", "10.200.1.1", 443, true},
+		{"10.0.0.0/8", "::ffff:10.200.1.1", 443, true},
+		{"10.0.0.0/8", "192.168.1.1", 443, false},
+		{"fe80::/10", "fe80::1%eth0", 22, true},
+		{"10.0.0.5", "", 0, false},
+	}
+	for _, tt := range tests {
+		e, err := parseRemote(tt.spec)
+		if err != nil {
+			t.Fatalf("parseRemote(%q): %v", tt.spec, err)
+		}
+		if got := e.matches(tt.addr, tt.port); got != tt.want {
+			t.Errorf("%s matches(%q, %d) = %v, want %v", tt.spec, tt.addr, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestResolveRemoteFromSnapshot(t *testing.T) {
+	archive := &snapshot.Archive{
+		Version: snapshot.FormatVersion,
+		OpenPorts: []model.OpenPort{
+			{PID: 700, Port: 5432, Address: "0.0.0.0", Protocol: "TCP", State: "LISTEN"},
+			{PID: 900, Port: 51234, Address: "10.0.0.2", Protocol: "TCP", State: "ESTABLISHED", RemoteAddress: "10.0.0.5", RemotePort: 5432},
+			{PID: 900, Port: 51230, Address: "10.0.0.2", Protocol: "TCP", State: "ESTABLISHED", RemoteAddress: "10.0.0.5", RemotePort: 5432},
+			{PID: 950, Port: 40000, Address: "10.0.0.2", Protocol: "TCP", State: "CLOSE_WAIT", RemoteAddress: "10.0.0.5", RemotePort: 6379},
+			{PID: 980, Port: 41000, Address: "10.0.0.2", Protocol: "TCP", State: "ESTABLISHED", RemoteAddress: "10.9.0.1", RemotePort: 443},
+		},
+	}
+	archive.Activate()
+	t.Cleanup(func() { procpkg.SetReplay(nil) })
+
+	conns, err := ResolveRemote("10.0.0.5:5432")
+	if err != nil {
+		t.Fatalf("ResolveRemote: %v", err)
+	}
+	if len(conns) != 1 || len(conns[900]) != 2 || conns[900][0].Port != 51230 {
+		t.Errorf("ResolveRemote(10.0.0.5:5432) = %+v, want pid 900's two connections, lowest port first", conns)
+	}
+
+	for spec, want := range map[string][]int{
+		"10.0.0.5":   {900, 950},
+		"10.0.0.0/8": {900, 950, 980},
+	} {
+		got, err := Resolve(model.Target{Type: model.TargetRemote, Value: spec}, false)
+		if err != nil {
+			t.Errorf("Resolve(%s): %v", spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Resolve(%s) = %v, want %v", spec, got, want)
+		}
+	}
+
+	if _, err := ResolveRemote("192.168.0.0/16"); err == nil || err.Error() != "no process connected to 192.168.0.0/16" {
+		t.Errorf("ResolveRemote(192.168.0.0/16) err = %v", err)
+	}
+}
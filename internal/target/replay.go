@@ -0,0 +1,61 @@
+package target
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// replayResolveName matches a name against the command and command line of
+// every process in a replayed snapshot.
+func replayResolveName(name string, exact bool) ([]int, error) {
+	procs, _ := procpkg.ListProcesses()
+	lowerName := strings.ToLower(name)
+	pids := make(map[int]bool)
+	for _, p := range procs {
+		command := strings.ToLower(p.Command)
+		cmdline := strings.ToLower(p.Cmdline)
+		var match bool
+		if exact {
+			match = command == lowerName || matchesExactToken(cmdline, lowerName)
+		} else {
+			match = strings.Contains(command, lowerName) || strings.Contains(cmdline, lowerName)
+		}
+		if match {
+			pids[p.PID] = true
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no running process or service named %q", name)
+	}
+	return sortedReplayPIDs(pids), nil
+}
+
+// replayResolveFile finds the processes that held path open or locked when
+// the snapshot was captured.
+func replayResolveFile(path string) ([]int, error) {
+	pids := make(map[int]bool)
+	for _, files := range [][]*model.LockedFile{procpkg.ListLockedFiles(), procpkg.ListAllOpenFiles()} {
+		for _, f := range files {
+			if f.Path == path {
+				pids[f.PID] = true
+			}
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no process found holding file: %s", path)
+	}
+	return sortedReplayPIDs(pids), nil
+}
+
+func sortedReplayPIDs(set map[int]bool) []int {
+	pids := make([]int, 0, len(set))
+	for pid := range set {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
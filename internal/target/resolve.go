@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -30,6 +31,7 @@ func matchesExactToken(cmdline, name string) bool {
 
 func Resolve(t model.Target, exact bool) ([]int, error) {
 	val := strings.TrimSpace(t.Value)
+	replaying := procpkg.ActiveReplay() != nil
 
 	switch t.Type {
 	case model.TargetPID:
@@ -43,21 +45,50 @@ func Resolve(t model.Target, exact bool) ([]int, error) {
 		return []int{pid}, nil
 
 	case model.TargetPort:
-		port, err := strconv.Atoi(val)
+		spec, err := parsePortSpec(val)
 		if err != nil {
-			return nil, fmt.Errorf("invalid port")
+			return nil, err
 		}
-		if port < 1 || port > 65535 {
-			return nil, fmt.Errorf("invalid port: must be between 1 and 65535")
+		if replaying || !spec.single() {
+			return resolvePortSpec(spec, val)
 		}
-		return ResolvePort(port)
+		return ResolvePort(spec.low)
 
 	case model.TargetName:
+		if replaying {
+			return replayResolveName(val, exact)
+		}
 		return ResolveName(val, exact)
 
 	case model.TargetFile:
+		if replaying {
+			return replayResolveFile(val)
+		}
 		return ResolveFile(val)
 
+	case model.TargetUnit:
+		units, err := ResolveUnits(val)
+		if err != nil {
+			return nil, err
+		}
+		return unitPIDs(units), nil
+
+	case model.TargetSocket:
+		if val == "" {
+			return nil, fmt.Errorf("invalid socket: must not be empty")
+		}
+		return ResolveSocket(val)
+
+	case model.TargetUser, model.TargetSession, model.TargetTTY:
+		return ResolveLogin(t)
+
+	case model.TargetRemote:
+		conns, err := ResolveRemote(val)
+		if err != nil {
+			return nil, err
+		}
+		return remotePIDs(conns), nil
+
 	default:
 		return nil, fmt.Errorf("unknown target")
 	}
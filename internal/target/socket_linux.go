@@ -0,0 +1,199 @@
+//go:build linux
+
+package target
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// DescribeUnixSocket finds the unix socket bound at path (a filesystem path,
+// or "@name" for an abstract socket), the processes holding it and the
+// processes connected to it. A listening socket's owners are the processes
+// holding the listener; the peers are found through the server side of each
+// accepted connection, which shares the listener's path.
+func DescribeUnixSocket(path string) (*model.UnixSocketInfo, error) {
+	if procpkg.ActiveReplay() != nil {
+		return nil, fmt.Errorf("unix sockets: %w", procpkg.ErrReplayUnavailable)
+	}
+	sockets, err := procpkg.ReadUnixSockets()
+	if err != nil {
+		return nil, fmt.Errorf("read unix sockets: %w", err)
+	}
+
+	bound := make(map[string]model.Socket)
+	for _, s := range sockets {
+		if s.Path != "" && samePath(s.Path, path) {
+			bound[s.Inode] = s
+		}
+	}
+	if len(bound) == 0 {
+		return nil, fmt.Errorf("no process listening on unix socket %s", path)
+	}
+
+	info := &model.UnixSocketInfo{Path: path}
+	owned := make(map[string]bool)
+	for inode, s := range bound {
+		if s.State == "LISTEN" {
+			owned[inode] = true
+			info.State = s.State
+			info.Type = strings.TrimPrefix(s.Protocol, "UNIX ")
+		}
+	}
+	if len(owned) == 0 {
+		// Datagram sockets don't listen: whoever holds the bound socket
+		// owns the path.
+		for inode, s := range bound {
+			owned[inode] = true
+			info.State = s.State
+			info.Type = strings.TrimPrefix(s.Protocol, "UNIX ")
+		}
+	}
+
+	// The far ends: the peer of every path-bound socket (the clients of
+	// accepted connections), and any socket whose peer is bound here (a
+	// datagram client that connected to the path).
+	peerInodes := make(map[string]bool)
+	for _, s := range sockets {
+		if _, ok := bound[s.Inode]; ok {
+			if s.Peer != "" {
+				if _, self := bound[s.Peer]; !self {
+					peerInodes[s.Peer] = true
+				}
+			}
+			continue
+		}
+		if _, ok := bound[s.Peer]; ok {
+			peerInodes[s.Inode] = true
+		}
+	}
+
+	holders := socketHolders(owned, peerInodes)
+	info.Owners = ownerPIDs(holders, owned)
+	if len(info.Owners) == 0 {
+		return nil, fmt.Errorf("%w: unix socket %s", ErrSocketOwnerUnknown, path)
+	}
+	info.Peers = peerProcesses(holders, peerInodes)
+	return info, nil
+}
+
+// ResolveSocket returns the processes holding the unix socket at path.
+func ResolveSocket(path string) ([]int, error) {
+	info, err := DescribeUnixSocket(path)
+	if err != nil {
+		return nil, err
+	}
+	return info.Owners, nil
+}
+
+// samePath reports whether a /proc/net/unix path names the same socket as
+// want. Paths are compared as written first, then by the file they resolve
+// to, so /var/run/docker.sock finds a socket bound as /run/docker.sock.
+func samePath(bound, want string) bool {
+	if bound == want {
+		return true
+	}
+	if strings.HasPrefix(bound, "@") || strings.HasPrefix(want, "@") {
+		return false
+	}
+	a, errA := os.Stat(bound)
+	b, errB := os.Stat(want)
+	return errA == nil && errB == nil && os.SameFile(a, b)
+}
+
+// socketHolders scans every process's fds and maps each inode of interest to
+// the PIDs holding it.
+func socketHolders(sets ...map[string]bool) map[string][]int {
+	holders := make(map[string][]int)
+	entries, _ := os.ReadDir(procpkg.ProcPath())
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := procpkg.ProcPath(entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+			rest, ok := strings.CutPrefix(link, "socket:[")
+			if !ok {
+				continue
+			}
+			inode, ok := strings.CutSuffix(rest, "]")
+			if !ok || seen[inode] {
+				continue
+			}
+			for _, set := range sets {
+				if set[inode] {
+					seen[inode] = true
+					holders[inode] = append(holders[inode], pid)
+					break
+				}
+			}
+		}
+	}
+	return holders
+}
+
+// ownerPIDs collects the holders of the owned inodes. Like a port, a socket
+// held by systemd for activation and by the service it started is
+// attributed to the service.
+func ownerPIDs(holders map[string][]int, owned map[string]bool) []int {
+	set := make(map[int]bool)
+	for inode := range owned {
+		for _, pid := range holders[inode] {
+			set[pid] = true
+		}
+	}
+	pids := make([]int, 0, len(set))
+	for pid := range set {
+		if len(set) > 1 && pid == 1 {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// peerProcesses lists the processes holding the peer inodes.
+func peerProcesses(holders map[string][]int, peerInodes map[string]bool) []model.UnixPeer {
+	set := make(map[int]bool)
+	for inode := range peerInodes {
+		for _, pid := range holders[inode] {
+			set[pid] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	names := make(map[int]string)
+	if procs, err := procpkg.ListProcessSnapshot(); err == nil {
+		for _, p := range procs {
+			if set[p.PID] {
+				names[p.PID] = p.Command
+			}
+		}
+	}
+	peers := make([]model.UnixPeer, 0, len(set))
+	for pid := range set {
+		peers = append(peers, model.UnixPeer{PID: pid, Command: names[pid]})
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].PID < peers[j].PID })
+	return peers
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package target
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDescribeUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+	client, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	info, err := DescribeUnixSocket(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != "stream" || info.State != "LISTEN" {
+		t.Errorf("socket = %s %s, want a listening stream socket", info.Type, info.State)
+	}
+	if len(info.Owners) != 1 || info.Owners[0] != os.Getpid() {
+		t.Errorf("owners = %v, want this test (pid %d)", info.Owners, os.Getpid())
+	}
+	// Peers come from sock_diag, which may be unavailable; when they are
+	// found, the client end is ours too.
+	for _, p := range info.Peers {
+		if p.PID != os.Getpid() {
+			t.Errorf("peer = %+v, want this test (pid %d)", p, os.Getpid())
+		}
+	}
+
+	if _, err := DescribeUnixSocket(filepath.Join(t.TempDir(), "missing.sock")); err == nil {
+		t.Error("DescribeUnixSocket on an unbound path succeeded")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package target
+
+import (
+	"fmt"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// DescribeUnixSocket needs /proc/net/unix and per-process fd links, which
+// only Linux has.
+func DescribeUnixSocket(path string) (*model.UnixSocketInfo, error) {
+	return nil, fmt.Errorf("unix sockets: %w", ErrUnsupported)
+}
+
+func ResolveSocket(path string) ([]int, error) {
+	return nil, fmt.Errorf("unix sockets: %w", ErrUnsupported)
+}
@@ -0,0 +1,180 @@
+package target
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// unitSuffixes are the systemd unit types that own processes.
+var unitSuffixes = []string{".service", ".scope", ".socket", ".mount", ".swap"}
+
+// ResolveUnits finds the systemd units matching pattern, a unit name or a
+// glob such as "nginx*", and every process in each unit's cgroup. A name
+// without a type suffix matches .service units by their short name, like
+// systemctl. Membership comes from the processes' cgroups rather than the
+// unit's MainPID, so workers, helpers and control processes are all found.
+func ResolveUnits(pattern string) ([]model.Unit, error) {
+	if runtime.GOOS != "linux" && procpkg.ActiveReplay() == nil {
+		return nil, fmt.Errorf("systemd units: %w", ErrUnsupported)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid unit pattern %q: %w", pattern, err)
+	}
+
+	procs, err := procpkg.ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+	byPID := make(map[int]model.Process, len(procs))
+	members := make(map[string][]int)
+	for _, p := range procs {
+		byPID[p.PID] = p
+		for _, name := range cgroupUnits(procpkg.ReadCgroup(p.PID)) {
+			if unitMatches(pattern, name) {
+				members[name] = append(members[name], p.PID)
+			}
+		}
+	}
+
+	if len(members) == 0 {
+		if !strings.ContainsAny(pattern, "*?[") {
+			u := model.Unit{Name: unitName(pattern)}
+			source.DescribeUnit(&u)
+			if u.ActiveState != "" {
+				return nil, fmt.Errorf("no running processes in unit %s (%s)", u.Name, u.ActiveState)
+			}
+		}
+		return nil, fmt.Errorf("no running processes in any unit matching %q", pattern)
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	units := make([]model.Unit, 0, len(names))
+	for _, name := range names {
+		u := model.Unit{Name: name}
+		source.DescribeUnit(&u)
+		u.Members = unitMembers(u, members[name], byPID)
+		units = append(units, u)
+	}
+	return units, nil
+}
+
+// unitPIDs flattens the members of units into one PID list, in order.
+func unitPIDs(units []model.Unit) []int {
+	var pids []int
+	seen := make(map[int]bool)
+	for _, u := range units {
+		for _, m := range u.Members {
+			if !seen[m.PID] {
+				seen[m.PID] = true
+				pids = append(pids, m.PID)
+			}
+		}
+	}
+	return pids
+}
+
+// cgroupUnits returns every systemd unit on the process's cgroup path
+// (/proc/<pid>/cgroup content), outermost first. A process in a unit nested
+// under another one (e.g. an app service inside user@1000.service) belongs to
+// both.
+func cgroupUnits(content string) []string {
+	for _, line := range strings.Split(content, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		// cgroup v2 lines have no controllers; on v1 only the name=systemd
+		// hierarchy reflects unit membership.
+		if parts[1] != "" && !strings.Contains(parts[1], "systemd") {
+			continue
+		}
+		var units []string
+		for _, seg := range strings.Split(strings.TrimSpace(parts[2]), "/") {
+			for _, suffix := range unitSuffixes {
+				if strings.HasSuffix(seg, suffix) {
+					units = append(units, seg)
+					break
+				}
+			}
+		}
+		return units
+	}
+	return nil
+}
+
+func unitMatches(pattern, name string) bool {
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	if short, isService := strings.CutSuffix(name, ".service"); isService && !hasUnitSuffix(pattern) {
+		ok, _ := path.Match(pattern, short)
+		return ok
+	}
+	return false
+}
+
+func hasUnitSuffix(name string) bool {
+	for _, suffix := range append(unitSuffixes, ".timer", ".target", ".path", ".slice") {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitName completes a unit name without a type suffix to a .service.
+func unitName(name string) string {
+	if hasUnitSuffix(name) {
+		return name
+	}
+	return name + ".service"
+}
+
+// unitMembers assigns each PID its role in u: main first, then the control
+// process, then helpers by PID. Without systemd's MainPID (no D-Bus, or when
+// replaying a snapshot) the process whose parent is outside the unit is
+// taken as main.
+func unitMembers(u model.Unit, pids []int, byPID map[int]model.Process) []model.UnitMember {
+	sort.Ints(pids)
+	in := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		in[pid] = true
+	}
+
+	mainPID := u.MainPID
+	if !in[mainPID] {
+		mainPID = 0
+		for _, pid := range pids {
+			if pid != u.ControlPID && !in[byPID[pid].PPID] {
+				mainPID = pid
+				break
+			}
+		}
+	}
+
+	var out []model.UnitMember
+	if mainPID > 0 {
+		out = append(out, model.UnitMember{PID: mainPID, Role: model.UnitRoleMain})
+	}
+	if u.ControlPID > 0 && in[u.ControlPID] && u.ControlPID != mainPID {
+		out = append(out, model.UnitMember{PID: u.ControlPID, Role: model.UnitRoleControl})
+	}
+	for _, pid := range pids {
+		if pid != mainPID && pid != u.ControlPID {
+			out = append(out, model.UnitMember{PID: pid, Role: model.UnitRoleHelper})
+		}
+	}
+	return out
+}
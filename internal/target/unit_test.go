@@ -0,0 +1,135 @@
+//go:build linux || darwin || freebsd || windows
+
+package target
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/snapshot"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestCgroupUnits(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"0::/system.slice/nginx.service\n", "nginx.service"},
+		{"0::/system.slice/docker-abc.scope\n", "docker-abc.scope"},
+		{"0::/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service\n", "user@1000.service,syncthing.service"},
+		{"12:cpu:/system.slice/other.service\n1:name=systemd:/system.slice/cron.service\n", "cron.service"},
+		{"0::/\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(cgroupUnits(tt.content), ","); got != tt.want {
+			t.Errorf("cgroupUnits(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestUnitMatches(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"nginx", "nginx.service", true},
+		{"nginx.service", "nginx.service", true},
+		{"nginx", "nginx.socket", false},
+		{"nginx*", "nginx-debug.service", true},
+		{"nginx*", "nginx.socket", true},
+		{"php*-fpm", "php8.2-fpm.service", true},
+		{"*.scope", "session-3.scope", true},
+		{"nginx.socket", "nginx.service", false},
+		{"ngin", "nginx.service", false},
+	}
+	for _, tt := range tests {
+		if got := unitMatches(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("unitMatches(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveUnitsFromSnapshot(t *testing.T) {
+	procs := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 812, PPID: 1, Command: "nginx"},
+		{PID: 813, PPID: 812, Command: "nginx"},
+		{PID: 814, PPID: 812, Command: "nginx"},
+		{PID: 900, PPID: 1, Command: "php-fpm8.2"},
+		{PID: 950, PPID: 1, Command: "bash"},
+	}
+	details := make(map[int]model.Process)
+	for _, p := range procs {
+		details[p.PID] = p
+	}
+	archive := &snapshot.Archive{
+		Version:   snapshot.FormatVersion,
+		Processes: procs,
+		Details:   details,
+		Cgroups: map[int]string{
+			1:   "0::/init.scope",
+			812: "0::/system.slice/nginx.service",
+			813: "0::/system.slice/nginx.service",
+			814: "0::/system.slice/nginx.service",
+			900: "0::/system.slice/php8.2-fpm.service",
+			950: "0::/user.slice/user-1000.slice/session-3.scope",
+		},
+	}
+	archive.Activate()
+	t.Cleanup(func() { procpkg.SetReplay(nil) })
+
+	units, err := ResolveUnits("nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 1 || units[0].Name != "nginx.service" {
+		t.Fatalf("units = %+v, want nginx.service", units)
+	}
+	var members []string
+	for _, m := range units[0].Members {
+		members = append(members, string(m.Role)+":"+strconv.Itoa(m.PID))
+	}
+	if got, want := strings.Join(members, ","), "main:812,helper:813,helper:814"; got != want {
+		t.Errorf("members = %s, want %s", got, want)
+	}
+
+	units, err = ResolveUnits("*.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 2 || units[0].Name != "nginx.service" || units[1].Name != "php8.2-fpm.service" {
+		t.Errorf("glob matched %+v", units)
+	}
+
+	pids, err := Resolve(model.Target{Type: model.TargetUnit, Value: "nginx"}, false)
+	if err != nil || len(pids) != 3 || pids[0] != 812 {
+		t.Errorf("Resolve(unit) = %v, %v; want the main PID first of 3", pids, err)
+	}
+
+	if _, err := ResolveUnits("redis"); err == nil || !strings.Contains(err.Error(), "no running processes") {
+		t.Errorf("missing unit: err = %v", err)
+	}
+	if _, err := ResolveUnits("nginx["); err == nil || !strings.Contains(err.Error(), "invalid unit pattern") {
+		t.Errorf("bad glob: err = %v", err)
+	}
+}
+
+func TestUnitMembersRoles(t *testing.T) {
+	byPID := map[int]model.Process{
+		812: {PID: 812, PPID: 1},
+		813: {PID: 813, PPID: 812},
+		820: {PID: 820, PPID: 1},
+	}
+	u := model.Unit{Name: "nginx.service", MainPID: 812, ControlPID: 820}
+	var got []string
+	for _, m := range unitMembers(u, []int{820, 813, 812}, byPID) {
+		got = append(got, string(m.Role)+":"+strconv.Itoa(m.PID))
+	}
+	if want := "main:812,control:820,helper:813"; strings.Join(got, ",") != want {
+		t.Errorf("members = %s, want %s", strings.Join(got, ","), want)
+	}
+}
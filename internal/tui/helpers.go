@@ -3,6 +3,8 @@ package tui
 import (
 	"regexp"
 	"strings"
+
+	"github.com/pranshuparmar/witr/internal/proc"
 )
 
 var ansiRegex = regexp.MustCompile(`[\x1b\x9b][[\]()#;?]*(?:(?:(?:[a-zA-Z\d]*(?:;[a-zA-Z\d]*)*)?[\x07])|(?:(?:\d{1,4}(?:;\d{0,4})*)?[\dA-PRZcf-ntqry=><~]))`)
@@ -19,3 +21,11 @@ func stripAnsi(str string) string {
 func normalizeRow(s string) string {
 	return strings.Join(strings.Fields(s), " ")
 }
+
+// actionsEnabled reports whether the process action menu (kill, pause, renice)
+// is offered. Besides platform support, actions are disabled while replaying a
+// snapshot: the PIDs on screen belong to another host (or another moment), so
+// signalling them would hit unrelated local processes.
+func actionsEnabled() bool {
+	return actionsSupported && proc.ActiveReplay() == nil
+}
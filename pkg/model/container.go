@@ -20,4 +20,35 @@ type ContainerMatch struct {
 	ComposeService    string `json:",omitempty"`
 	ComposeConfigFile string `json:",omitempty"`
 	ComposeWorkingDir string `json:",omitempty"`
+	// Pod context for containers the kubelet runs.
+	PodName      string `json:",omitempty"`
+	PodNamespace string `json:",omitempty"`
+	PodUID       string `json:",omitempty"`
+	PodQOSClass  string `json:",omitempty"`
+	// PodOwnerKind is the controller the pod belongs to (Deployment,
+	// ReplicaSet, StatefulSet, DaemonSet, Job), or Node for a static pod the
+	// kubelet runs from a manifest.
+	PodOwnerKind string `json:",omitempty"`
+	PodOwnerName string `json:",omitempty"`
+}
+
+// Workload names what a Kubernetes container runs for: the pod's owner
+// ("Deployment shop/web"), else the pod itself ("pod shop/web-0"). Returns ""
+// when the pod isn't known.
+func (m *ContainerMatch) Workload() string {
+	qualify := func(name string) string {
+		if m.PodNamespace != "" {
+			return m.PodNamespace + "/" + name
+		}
+		return name
+	}
+	switch {
+	case m.PodName == "":
+		return ""
+	case m.PodOwnerKind == "Node":
+		return "static pod " + qualify(m.PodName)
+	case m.PodOwnerKind != "" && m.PodOwnerName != "":
+		return m.PodOwnerKind + " " + qualify(m.PodOwnerName)
+	}
+	return "pod " + qualify(m.PodName)
 }
@@ -0,0 +1,33 @@
+package model
+
+// Severity ranks a Finding, lowest first: info, low, medium, high, critical.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+var severityRank = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// Rank orders severities for comparison; it is 0 for an unknown severity.
+func (s Severity) Rank() int {
+	return severityRank[s]
+}
+
+// Finding is a warning raised by a policy rule. RuleID is stable across
+// releases so CI gates and allow-lists can key on it; Message is for humans.
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	Message  string
+}
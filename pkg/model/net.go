@@ -6,4 +6,12 @@ type OpenPort struct {
 	Address  string
 	Protocol string
 	State    string
+
+	// RemoteAddress and RemotePort are the far end of a connected socket.
+	RemoteAddress string `json:",omitempty"`
+	RemotePort    int    `json:",omitempty"`
+
+	// NetNamespace is the inode of the network namespace the socket lives
+	// in, when it isn't witr's own (e.g. an unpublished container port).
+	NetNamespace string `json:",omitempty"`
 }
@@ -14,6 +14,19 @@ type Process struct {
 	MemoryRSS     uint64 // In bytes
 	MemoryPercent float64
 
+	// Login context from the process list: the controlling terminal
+	// ("pts/3", "ttys001") and the login session (a logind session on
+	// Linux, a Terminal Services session on Windows).
+	TTY     string `json:",omitempty"`
+	Session string `json:",omitempty"`
+
+	// Process group and session IDs, and whether the process ignores SIGHUP
+	// (as nohup leaves it): what tells a job detached from its terminal
+	// apart from a service. Linux only.
+	PGID       int  `json:",omitempty"`
+	SID        int  `json:",omitempty"`
+	IgnoresHUP bool `json:",omitempty"`
+
 	WorkingDir string
 	GitRepo    string
 	GitBranch  string
@@ -10,9 +10,20 @@ type Result struct {
 	Source         Source
 	Warnings       []string
 
+	// Findings are Warnings with the rule ID and severity that raised each
+	// one, in the same order.
+	Findings []Finding `json:",omitempty"`
+
 	// SocketInfo holds socket state details (for port queries)
 	SocketInfo *SocketInfo
 
+	// UnixSocket holds the socket's holders and peers (for --socket queries)
+	UnixSocket *UnixSocketInfo `json:",omitempty"`
+
+	// Connections are the process's connections to the endpoint (for
+	// --remote queries)
+	Connections []Socket `json:",omitempty"`
+
 	// ResourceContext holds resource usage context (macOS)
 	ResourceContext *ResourceContext
 
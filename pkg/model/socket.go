@@ -6,6 +6,41 @@ type Socket struct {
 	Address  string // 0.0.0.0, 127.0.0.1, ::
 	State    string
 	Protocol string
+
+	// RemoteAddress and RemotePort are the far end of a connected socket.
+	RemoteAddress string `json:",omitempty"`
+	RemotePort    int    `json:",omitempty"`
+
+	// Path is a unix socket's bound path ("@name" for an abstract socket).
+	Path string `json:",omitempty"`
+	// Peer is the inode of the socket at the other end of a connection, for
+	// unix sockets and TCP connections between two local processes.
+	Peer string `json:",omitempty"`
+
+	// PeerPID and PeerCommand name the local process on the other end of an
+	// established connection, and PeerSource what started it, e.g.
+	// "systemd postgresql.service".
+	PeerPID     int    `json:",omitempty"`
+	PeerCommand string `json:",omitempty"`
+	PeerSource  string `json:",omitempty"`
+}
+
+// UnixSocketInfo describes the unix socket of a --socket lookup: who holds
+// it and who is connected to it.
+type UnixSocketInfo struct {
+	Path  string
+	Type  string // stream, dgram or seqpacket
+	State string // LISTEN for a listening socket, else the bound socket's state
+	// Owners are the processes holding the bound socket.
+	Owners []int
+	// Peers are the processes on the other end of its connections.
+	Peers []UnixPeer `json:",omitempty"`
+}
+
+// UnixPeer is a process connected to a unix socket.
+type UnixPeer struct {
+	PID     int
+	Command string
 }
 
 // SocketInfo holds information about a socket's state
@@ -16,4 +51,49 @@ type SocketInfo struct {
 	RemoteAddr  string
 	Explanation string // Human-readable explanation of the state
 	Workaround  string // Suggested workaround if applicable
+
+	// Activation is set when a systemd .socket unit holds the listener and
+	// starts its service on demand.
+	Activation *SocketActivation `json:",omitempty"`
+
+	// Namespace is set when the port was found in a network namespace other
+	// than witr's own, e.g. an unpublished container port.
+	Namespace *NetNamespace `json:",omitempty"`
+}
+
+// NetNamespace is a network namespace, identified by its inode number.
+type NetNamespace struct {
+	Inode string
+	// PID is the process whose /proc/<pid>/net tables describe the namespace.
+	PID int
+	// Name is the `ip netns` name bound to the namespace under /run/netns.
+	Name      string `json:",omitempty"`
+	Container string `json:",omitempty"`
+}
+
+// SocketActivation describes the systemd .socket unit listening on a port on
+// behalf of a service.
+type SocketActivation struct {
+	SocketUnit string
+	// Listen are the unit's listening addresses, e.g. "[::]:80".
+	Listen []string
+	// Service is the unit the socket starts; with Accept=yes it is the
+	// template (sshd@.service) instantiated once per connection.
+	Service      string
+	ServiceState string `json:",omitempty"`
+	// ServicePID is the activated service's main process, 0 while the
+	// service isn't running or with Accept=yes.
+	ServicePID int  `json:",omitempty"`
+	Accept     bool `json:",omitempty"`
+	// Instances are the running per-connection service units (Accept=yes).
+	Instances   []string `json:",omitempty"`
+	Connections int      `json:",omitempty"`
+}
+
+// Activated reports whether the socket's service is currently running.
+func (a *SocketActivation) Activated() bool {
+	if a.Accept {
+		return len(a.Instances) > 0
+	}
+	return a.ServiceState == "active" || a.ServiceState == "reloading"
 }
@@ -8,6 +8,12 @@ const (
 	TargetPort      TargetType = "port"
 	TargetFile      TargetType = "file"
 	TargetContainer TargetType = "container"
+	TargetUnit      TargetType = "unit"
+	TargetUser      TargetType = "user"
+	TargetSession   TargetType = "session"
+	TargetTTY       TargetType = "tty"
+	TargetSocket    TargetType = "socket"
+	TargetRemote    TargetType = "remote"
 )
 
 type Target struct {
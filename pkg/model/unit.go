@@ -0,0 +1,44 @@
+package model
+
+// UnitRole is what a process does for the systemd unit whose cgroup it is in.
+type UnitRole string
+
+const (
+	// UnitRoleMain is the unit's main process (MainPID).
+	UnitRoleMain UnitRole = "main"
+	// UnitRoleControl runs an ExecStartPre/ExecReload/ExecStop command
+	// (ControlPID).
+	UnitRoleControl UnitRole = "control"
+	// UnitRoleHelper is any other process in the unit: workers and helpers
+	// the main process forked.
+	UnitRoleHelper UnitRole = "helper"
+)
+
+// Unit is a systemd unit and the processes in its cgroup.
+type Unit struct {
+	Name        string
+	Description string `json:",omitempty"`
+	UnitFile    string `json:",omitempty"`
+	ActiveState string `json:",omitempty"`
+	SubState    string `json:",omitempty"`
+	// WantedBy and RequiredBy are the units that pull this one in, e.g.
+	// multi-user.target.
+	WantedBy   []string `json:",omitempty"`
+	RequiredBy []string `json:",omitempty"`
+	// TriggeredBy are the timer, socket and path units that start this one.
+	TriggeredBy []string `json:",omitempty"`
+	// Activation is the chain of units that pulled this one in, root first,
+	// e.g. [sockets.target nginx.socket nginx.service].
+	Activation []string `json:",omitempty"`
+	// Schedule describes the triggering timer, when there is one.
+	Schedule   string `json:",omitempty"`
+	MainPID    int    `json:",omitempty"`
+	ControlPID int    `json:",omitempty"`
+	Members    []UnitMember
+}
+
+// UnitMember is one process in a unit's cgroup.
+type UnitMember struct {
+	PID  int
+	Role UnitRole
+}
@@ -0,0 +1,259 @@
+// Package witr is the Go API to witr's causality engine: resolve a PID, port,
+// name, file or container to the process behind it and explain why it is
+// running, without exec'ing the witr binary.
+//
+//	c := witr.New(witr.Options{})
+//	res, err := c.ExplainPort(ctx, 8080)
+//	fmt.Println(res.Source.Name) // e.g. "nginx.service"
+//
+// Results are the same pkg/model types the CLI's --json output encodes. The
+// engine reads process-wide host state (procfs, the service manager,
+// container runtimes), so a Client is safe for concurrent use but all Clients
+// in a process see the same host.
+package witr
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/internal/policy"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Options tune every lookup a Client makes. They mirror the CLI flags of the
+// same names.
+type Options struct {
+	// Verbose adds extended process information (memory, I/O, file
+	// descriptors, resource and file context), the process's children and,
+	// for connections between two local processes, the one at the other end.
+	Verbose bool
+	// Tree adds the process's children, for rendering a process tree.
+	Tree bool
+	// Exact matches names and container queries exactly instead of by
+	// substring.
+	Exact bool
+}
+
+// Errors returned by the Explain methods, matchable with errors.Is / errors.As.
+var (
+	// ErrSocketOwnerUnknown means a socket is bound to the port but no
+	// host-visible process owns it (socket activation, a container runtime,
+	// or insufficient permissions).
+	ErrSocketOwnerUnknown = target.ErrSocketOwnerUnknown
+
+	// ErrUnsupported means the lookup isn't available on this platform.
+	ErrUnsupported = target.ErrUnsupported
+)
+
+// MultipleMatchesError is returned when a port, name or file resolves to more
+// than one process. Pick one of PIDs and call ExplainPID.
+type MultipleMatchesError struct {
+	Target model.Target
+	PIDs   []int
+}
+
+func (e *MultipleMatchesError) Error() string {
+	return fmt.Sprintf("%s %q matches %d processes", e.Target.Type, e.Target.Value, len(e.PIDs))
+}
+
+// ContainerResult is what ExplainContainer found: the runtime's view of the
+// container, plus the full analysis of its main process when that process is
+// visible on the host.
+type ContainerResult struct {
+	Container *model.ContainerMatch
+	Result    *model.Result `json:",omitempty"`
+}
+
+// Client runs witr lookups against the local host.
+type Client struct {
+	opts Options
+}
+
+// New returns a Client that applies opts to every lookup.
+func New(opts Options) *Client {
+	return &Client{opts: opts}
+}
+
+// ExplainPID explains why the process pid is running.
+func (c *Client) ExplainPID(ctx context.Context, pid int) (model.Result, error) {
+	return c.Explain(ctx, model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)})
+}
+
+// ExplainPort explains the process listening on (or otherwise bound to) port.
+// The result carries the port's socket state in SocketInfo.
+func (c *Client) ExplainPort(ctx context.Context, port int) (model.Result, error) {
+	return c.Explain(ctx, model.Target{Type: model.TargetPort, Value: strconv.Itoa(port)})
+}
+
+// ExplainName explains the process or service called name.
+func (c *Client) ExplainName(ctx context.Context, name string) (model.Result, error) {
+	return c.Explain(ctx, model.Target{Type: model.TargetName, Value: name})
+}
+
+// ExplainFile explains the process holding path open or locked.
+func (c *Client) ExplainFile(ctx context.Context, path string) (model.Result, error) {
+	return c.Explain(ctx, model.Target{Type: model.TargetFile, Value: path})
+}
+
+// Explain resolves any non-container target to a single process and
+// explains it. A target matching several processes returns a
+// *MultipleMatchesError; for a port range with several listeners, its PIDs
+// are the listeners', each of which can be explained by PID or by port.
+func (c *Client) Explain(ctx context.Context, t model.Target) (model.Result, error) {
+	if t.Type == model.TargetContainer {
+		return model.Result{}, fmt.Errorf("invalid target: use ExplainContainer for container lookups")
+	}
+	return run(ctx, func() (model.Result, error) { return c.explain(t) })
+}
+
+func (c *Client) explain(t model.Target) (model.Result, error) {
+	pids, err := target.Resolve(t, c.opts.Exact)
+	if err != nil {
+		return model.Result{}, err
+	}
+	switch {
+	case len(pids) == 0:
+		return model.Result{}, fmt.Errorf("no matching process found")
+	case len(pids) > 1:
+		return model.Result{}, &MultipleMatchesError{Target: t, PIDs: pids}
+	}
+	pid := pids[0]
+
+	res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+		PID:     pid,
+		Verbose: c.opts.Verbose,
+		Peers:   c.opts.Verbose,
+		Tree:    c.opts.Tree,
+		Target:  t,
+	})
+	if err != nil {
+		return model.Result{}, err
+	}
+
+	if t.Type == model.TargetPort {
+		target.DescribePort(&res, t.Value)
+	}
+	return res, nil
+}
+
+// ExplainContainer resolves query against every available container runtime
+// (by name, ID, image, command or compose labels) and explains the single
+// container it matches.
+func (c *Client) ExplainContainer(ctx context.Context, query string) (ContainerResult, error) {
+	return run(ctx, func() (ContainerResult, error) {
+		matches := procpkg.ResolveContainer(query, c.opts.Exact)
+		switch {
+		case len(matches) == 0:
+			return ContainerResult{}, fmt.Errorf("no container found matching %q", query)
+		case len(matches) > 1:
+			return ContainerResult{}, fmt.Errorf("multiple containers matched %q (%d results)", query, len(matches))
+		}
+
+		match := matches[0]
+		procpkg.EnrichContainer(match)
+		out := ContainerResult{Container: match}
+		pid := procpkg.ResolveContainerHostPID(match.Runtime, match.ID)
+		if pid <= 0 || !procpkg.PIDBelongsToContainer(pid, match.ID) {
+			return out, nil
+		}
+		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
+			PID:     pid,
+			Verbose: c.opts.Verbose,
+			Peers:   c.opts.Verbose,
+			Tree:    c.opts.Tree,
+			Target:  model.Target{Type: model.TargetContainer, Value: query},
+		})
+		if err != nil {
+			// The process exited between the runtime lookup and the analysis;
+			// the runtime's view still answers the question.
+			return out, nil
+		}
+		res.Process.Container = output.FormatContainerLine(match)
+		if len(res.Ancestry) > 0 {
+			res.Ancestry[len(res.Ancestry)-1].Container = res.Process.Container
+		}
+		out.Result = &res
+		return out, nil
+	})
+}
+
+// ListProcesses returns every process on the host.
+func (c *Client) ListProcesses(ctx context.Context) ([]model.Process, error) {
+	return run(ctx, procpkg.ListProcesses)
+}
+
+// ListPorts returns every socket on the host with its owning PID.
+func (c *Client) ListPorts(ctx context.Context) ([]model.OpenPort, error) {
+	return run(ctx, procpkg.ListOpenPorts)
+}
+
+// ListContainers returns the containers of every available runtime.
+func (c *Client) ListContainers(ctx context.Context) ([]*model.ContainerMatch, error) {
+	return run(ctx, func() ([]*model.ContainerMatch, error) {
+		return procpkg.ListAllContainers(), nil
+	})
+}
+
+// ListLocks returns every file lock held on the host.
+func (c *Client) ListLocks(ctx context.Context) ([]*model.LockedFile, error) {
+	return run(ctx, func() ([]*model.LockedFile, error) {
+		return procpkg.ListLockedFiles(), nil
+	})
+}
+
+// LoadSourcesConfig installs the custom source detectors declared in the
+// given sources.yaml files (see DefaultSourcesPaths), as the CLI does at
+// startup. Like the engine's other state it is process-wide.
+func LoadSourcesConfig(paths ...string) error {
+	return source.LoadSourcesConfig(paths...)
+}
+
+// DefaultSourcesPaths returns the sources.yaml files the CLI reads.
+func DefaultSourcesPaths() []string {
+	return source.DefaultSourcesPaths()
+}
+
+// LoadPolicy installs the warning policy declared in the given policy files
+// (see DefaultPolicyPaths), which decides the Findings and Warnings of every
+// result. Like LoadSourcesConfig it is process-wide.
+func LoadPolicy(paths ...string) error {
+	return policy.Load(paths...)
+}
+
+// DefaultPolicyPaths returns the policy files the CLI reads.
+func DefaultPolicyPaths() []string {
+	return policy.DefaultPaths()
+}
+
+// run calls fn and waits for it or ctx, whichever finishes first. The host
+// readers aren't interruptible, so a cancelled lookup finishes in the
+// background and its result is dropped.
+func run[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
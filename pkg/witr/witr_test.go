@@ -0,0 +1,64 @@
+package witr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExplainPIDSelf(t *testing.T) {
+	pid := os.Getpid()
+	res, err := New(Options{Tree: true}).ExplainPID(context.Background(), pid)
+	if err != nil {
+		t.Fatalf("ExplainPID(self): %v", err)
+	}
+	if res.Process.PID != pid || len(res.Ancestry) == 0 || res.Ancestry[len(res.Ancestry)-1].PID != pid {
+		t.Errorf("result = %+v, want self at the end of the ancestry", res.Process)
+	}
+	if res.Source.Type == "" {
+		t.Error("result has no source")
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	c := New(Options{})
+	if _, err := c.ExplainPID(context.Background(), -1); err == nil {
+		t.Error("negative pid should fail")
+	}
+	if _, err := c.Explain(context.Background(), model.Target{Type: model.TargetContainer, Value: "x"}); err == nil {
+		t.Error("Explain must point container lookups at ExplainContainer")
+	}
+}
+
+// A cancelled context wins even though the host readers can't be interrupted.
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(Options{}).ListProcesses(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := run(ctx, func() (int, error) { <-block; return 1, nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMultipleMatchesError(t *testing.T) {
+	var err error = &MultipleMatchesError{Target: model.Target{Type: model.TargetName, Value: "nginx"}, PIDs: []int{10, 11}}
+	var multi *MultipleMatchesError
+	if !errors.As(err, &multi) || len(multi.PIDs) != 2 {
+		t.Fatal("errors.As should recover the candidate PIDs")
+	}
+	if got, want := err.Error(), `name "nginx" matches 2 processes`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}